@@ -0,0 +1,594 @@
+// Package classfile reads Java class files, as described by chapter 4 of the
+// Java Virtual Machine Specification, far enough to list the classes they
+// refer to.
+//
+// Class names are binary names, like java.util.Map$Entry, rather than the
+// internal names of the class file format, like java/util/Map$Entry.
+package classfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// Magic is the first four bytes of every class file.
+const Magic = 0xCAFEBABE
+
+// ConstantTag is the kind of a constant pool entry.
+type ConstantTag uint8
+
+const (
+	TagUtf8               ConstantTag = 1
+	TagInteger            ConstantTag = 3
+	TagFloat              ConstantTag = 4
+	TagLong               ConstantTag = 5
+	TagDouble             ConstantTag = 6
+	TagClass              ConstantTag = 7
+	TagString             ConstantTag = 8
+	TagFieldref           ConstantTag = 9
+	TagMethodref          ConstantTag = 10
+	TagInterfaceMethodref ConstantTag = 11
+	TagNameAndType        ConstantTag = 12
+	TagMethodHandle       ConstantTag = 15
+	TagMethodType         ConstantTag = 16
+	TagDynamic            ConstantTag = 17
+	TagInvokeDynamic      ConstantTag = 18
+	TagModule             ConstantTag = 19
+	TagPackage            ConstantTag = 20
+)
+
+// Constant is an entry of a constant pool. The entries following a Long or
+// a Double are unusable and have a zero Tag.
+type Constant struct {
+	Tag ConstantTag
+	// Utf8 is the text of a TagUtf8 entry.
+	Utf8 string
+	// Value is the bits of a TagInteger, TagFloat, TagLong or TagDouble
+	// entry.
+	Value uint64
+	// Index and Index2 are the indexes of the entries this one refers to,
+	// like the name and descriptor of a TagNameAndType entry, or the kind
+	// and reference of a TagMethodHandle one.
+	Index  uint16
+	Index2 uint16
+}
+
+// Access flags of classes and members.
+const (
+	AccPublic     = 0x0001
+	AccPrivate    = 0x0002
+	AccProtected  = 0x0004
+	AccStatic     = 0x0008
+	AccFinal      = 0x0010
+	AccInterface  = 0x0200
+	AccAbstract   = 0x0400
+	AccSynthetic  = 0x1000
+	AccAnnotation = 0x2000
+	AccEnum       = 0x4000
+	AccModule     = 0x8000
+)
+
+// Attribute is an attribute of a class or member, undecoded.
+type Attribute struct {
+	Name string
+	Info []byte
+}
+
+// Member is a field or method.
+type Member struct {
+	AccessFlags uint16
+	Name        string
+	// Descriptor is the type of a field, like Ljava/lang/String;, or the
+	// parameters and return type of a method, like (I)V.
+	Descriptor string
+	Attributes []Attribute
+}
+
+// ClassFile is a decoded class file.
+type ClassFile struct {
+	MinorVersion uint16
+	// MajorVersion is 52 for Java 8, 61 for Java 17.
+	MajorVersion uint16
+	// ConstantPool is indexed like in the class file: its first entry is
+	// unused.
+	ConstantPool []Constant
+	AccessFlags  uint16
+	// ThisClass and SuperClass are binary names. SuperClass is empty for
+	// java.lang.Object and modules.
+	ThisClass  string
+	SuperClass string
+	Interfaces []string
+	Fields     []Member
+	Methods    []Member
+	Attributes []Attribute
+}
+
+// FormatError is returned for data that is not a valid class file.
+type FormatError struct {
+	Msg string
+}
+
+func (e *FormatError) Error() string {
+	return "classfile: " + e.Msg
+}
+
+func formatError(format string, args ...any) error {
+	return &FormatError{Msg: fmt.Sprintf(format, args...)}
+}
+
+// Read decodes the class file read from r.
+func Read(r io.Reader) (*ClassFile, error) {
+	d := &decoder{r: bufio.NewReader(r)}
+	c, err := d.classFile()
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, formatError("unexpected end of file")
+	}
+	return c, err
+}
+
+// ReadFile decodes the class file at path.
+func ReadFile(path string) (*ClassFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	c, err := Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+type decoder struct {
+	r    *bufio.Reader
+	pool []Constant
+}
+
+func (d *decoder) u1() (uint8, error) {
+	return d.r.ReadByte()
+}
+
+func (d *decoder) u2() (uint16, error) {
+	var b [2]byte
+	_, err := io.ReadFull(d.r, b[:])
+	return binary.BigEndian.Uint16(b[:]), err
+}
+
+func (d *decoder) u4() (uint32, error) {
+	var b [4]byte
+	_, err := io.ReadFull(d.r, b[:])
+	return binary.BigEndian.Uint32(b[:]), err
+}
+
+// bytes reads n bytes. The buffer grows as they are read rather than being
+// allocated from n, which a corrupt class file may give as up to 2 GiB.
+func (d *decoder) bytes(n int) ([]byte, error) {
+	var b bytes.Buffer
+	if _, err := io.CopyN(&b, d.r, int64(n)); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func (d *decoder) classFile() (*ClassFile, error) {
+	magic, err := d.u4()
+	if err != nil {
+		return nil, err
+	}
+	if magic != Magic {
+		return nil, formatError("bad magic number %#x", magic)
+	}
+
+	c := &ClassFile{}
+	if c.MinorVersion, err = d.u2(); err != nil {
+		return nil, err
+	}
+	if c.MajorVersion, err = d.u2(); err != nil {
+		return nil, err
+	}
+	if err := d.constantPool(); err != nil {
+		return nil, err
+	}
+	c.ConstantPool = d.pool
+
+	if c.AccessFlags, err = d.u2(); err != nil {
+		return nil, err
+	}
+	if c.ThisClass, err = d.classRef(false); err != nil {
+		return nil, err
+	}
+	if c.SuperClass, err = d.classRef(true); err != nil {
+		return nil, err
+	}
+	n, err := d.u2()
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < int(n); i++ {
+		name, err := d.classRef(false)
+		if err != nil {
+			return nil, err
+		}
+		c.Interfaces = append(c.Interfaces, name)
+	}
+
+	if c.Fields, err = d.members(); err != nil {
+		return nil, err
+	}
+	if c.Methods, err = d.members(); err != nil {
+		return nil, err
+	}
+	if c.Attributes, err = d.attributes(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (d *decoder) constantPool() error {
+	n, err := d.u2()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return formatError("empty constant pool")
+	}
+
+	d.pool = make([]Constant, n)
+	for i := 1; i < int(n); i++ {
+		tag, err := d.u1()
+		if err != nil {
+			return err
+		}
+		c := Constant{Tag: ConstantTag(tag)}
+		switch c.Tag {
+		case TagUtf8:
+			length, err := d.u2()
+			if err != nil {
+				return err
+			}
+			b, err := d.bytes(int(length))
+			if err != nil {
+				return err
+			}
+			if c.Utf8, err = decodeModifiedUTF8(b); err != nil {
+				return err
+			}
+		case TagInteger, TagFloat:
+			v, err := d.u4()
+			if err != nil {
+				return err
+			}
+			c.Value = uint64(v)
+		case TagLong, TagDouble:
+			high, err := d.u4()
+			if err != nil {
+				return err
+			}
+			low, err := d.u4()
+			if err != nil {
+				return err
+			}
+			c.Value = uint64(high)<<32 | uint64(low)
+		case TagClass, TagString, TagMethodType, TagModule, TagPackage:
+			if c.Index, err = d.u2(); err != nil {
+				return err
+			}
+		case TagFieldref, TagMethodref, TagInterfaceMethodref, TagNameAndType, TagDynamic, TagInvokeDynamic:
+			if c.Index, err = d.u2(); err != nil {
+				return err
+			}
+			if c.Index2, err = d.u2(); err != nil {
+				return err
+			}
+		case TagMethodHandle:
+			kind, err := d.u1()
+			if err != nil {
+				return err
+			}
+			c.Index = uint16(kind)
+			if c.Index2, err = d.u2(); err != nil {
+				return err
+			}
+		default:
+			return formatError("unknown constant pool tag %d at index %d", tag, i)
+		}
+
+		d.pool[i] = c
+		if c.Tag == TagLong || c.Tag == TagDouble {
+			i++
+		}
+	}
+	return nil
+}
+
+// utf8 returns the text of the Utf8 constant at index.
+func (d *decoder) utf8(index uint16) (string, error) {
+	return utf8At(d.pool, index)
+}
+
+func utf8At(pool []Constant, index uint16) (string, error) {
+	if int(index) >= len(pool) || pool[index].Tag != TagUtf8 {
+		return "", formatError("constant %d is not a Utf8 entry", index)
+	}
+	return pool[index].Utf8, nil
+}
+
+// classRef reads the index of a Class constant and returns its binary name.
+func (d *decoder) classRef(optional bool) (string, error) {
+	index, err := d.u2()
+	if err != nil {
+		return "", err
+	}
+	if index == 0 && optional {
+		return "", nil
+	}
+	if int(index) >= len(d.pool) || d.pool[index].Tag != TagClass {
+		return "", formatError("constant %d is not a Class entry", index)
+	}
+	name, err := d.utf8(d.pool[index].Index)
+	return BinaryName(name), err
+}
+
+func (d *decoder) members() ([]Member, error) {
+	n, err := d.u2()
+	if err != nil {
+		return nil, err
+	}
+	members := make([]Member, n)
+	for i := range members {
+		m := &members[i]
+		if m.AccessFlags, err = d.u2(); err != nil {
+			return nil, err
+		}
+		for _, s := range []*string{&m.Name, &m.Descriptor} {
+			index, err := d.u2()
+			if err != nil {
+				return nil, err
+			}
+			if *s, err = d.utf8(index); err != nil {
+				return nil, err
+			}
+		}
+		if m.Attributes, err = d.attributes(); err != nil {
+			return nil, err
+		}
+	}
+	return members, nil
+}
+
+func (d *decoder) attributes() ([]Attribute, error) {
+	n, err := d.u2()
+	if err != nil {
+		return nil, err
+	}
+	attributes := make([]Attribute, n)
+	for i := range attributes {
+		index, err := d.u2()
+		if err != nil {
+			return nil, err
+		}
+		if attributes[i].Name, err = d.utf8(index); err != nil {
+			return nil, err
+		}
+		length, err := d.u4()
+		if err != nil {
+			return nil, err
+		}
+		if length > math.MaxInt32 {
+			return nil, formatError("attribute %s is too long", attributes[i].Name)
+		}
+		if attributes[i].Info, err = d.bytes(int(length)); err != nil {
+			return nil, err
+		}
+	}
+	return attributes, nil
+}
+
+// decodeModifiedUTF8 decodes the modified UTF-8 of class files, which
+// encodes NUL in two bytes and supplementary characters as surrogate pairs.
+func decodeModifiedUTF8(b []byte) (string, error) {
+	ascii := true
+	for _, c := range b {
+		if c == 0 || c >= 0x80 {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return string(b), nil
+	}
+
+	units := make([]uint16, 0, len(b))
+	for i := 0; i < len(b); {
+		c := b[i]
+		switch {
+		case c&0x80 == 0 && c != 0:
+			units = append(units, uint16(c))
+			i++
+		case c&0xE0 == 0xC0 && i+1 < len(b):
+			units = append(units, uint16(c&0x1F)<<6|uint16(b[i+1]&0x3F))
+			i += 2
+		case c&0xF0 == 0xE0 && i+2 < len(b):
+			units = append(units, uint16(c&0x0F)<<12|uint16(b[i+1]&0x3F)<<6|uint16(b[i+2]&0x3F))
+			i += 3
+		default:
+			return "", formatError("malformed modified UTF-8")
+		}
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+// BinaryName returns the binary name of the class with the internal name
+// name, like java.lang.String for java/lang/String.
+func BinaryName(name string) string {
+	return strings.ReplaceAll(name, "/", ".")
+}
+
+// Attribute returns the attribute of c named name, or nil.
+func (c *ClassFile) Attribute(name string) *Attribute {
+	return findAttribute(c.Attributes, name)
+}
+
+func findAttribute(attributes []Attribute, name string) *Attribute {
+	for i := range attributes {
+		if attributes[i].Name == name {
+			return &attributes[i]
+		}
+	}
+	return nil
+}
+
+// ReferencedClasses returns the binary names of the classes c refers to,
+// sorted, leaving out c itself and primitive types. They are those of the
+// Class constants, of the descriptors of its members, of the fields and
+// methods it uses, and of the annotations of the class and its members.
+func (c *ClassFile) ReferencedClasses() []string {
+	refs := make(map[string]bool)
+	for _, k := range c.ConstantPool {
+		switch k.Tag {
+		case TagClass:
+			if name, err := utf8At(c.ConstantPool, k.Index); err == nil {
+				if strings.HasPrefix(name, "[") {
+					addDescriptor(refs, name)
+				} else {
+					refs[BinaryName(name)] = true
+				}
+			}
+		case TagNameAndType:
+			if descriptor, err := utf8At(c.ConstantPool, k.Index2); err == nil {
+				addDescriptor(refs, descriptor)
+			}
+		case TagMethodType:
+			if descriptor, err := utf8At(c.ConstantPool, k.Index); err == nil {
+				addDescriptor(refs, descriptor)
+			}
+		}
+	}
+
+	annotated := [][]Attribute{c.Attributes}
+	for _, members := range [][]Member{c.Fields, c.Methods} {
+		for _, m := range members {
+			addDescriptor(refs, m.Descriptor)
+			annotated = append(annotated, m.Attributes)
+		}
+	}
+	for _, attributes := range annotated {
+		for _, name := range []string{"RuntimeVisibleAnnotations", "RuntimeInvisibleAnnotations"} {
+			if a := findAttribute(attributes, name); a != nil {
+				addAnnotations(refs, c.ConstantPool, a.Info)
+			}
+		}
+	}
+
+	delete(refs, c.ThisClass)
+	names := make([]string, 0, len(refs))
+	for name := range refs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// addDescriptor adds the classes of a field or method descriptor, like
+// (Ljava/lang/String;[I)Ljava/util/List;, to refs.
+func addDescriptor(refs map[string]bool, descriptor string) {
+	for {
+		start := strings.IndexByte(descriptor, 'L')
+		if start < 0 {
+			return
+		}
+		end := strings.IndexByte(descriptor[start:], ';')
+		if end < 0 {
+			return
+		}
+		refs[BinaryName(descriptor[start+1:start+end])] = true
+		descriptor = descriptor[start+end+1:]
+	}
+}
+
+// addAnnotations adds the types of the annotations of a
+// Runtime(In)VisibleAnnotations attribute to refs, including those of
+// their nested annotations and enum and class values.
+func addAnnotations(refs map[string]bool, pool []Constant, info []byte) {
+	r := &annotationReader{b: info, pool: pool, refs: refs, ok: true}
+	n := r.u2()
+	for i := 0; i < int(n) && r.ok; i++ {
+		r.annotation()
+	}
+}
+
+// annotationReader decodes annotations, stopping at the first malformed
+// byte with ok false.
+type annotationReader struct {
+	b    []byte
+	pool []Constant
+	refs map[string]bool
+	ok   bool
+}
+
+func (r *annotationReader) u1() byte {
+	if len(r.b) < 1 {
+		r.ok = false
+		return 0
+	}
+	v := r.b[0]
+	r.b = r.b[1:]
+	return v
+}
+
+func (r *annotationReader) u2() uint16 {
+	if len(r.b) < 2 {
+		r.ok = false
+		return 0
+	}
+	v := binary.BigEndian.Uint16(r.b)
+	r.b = r.b[2:]
+	return v
+}
+
+func (r *annotationReader) descriptor() {
+	if s, err := utf8At(r.pool, r.u2()); err == nil {
+		addDescriptor(r.refs, s)
+	}
+}
+
+func (r *annotationReader) annotation() {
+	r.descriptor()
+	pairs := r.u2()
+	for i := 0; i < int(pairs) && r.ok; i++ {
+		r.u2()
+		r.elementValue()
+	}
+}
+
+func (r *annotationReader) elementValue() {
+	switch r.u1() {
+	case 'B', 'C', 'D', 'F', 'I', 'J', 'S', 'Z', 's':
+		r.u2()
+	case 'e':
+		r.descriptor()
+		r.u2()
+	case 'c':
+		r.descriptor()
+	case '@':
+		r.annotation()
+	case '[':
+		n := r.u2()
+		for i := 0; i < int(n) && r.ok; i++ {
+			r.elementValue()
+		}
+	default:
+		r.ok = false
+	}
+}
@@ -0,0 +1,110 @@
+package classfile_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/obscurelyme/encoding/classfile"
+)
+
+func TestRead(t *testing.T) {
+	c, err := classfile.ReadFile(filepath.Join("testdata", "Example.class"))
+	if err != nil {
+		t.Fatalf("Expected no errors reading the class, but found: %s", err.Error())
+	}
+
+	t.Run("Should decode the class", func(t *testing.T) {
+		if c.MajorVersion != 61 || c.ThisClass != "org.example.app.Example" || c.SuperClass != "java.lang.Object" {
+			t.Errorf("Expected a Java 17 org.example.app.Example, but found: %d %s %s", c.MajorVersion, c.ThisClass, c.SuperClass)
+		}
+		if len(c.Interfaces) != 1 || c.Interfaces[0] != "java.io.Serializable" {
+			t.Errorf("Expected java.io.Serializable, but found: %v", c.Interfaces)
+		}
+		if len(c.Fields) != 1 || c.Fields[0].Name != "core" || len(c.Methods) != 1 || c.Methods[0].Descriptor != "([Lorg/example/util/Util;I)Ljava/util/List;" {
+			t.Errorf("Expected the field and method, but found: %v %v", c.Fields, c.Methods)
+		}
+		if c.Attribute("RuntimeVisibleAnnotations") == nil {
+			t.Errorf("Expected the annotations attribute")
+		}
+	})
+
+	t.Run("Should decode the constant pool", func(t *testing.T) {
+		var long, text bool
+		for i, k := range c.ConstantPool {
+			switch {
+			case k.Tag == classfile.TagLong && k.Value == 1<<40:
+				long = c.ConstantPool[i+1].Tag == 0
+			case k.Tag == classfile.TagUtf8 && k.Utf8 == "café\x00":
+				text = true
+			}
+		}
+		if !long || !text {
+			t.Errorf("Expected a long taking two entries and modified UTF-8 text, but found: %v %v", long, text)
+		}
+	})
+
+	t.Run("Should list the referenced classes", func(t *testing.T) {
+		expected := []string{
+			"java.io.Serializable",
+			"java.lang.Object",
+			"java.util.List",
+			"org.example.anno.Level",
+			"org.example.anno.Marker",
+			"org.example.core.Core",
+			"org.example.util.Util",
+			"org.example.web.Request",
+			"org.example.web.Web",
+		}
+		if refs := c.ReferencedClasses(); strings.Join(refs, " ") != strings.Join(expected, " ") {
+			t.Errorf("Expected %v, but found: %v", expected, refs)
+		}
+	})
+}
+
+func TestReadErrors(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "Example.class"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, b := range map[string][]byte{
+		"bad magic":   append([]byte{0xCA, 0xFE, 0xBA, 0xBF}, data[4:]...),
+		"truncated":   data[:len(data)/2],
+		"unknown tag": append(append(data[:10:10], 99), data[11:]...),
+	} {
+		var formatErr *classfile.FormatError
+		if _, err := classfile.Read(bytes.NewReader(b)); !errors.As(err, &formatErr) {
+			t.Errorf("Expected a FormatError for %s, but found: %v", name, err)
+		}
+	}
+
+	t.Run("Should not allocate the length of truncated attributes", func(t *testing.T) {
+		class := []byte{
+			0xCA, 0xFE, 0xBA, 0xBE, 0, 0, 0, 52,
+			// constant pool: #1 Utf8 A, #2 Class #1, #3 Utf8 X
+			0, 4, 1, 0, 1, 'A', 7, 0, 1, 1, 0, 1, 'X',
+			// access flags, this class, no super class, interfaces, fields and methods
+			0, 0x21, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0,
+			// one attribute X of 2 GiB, with 3 bytes
+			0, 1, 0, 3, 0x7F, 0xFF, 0xFF, 0xF0, 1, 2, 3,
+		}
+
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		_, err := classfile.Read(bytes.NewReader(class))
+		runtime.ReadMemStats(&after)
+
+		var formatErr *classfile.FormatError
+		if !errors.As(err, &formatErr) {
+			t.Errorf("Expected a FormatError, but found: %v", err)
+		}
+		if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+			t.Errorf("Expected less than 1 MiB allocated, but found: %d bytes", allocated)
+		}
+	})
+}
@@ -0,0 +1,208 @@
+// Command pom-merge-driver is a git merge driver for pom.xml files. It merges
+// dependencies, plugins, properties and modules by their identity instead of
+// by line, so that edits to different elements merge cleanly even when they
+// are next to each other in the document.
+//
+// To use it, declare the driver in the git configuration:
+//
+//	[merge "pom"]
+//		name = pom.xml merge
+//		driver = pom-merge-driver %O %A %B %P
+//
+// and select it for the POMs in .gitattributes:
+//
+//	pom.xml merge=pom
+//
+// The changes of theirs are made to ours in place, so its formatting, comments
+// and element order are kept. When both sides changed the same value
+// differently, the result keeps ours with a comment describing the conflict,
+// the conflicts are listed on standard error and the exit status is 1, so that
+// git reports the file as conflicted. When a file cannot be read as a POM, or
+// the changes cannot be made to ours in place, the driver falls back to the
+// line based git merge-file.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+func run(args []string, stderr io.Writer) int {
+	flags := flag.NewFlagSet("pom-merge-driver", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: pom-merge-driver base ours theirs [path]")
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() < 3 || flags.NArg() > 4 {
+		flags.Usage()
+		return 2
+	}
+
+	basePath, oursPath, theirsPath := flags.Arg(0), flags.Arg(1), flags.Arg(2)
+	name := oursPath
+	if flags.NArg() == 4 {
+		name = flags.Arg(3)
+	}
+
+	base, errBase := readModel(basePath)
+	ours, errOurs := readModel(oursPath)
+	theirs, errTheirs := readModel(theirsPath)
+	if err := errors.Join(errBase, errOurs, errTheirs); err != nil {
+		fmt.Fprintf(stderr, "%s: %s; merging by line\n", name, err)
+		return mergeFile(basePath, oursPath, theirsPath, stderr)
+	}
+
+	merged, conflicts := pom.Merge(base, ours, theirs)
+
+	data, err := mergeDocument(oursPath, ours, merged, conflicts)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %s; merging by line\n", name, err)
+		return mergeFile(basePath, oursPath, theirsPath, stderr)
+	}
+	if err := os.WriteFile(oursPath, data, 0o644); err != nil {
+		fmt.Fprintf(stderr, "%s: %s\n", name, err)
+		return 2
+	}
+
+	if len(conflicts) > 0 {
+		for _, c := range conflicts {
+			fmt.Fprintf(stderr, "%s: CONFLICT %s\n", name, c)
+		}
+		return 1
+	}
+	return 0
+}
+
+// readModel reads the POM at path. An empty file, which git passes as the
+// base of files added on both sides, is an empty model.
+func readModel(path string) (*pom.Model, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	return pom.Read(bytes.NewReader(data))
+}
+
+// mergeDocument makes the changes from ours to merged in the document at
+// path, the file of ours, and adds a comment before each conflict. It fails
+// when the edited document does not decode to merged.
+func mergeDocument(path string, ours, merged *pom.Model, conflicts []pom.Conflict) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if ours == nil {
+		return nil, errors.New("no document to merge into")
+	}
+	doc, err := pom.ParseDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// the elements of merged, to add those added as a whole
+	elements := make(map[string]any)
+	merged.Walk(func(path string, element any) {
+		elements[path] = element
+	})
+
+	for _, c := range pom.Diff(ours, merged) {
+		element, whole := elements[c.Path]
+		switch {
+		case c.Kind == pom.Removed:
+			err = doc.Remove(listItem(doc, c.Path, c.Old))
+		case whole:
+			// added elements, and configurations that changed
+			if doc.Has(c.Path) {
+				if err = doc.Remove(c.Path); err != nil {
+					break
+				}
+			}
+			err = doc.Add(listPath(c.Path), element)
+		case c.Kind == pom.Added && doc.Has(c.Path):
+			// values added to a list, like modules
+			err = doc.Add(c.Path, c.New)
+		default:
+			err = doc.Set(c.Path, c.New)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := doc.Model()
+	if err != nil {
+		return nil, err
+	}
+	if changes := pom.Diff(result, merged); len(changes) > 0 {
+		return nil, fmt.Errorf("cannot merge in place: %s", changes[0])
+	}
+
+	for _, c := range conflicts {
+		if err := doc.Comment(c.Path, " CONFLICT "+c.String()+" "); err != nil {
+			return nil, err
+		}
+	}
+	return doc.Bytes(), nil
+}
+
+// listItem returns the path of the element of a list of values, like the
+// modules, at path with the value old: Diff gives the values of such lists
+// the path of the list, which the Document takes for the first value.
+func listItem(doc *pom.Document, path, old string) string {
+	if strings.HasSuffix(path, "]") {
+		return path
+	}
+	if value, ok := doc.Get(path); !ok || value == old {
+		return path
+	}
+	for i := 1; ; i++ {
+		item := fmt.Sprintf("%s[%d]", path, i)
+		value, ok := doc.Get(item)
+		if !ok || value == old {
+			return item
+		}
+	}
+}
+
+// listPath returns path without the key of its last element, the list to
+// add the element to, like /project/dependencies/dependency.
+func listPath(path string) string {
+	if i := strings.LastIndexByte(path, '['); i >= 0 && strings.HasSuffix(path, "]") && !strings.Contains(path[i:], "/") {
+		return path[:i]
+	}
+	return path
+}
+
+// mergeFile merges the files by line with git merge-file, which leaves
+// conflict markers in ours.
+func mergeFile(base, ours, theirs string, stderr io.Writer) int {
+	cmd := exec.Command("git", "merge-file", "-L", "ours", "-L", "base", "-L", "theirs", ours, base, theirs)
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		var exit *exec.ExitError
+		if errors.As(err, &exit) {
+			return 1
+		}
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+func copyFixtures(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, name := range []string{"base.xml", "ours.xml", "theirs.xml"} {
+		data, err := os.ReadFile(filepath.Join("..", "..", "pom", "testdata", "merge", name))
+		if err != nil {
+			t.Fatalf("Expected no errors reading %s, but found: %s", name, err.Error())
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatalf("Expected no errors writing %s, but found: %s", name, err.Error())
+		}
+	}
+	return dir
+}
+
+func TestRun(t *testing.T) {
+	t.Run("Should write the merge over ours and report conflicts", func(t *testing.T) {
+		dir := copyFixtures(t)
+		ours := filepath.Join(dir, "ours.xml")
+
+		var stderr bytes.Buffer
+		code := run([]string{filepath.Join(dir, "base.xml"), ours, filepath.Join(dir, "theirs.xml"), "pom.xml"}, &stderr)
+
+		if code != 1 {
+			t.Errorf("Expected exit status 1, but found: %d", code)
+		}
+		if !strings.Contains(stderr.String(), "pom.xml: CONFLICT /project/dependencies/dependency[org.junit.jupiter:junit-jupiter:jar:]/version") {
+			t.Errorf("Expected the conflict to be reported, but found: %s", stderr.String())
+		}
+
+		merged, err := pom.ReadFile(ours)
+		if err != nil {
+			t.Fatalf("Expected the merge to be a valid pom, but found: %s", err.Error())
+		}
+		if merged.Properties.Fields["jackson.version"] != "2.16.1" {
+			t.Errorf("Expected the changes of theirs to be merged into ours")
+		}
+	})
+
+	t.Run("Should keep the formatting and comments of ours", func(t *testing.T) {
+		dir := copyFixtures(t)
+		ours := filepath.Join(dir, "ours.xml")
+		data, err := os.ReadFile(ours)
+		if err != nil {
+			t.Fatalf("Expected no errors reading ours, but found: %s", err.Error())
+		}
+		data = bytes.Replace(data, []byte("  <modules>"), []byte("  <!-- the modules -->\n  <modules>"), 1)
+		if err := os.WriteFile(ours, data, 0o644); err != nil {
+			t.Fatalf("Expected no errors writing ours, but found: %s", err.Error())
+		}
+
+		var stderr bytes.Buffer
+		run([]string{filepath.Join(dir, "base.xml"), ours, filepath.Join(dir, "theirs.xml"), "pom.xml"}, &stderr)
+
+		expected, err := os.ReadFile(filepath.Join("testdata", "merged.xml"))
+		if err != nil {
+			t.Fatalf("Expected no errors reading the expected merge, but found: %s", err.Error())
+		}
+		if found, _ := os.ReadFile(ours); string(found) != string(expected) {
+			t.Errorf("Expected:\n%s\nbut found:\n%s", expected, found)
+		}
+		if strings.Contains(stderr.String(), "merging by line") {
+			t.Errorf("Expected the merge to be made in place, but found: %s", stderr.String())
+		}
+	})
+
+	t.Run("Should exit cleanly without conflicts", func(t *testing.T) {
+		dir := copyFixtures(t)
+
+		var stderr bytes.Buffer
+		code := run([]string{filepath.Join(dir, "base.xml"), filepath.Join(dir, "base.xml"), filepath.Join(dir, "theirs.xml")}, &stderr)
+
+		if code != 0 {
+			t.Errorf("Expected exit status 0, but found: %d %s", code, stderr.String())
+		}
+	})
+
+	t.Run("Should reject missing arguments", func(t *testing.T) {
+		var stderr bytes.Buffer
+		if code := run([]string{"base.xml"}, &stderr); code != 2 {
+			t.Errorf("Expected exit status 2, but found: %d", code)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/obscurelyme/encoding/pom/analyze"
+)
+
+func runAnalyze(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("analyze", "", stderr)
+	model := addModelFlags(flags)
+	basedir := flags.String("basedir", "", "the `dir` of the compiled project, the directory of the POM by default")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+	if *basedir == "" {
+		*basedir = filepath.Dir(*model.file)
+	}
+
+	root, err := model.resolve(false)
+	if err != nil {
+		fmt.Fprintf(stderr, "pom analyze: %s\n", err)
+		return 2
+	}
+	repository, err := model.resolver()
+	if err != nil {
+		fmt.Fprintf(stderr, "pom analyze: %s\n", err)
+		return 2
+	}
+	a, err := analyze.Analyze(root, &analyze.Options{Repository: repository, Basedir: *basedir})
+	if err != nil {
+		fmt.Fprintf(stderr, "pom analyze: %s\n", err)
+		return 2
+	}
+	if err := a.WriteText(stdout); err != nil {
+		fmt.Fprintf(stderr, "pom analyze: %s\n", err)
+		return 2
+	}
+	if a.Problems() {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	testdata := filepath.Join("..", "..", "pom", "analyze", "testdata")
+	code, stdout, stderr := runPom(t, "analyze", "-f", filepath.Join(testdata, "pom.xml"), "-repo", filepath.Join(testdata, "repository"))
+
+	if code != 1 || !strings.HasPrefix(stdout, "Used undeclared dependencies found:\n   org.example:impl:jar:1.0:compile\n") {
+		t.Errorf("Expected exit status 1 and the undeclared impl dependency, but found: %d %s %s", code, stdout, stderr)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/obscurelyme/encoding/pom/resolve"
+)
+
+// placements are the values of the -module-path flag of classpath.
+var placements = map[string]resolve.Placement{
+	"none": resolve.PlaceClasspath,
+	"all":  resolve.PlaceModulePath,
+	"auto": resolve.PlaceAuto,
+}
+
+func runClasspath(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("classpath", "", stderr)
+	model := addModelFlags(flags)
+	scope := flags.String("scope", "compile", "the `scope`: compile, runtime, test, main-compile, main-runtime, test-compile or test-runtime")
+	modulePath := flags.String("module-path", "none", "the dependencies on the module path: none, all or auto, for the modules among them")
+	basedir := flags.String("basedir", "", "put the output directories of the project in `dir` first")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+	pathScope, err := resolve.ParsePathScope(*scope)
+	if err != nil {
+		fmt.Fprintf(stderr, "pom classpath: %s\n", err)
+		return 2
+	}
+	placement, ok := placements[*modulePath]
+	if !ok {
+		fmt.Fprintf(stderr, "pom classpath: unknown module path placement %q\n", *modulePath)
+		return 2
+	}
+
+	root, err := model.resolve(false)
+	if err != nil {
+		fmt.Fprintf(stderr, "pom classpath: %s\n", err)
+		return 2
+	}
+	repository, err := model.resolver()
+	if err != nil {
+		fmt.Fprintf(stderr, "pom classpath: %s\n", err)
+		return 2
+	}
+	cp, err := root.Classpath(pathScope, &resolve.ClasspathOptions{Repository: repository, Placement: placement, Basedir: *basedir})
+	if err != nil {
+		fmt.Fprintf(stderr, "pom classpath: %s\n", err)
+		return 2
+	}
+
+	if placement == resolve.PlaceClasspath {
+		fmt.Fprintln(stdout, cp.ClassPath())
+		return 0
+	}
+	if p := cp.ModulePath(); p != "" {
+		fmt.Fprintf(stdout, "--module-path %s\n", p)
+	}
+	if p := cp.ClassPath(); p != "" {
+		fmt.Fprintf(stdout, "--class-path %s\n", p)
+	}
+	return 0
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClasspath(t *testing.T) {
+	testdata := filepath.Join("..", "..", "pom", "resolve", "testdata")
+	repository := filepath.Join(testdata, "repository")
+	args := []string{"classpath", "-f", filepath.Join(testdata, "pom.xml"), "-repo", repository}
+
+	t.Run("Should print the classpath of the scope", func(t *testing.T) {
+		code, stdout, stderr := runPom(t, append(args, "-scope", "runtime")...)
+
+		entries := strings.Split(strings.TrimSuffix(stdout, "\n"), string(os.PathListSeparator))
+		expected := filepath.Join(repository, "org", "example", "web", "1.0", "web-1.0.jar")
+		if code != 0 || len(entries) != 6 || entries[0] != expected {
+			t.Errorf("Expected 6 entries starting with %s, but found: %d %s %s", expected, code, stdout, stderr)
+		}
+	})
+
+	t.Run("Should put everything on the module path", func(t *testing.T) {
+		code, stdout, stderr := runPom(t, append(args, "-module-path", "all")...)
+
+		if code != 0 || !strings.HasPrefix(stdout, "--module-path ") || strings.Contains(stdout, "--class-path") {
+			t.Errorf("Expected a module path only, but found: %d %s %s", code, stdout, stderr)
+		}
+	})
+
+	t.Run("Should reject unknown scopes", func(t *testing.T) {
+		if code, _, _ := runPom(t, append(args, "-scope", "import")...); code != 2 {
+			t.Errorf("Expected exit status 2, but found: %d", code)
+		}
+	})
+}
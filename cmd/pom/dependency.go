@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+func runAddDependency(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("add-dependency", "groupId:artifactId[:version]", stderr)
+	file := flags.String("f", "pom.xml", "the POM `file`")
+	scope := flags.String("scope", "", "the `scope` of the dependency")
+	typ := flags.String("type", "", "the `type` of the dependency")
+	classifier := flags.String("classifier", "", "the `classifier` of the dependency")
+	optional := flags.Bool("optional", false, "mark the dependency optional")
+	managed := flags.Bool("managed", false, "add the dependency to the dependency management")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	parts := strings.Split(flags.Arg(0), ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		fmt.Fprintf(stderr, "pom add-dependency: invalid coordinates %q\n", flags.Arg(0))
+		return 2
+	}
+	dep := pom.Dependency{GroupId: parts[0], ArtifactId: parts[1], Type: *typ, Classifier: *classifier, Scope: *scope}
+	if len(parts) == 3 {
+		dep.Version = parts[2]
+	}
+	if *optional {
+		dep.Optional = "true"
+	}
+
+	path := dependenciesPath(*managed)
+	err := editFile(*file, func(d *pom.Document) error {
+		if d.Has(path + "[" + dep.ManagementKey() + "]") {
+			return fmt.Errorf("%s is already declared", dep.ManagementKey())
+		}
+		return d.Add(path, dep)
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "pom add-dependency: %s\n", err)
+		return 2
+	}
+	return 0
+}
+
+func runRemoveDependency(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("remove-dependency", "groupId:artifactId[:type[:classifier]]", stderr)
+	file := flags.String("f", "pom.xml", "the POM `file`")
+	managed := flags.Bool("managed", false, "remove the dependency from the dependency management")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	parts := strings.Split(flags.Arg(0), ":")
+	if len(parts) < 2 || len(parts) > 4 || parts[0] == "" || parts[1] == "" {
+		fmt.Fprintf(stderr, "pom remove-dependency: invalid coordinates %q\n", flags.Arg(0))
+		return 2
+	}
+	parts = append(parts, "", "")[:4]
+	dep := pom.Dependency{GroupId: parts[0], ArtifactId: parts[1], Type: parts[2], Classifier: parts[3]}
+
+	err := editFile(*file, func(d *pom.Document) error {
+		return d.Remove(dependenciesPath(*managed) + "[" + dep.ManagementKey() + "]")
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "pom remove-dependency: %s\n", err)
+		return 2
+	}
+	return 0
+}
+
+func dependenciesPath(managed bool) string {
+	if managed {
+		return "/project/dependencyManagement/dependencies/dependency"
+	}
+	return "/project/dependencies/dependency"
+}
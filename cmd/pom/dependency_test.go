@@ -0,0 +1,48 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDependency(t *testing.T) {
+	t.Run("Should add and remove dependencies", func(t *testing.T) {
+		path := copyTestdata(t, filepath.Join("document", "pom.xml"))
+		original, _ := os.ReadFile(path)
+
+		if code, _, stderr := runPom(t, "add-dependency", "-f", path, "-scope", "test", "org.assertj:assertj-core:3.25.1"); code != 0 {
+			t.Fatalf("Expected exit status 0, but found: %d %s", code, stderr)
+		}
+		data, _ := os.ReadFile(path)
+		expected := "\t\t<dependency>\n\t\t\t<groupId>org.assertj</groupId>\n\t\t\t<artifactId>assertj-core</artifactId>\n\t\t\t<version>3.25.1</version>\n\t\t\t<scope>test</scope>\n\t\t</dependency>\n\t</dependencies>"
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Expected the dependency at the end of the list, but found:\n%s", data)
+		}
+
+		if code, _, stderr := runPom(t, "remove-dependency", "-f", path, "org.assertj:assertj-core"); code != 0 {
+			t.Fatalf("Expected exit status 0, but found: %d %s", code, stderr)
+		}
+		data, _ = os.ReadFile(path)
+		if string(data) != string(original) {
+			t.Errorf("Expected the original document, but found:\n%s", data)
+		}
+	})
+
+	t.Run("Should not add a dependency twice", func(t *testing.T) {
+		path := copyTestdata(t, filepath.Join("document", "pom.xml"))
+
+		if code, _, stderr := runPom(t, "add-dependency", "-f", path, "org.slf4j:slf4j-api:2.0.10"); code != 2 || !strings.Contains(stderr, "already declared") {
+			t.Errorf("Expected exit status 2, but found: %d %s", code, stderr)
+		}
+	})
+
+	t.Run("Should fail on missing dependencies", func(t *testing.T) {
+		path := copyTestdata(t, filepath.Join("document", "pom.xml"))
+
+		if code, _, _ := runPom(t, "remove-dependency", "-f", path, "-managed", "org.slf4j:slf4j-api"); code != 2 {
+			t.Errorf("Expected exit status 2, but found: %d", code)
+		}
+	})
+}
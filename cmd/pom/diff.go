@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+func runDiff(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("diff", "old new", stderr)
+	json := flags.Bool("json", false, "write the changes as JSON")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+
+	var models [2]*pom.Model
+	for i, file := range flags.Args() {
+		m, err := pom.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(stderr, "pom diff: %s\n", err)
+			return 2
+		}
+		models[i] = m
+	}
+
+	changes := pom.Diff(models[0], models[1])
+	write := changes.WriteText
+	if *json {
+		write = changes.WriteJSON
+	}
+	if err := write(stdout); err != nil {
+		fmt.Fprintf(stderr, "pom diff: %s\n", err)
+		return 2
+	}
+	if len(changes) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	testdata := filepath.Join("..", "..", "pom", "testdata", "diff")
+	before, after := filepath.Join(testdata, "before.xml"), filepath.Join(testdata, "after.xml")
+
+	t.Run("Should fail when the POMs differ", func(t *testing.T) {
+		if code, stdout, _ := runPom(t, "diff", before, after); code != 1 || stdout == "" {
+			t.Errorf("Expected exit status 1 and changes, but found: %d %s", code, stdout)
+		}
+		if code, stdout, _ := runPom(t, "diff", before, before); code != 0 || stdout != "" {
+			t.Errorf("Expected exit status 0 and no changes, but found: %d %s", code, stdout)
+		}
+	})
+
+	t.Run("Should write JSON", func(t *testing.T) {
+		_, stdout, _ := runPom(t, "diff", "-json", before, after)
+
+		var changes []map[string]any
+		if err := json.Unmarshal([]byte(stdout), &changes); err != nil || len(changes) == 0 {
+			t.Errorf("Expected a JSON list of changes, but found: %s", stdout)
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"path/filepath"
+	"strings"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+// propertiesFlag collects -D name=value options.
+type propertiesFlag map[string]string
+
+func (p propertiesFlag) String() string {
+	return ""
+}
+
+func (p propertiesFlag) Set(s string) error {
+	name, value, _ := strings.Cut(s, "=")
+	if name == "" {
+		return fmt.Errorf("invalid property %q", s)
+	}
+	p[name] = value
+	return nil
+}
+
+// modelFlags are the flags of the commands reading the effective model of a
+// POM.
+type modelFlags struct {
+	file       *string
+	properties propertiesFlag
+	profiles   *string
+	repository *string
+	// superPOM is inherited by the effective model when set.
+	superPOM *pom.Model
+}
+
+func addModelFlags(flags *flag.FlagSet) *modelFlags {
+	f := &modelFlags{properties: make(propertiesFlag)}
+	f.file = flags.String("f", "pom.xml", "the POM `file`")
+	flags.Var(f.properties, "D", "set a user `property`, like name=value")
+	f.profiles = flags.String("P", "", "comma separated `profiles` to activate, or deactivate with a ! or - prefix")
+	f.repository = flags.String("repo", "", "the local repository `dir`ectory, ~/.m2/repository by default")
+	return f
+}
+
+// resolver returns the local repository selected by the flags.
+func (f *modelFlags) resolver() (pom.LocalRepository, error) {
+	if *f.repository != "" {
+		return pom.LocalRepository{Dir: *f.repository}, nil
+	}
+	return pom.DefaultLocalRepository()
+}
+
+// userProperties returns the properties of the -D flags, over those of the
+// .mvn/maven.config file of the project.
+func (f *modelFlags) userProperties() (map[string]string, error) {
+	c, err := pom.FindMavenConfig(filepath.Dir(*f.file))
+	if err != nil || c == nil {
+		return f.properties, err
+	}
+
+	props := maps.Clone(c.Properties)
+	maps.Copy(props, f.properties)
+	return props, nil
+}
+
+// effective reads the POM selected by the flags and builds its effective
+// model, with the options of the .mvn/maven.config file of the project
+// under those of the flags.
+func (f *modelFlags) effective() (*pom.Model, error) {
+	dir := filepath.Dir(*f.file)
+	c, err := pom.FindMavenConfig(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := pom.ReadFile(*f.file)
+	if err != nil {
+		return nil, err
+	}
+	repository, err := f.resolver()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &pom.EffectiveOptions{
+		Dir:        dir,
+		Resolver:   repository,
+		Properties: f.properties,
+		SuperPOM:   f.superPOM,
+	}
+	opts.ActiveProfiles, opts.InactiveProfiles = pom.ParseProfileIds(*f.profiles)
+	if c != nil {
+		opts = c.Apply(opts)
+	}
+	return m.Effective(opts)
+}
+
+func runEffective(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("effective", "", stderr)
+	model := addModelFlags(flags)
+	superPOM := flags.String("super-pom", "", "inherit the Super POM of the Maven `version`, like 3.9.6 or 4.0.0")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+
+	if *superPOM != "" {
+		var err error
+		if model.superPOM, err = pom.SuperPOM(*superPOM); err != nil {
+			fmt.Fprintf(stderr, "pom effective: %s\n", err)
+			return 2
+		}
+	}
+	m, err := model.effective()
+	if err != nil {
+		fmt.Fprintf(stderr, "pom effective: %s\n", err)
+		return 2
+	}
+	if err := m.Write(stdout, nil); err != nil {
+		fmt.Fprintf(stderr, "pom effective: %s\n", err)
+		return 2
+	}
+	return 0
+}
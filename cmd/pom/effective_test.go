@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEffective(t *testing.T) {
+	testdata := filepath.Join("..", "..", "pom", "testdata", "effective")
+	args := []string{"-f", filepath.Join(testdata, "core", "pom.xml"), "-repo", filepath.Join(testdata, "repository")}
+
+	t.Run("Should print the effective POM", func(t *testing.T) {
+		code, stdout, stderr := runPom(t, append([]string{"effective"}, args...)...)
+
+		if code != 0 || !strings.Contains(stdout, "<version>1.2.0-SNAPSHOT</version>\n  <name>Example core</name>") {
+			t.Errorf("Expected the interpolated version and inherited name, but found: %d %s %s", code, stdout, stderr)
+		}
+	})
+
+	t.Run("Should evaluate against the effective model", func(t *testing.T) {
+		code, stdout, stderr := runPom(t, append([]string{"get", "-effective"}, append(args, "project.url")...)...)
+
+		if code != 0 || stdout != "https://example.org/project/core\n" {
+			t.Errorf("Expected the inherited url, but found: %d %q %s", code, stdout, stderr)
+		}
+	})
+
+	t.Run("Should inherit the Super POM", func(t *testing.T) {
+		code, stdout, stderr := runPom(t, append([]string{"effective", "-super-pom", "3.9.6"}, args...)...)
+
+		if code != 0 || !strings.Contains(stdout, "<url>https://repo.maven.apache.org/maven2</url>") || !strings.Contains(stdout, "/src/main/java</sourceDirectory>") {
+			t.Errorf("Expected the central repository and default directories, but found: %d %s %s", code, stdout, stderr)
+		}
+	})
+
+	t.Run("Should apply the options of maven.config", func(t *testing.T) {
+		dir := t.TempDir()
+		os.MkdirAll(filepath.Join(dir, ".mvn"), 0o755)
+		os.WriteFile(filepath.Join(dir, ".mvn", "maven.config"), []byte("-Drevision=2.0 -Pci\n"), 0o644)
+		os.WriteFile(filepath.Join(dir, "pom.xml"), []byte(`<project>
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>org.example</groupId>
+  <artifactId>example</artifactId>
+  <version>${revision}</version>
+  <profiles>
+    <profile>
+      <id>ci</id>
+      <properties>
+        <ci>true</ci>
+      </properties>
+    </profile>
+  </profiles>
+</project>`), 0o644)
+
+		code, stdout, stderr := runPom(t, "effective", "-f", filepath.Join(dir, "pom.xml"), "-repo", dir, "-D", "revision=2.1")
+
+		if code != 0 || !strings.Contains(stdout, "<version>2.1</version>") || !strings.Contains(stdout, "<ci>true</ci>") {
+			t.Errorf("Expected the version of the flags and the profile of maven.config, but found: %d %s %s", code, stdout, stderr)
+		}
+	})
+}
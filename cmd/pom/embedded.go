@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/obscurelyme/encoding/jar"
+)
+
+// embeddedJSON is the JSON form of a jar.EmbeddedPOM.
+type embeddedJSON struct {
+	Archive    string `json:"archive"`
+	Path       string `json:"path"`
+	GroupId    string `json:"groupId"`
+	ArtifactId string `json:"artifactId"`
+	Version    string `json:"version"`
+	Error      string `json:"error,omitempty"`
+}
+
+func runEmbedded(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("embedded", "archive ...", stderr)
+	asJSON := flags.Bool("json", false, "write the POMs found as JSON")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	status := 0
+	found := []embeddedJSON{}
+	for _, archive := range flags.Args() {
+		poms, err := jar.EmbeddedPOMs(archive)
+		if err != nil {
+			fmt.Fprintf(stderr, "pom embedded: %s\n", err)
+			status = 2
+			continue
+		}
+		for _, p := range poms {
+			e := embeddedJSON{Archive: p.Archive, Path: p.Dir, GroupId: p.GroupId, ArtifactId: p.ArtifactId, Version: p.Version}
+			if p.Err != nil {
+				e.Error = p.Err.Error()
+			}
+			found = append(found, e)
+			switch {
+			case *asJSON:
+			case p.Dir == "":
+				fmt.Fprintf(stderr, "pom embedded: skipped %s\n", p.Err)
+			default:
+				fmt.Fprintf(stdout, "%s: %s\n", p.Archive, p.Coordinates())
+			}
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(found); err != nil {
+			fmt.Fprintf(stderr, "pom embedded: %s\n", err)
+			return 2
+		}
+	}
+	return status
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEmbedded(t *testing.T) {
+	example := filepath.Join("..", "..", "jar", "testdata", "example.jar")
+	api := filepath.Join("..", "..", "pom", "analyze", "testdata", "repository", "org", "example", "api", "1.0", "api-1.0.jar")
+
+	t.Run("Should print the coordinates of the POMs", func(t *testing.T) {
+		code, stdout, stderr := runPom(t, "embedded", api, example)
+
+		if code != 0 || stdout != example+": org.example:example:1.0\n" {
+			t.Errorf("Expected the POM of example only, but found: %d %s %s", code, stdout, stderr)
+		}
+	})
+
+	t.Run("Should write JSON", func(t *testing.T) {
+		code, stdout, stderr := runPom(t, "embedded", "-json", example)
+
+		var found []map[string]string
+		if err := json.Unmarshal([]byte(stdout), &found); err != nil || code != 0 || len(found) != 1 || found[0]["artifactId"] != "example" {
+			t.Errorf("Expected the POM of example as JSON, but found: %d %s %s", code, stdout, stderr)
+		}
+	})
+
+	t.Run("Should report archives that cannot be read", func(t *testing.T) {
+		code, _, stderr := runPom(t, "embedded", filepath.Join("..", "..", "pom", "pom.xml"))
+
+		if code != 2 || !strings.HasPrefix(stderr, "pom embedded: ") {
+			t.Errorf("Expected exit status 2, but found: %d %s", code, stderr)
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/obscurelyme/encoding/pom"
+	"github.com/obscurelyme/encoding/pom/enforcer"
+	"github.com/obscurelyme/encoding/pom/resolve"
+)
+
+const defaultEnforcerRules = "dependencyConvergence,requireUpperBoundDeps,banDuplicatePomDependencyVersions,requireReleaseDeps,requirePluginVersions"
+
+func runEnforce(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("enforce", "", stderr)
+	model := addModelFlags(flags)
+	names := flags.String("rules", defaultEnforcerRules, "comma separated `rules` to check")
+	ban := flags.String("ban", "", "comma separated `patterns` of the dependencies to ban, like groupId:artifactId:version")
+	allow := flags.String("allow", "", "comma separated `patterns` of the dependencies to allow despite -ban")
+	mavenRange := flags.String("require-maven", "", "the `range` of Maven versions to require")
+	javaRange := flags.String("require-java", "", "the `range` of Java versions to require")
+	mavenVersion := flags.String("maven-version", "", "the Maven `version` of the build, instead of the prerequisites of the POM")
+	javaVersion := flags.String("java-version", "", "the Java `version` of the build, instead of the compiler release of the POM")
+	asJSON := flags.Bool("json", false, "write the violations as JSON")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+
+	rules := make(map[string]enforcer.Rule)
+	for _, r := range []enforcer.Rule{
+		enforcer.DependencyConvergence{},
+		enforcer.RequireUpperBoundDeps{},
+		enforcer.BanDuplicatePomDependencyVersions{},
+		enforcer.RequireReleaseDeps{OnlyWhenRelease: true},
+		enforcer.RequirePluginVersions{},
+	} {
+		rules[r.ID()] = r
+	}
+	var selected []enforcer.Rule
+	for _, name := range splitList(*names) {
+		r, ok := rules[name]
+		if !ok {
+			fmt.Fprintf(stderr, "pom enforce: unknown rule %q\n", name)
+			return 2
+		}
+		selected = append(selected, r)
+	}
+	if *ban != "" {
+		selected = append(selected, enforcer.BannedDependencies{Excludes: splitList(*ban), Includes: splitList(*allow)})
+	}
+	if *mavenRange != "" {
+		selected = append(selected, enforcer.RequireMavenVersion{Version: *mavenRange})
+	}
+	if *javaRange != "" {
+		selected = append(selected, enforcer.RequireJavaVersion{Version: *javaRange})
+	}
+
+	p, err := model.project()
+	if err != nil {
+		fmt.Fprintf(stderr, "pom enforce: %s\n", err)
+		return 2
+	}
+	p.MavenVersion, p.JavaVersion = *mavenVersion, *javaVersion
+	violations, err := enforcer.Enforce(p, selected...)
+	if err != nil {
+		fmt.Fprintf(stderr, "pom enforce: %s\n", err)
+		return 2
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if violations == nil {
+			violations = []enforcer.Violation{}
+		}
+		if err := enc.Encode(violations); err != nil {
+			fmt.Fprintf(stderr, "pom enforce: %s\n", err)
+			return 2
+		}
+	} else {
+		for _, v := range violations {
+			fmt.Fprintln(stdout, v)
+		}
+	}
+	if len(violations) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// project reads the POM selected by the flags into an enforcer.Project,
+// with its full dependency graph.
+func (f *modelFlags) project() (*enforcer.Project, error) {
+	m, err := pom.ReadFile(*f.file)
+	if err != nil {
+		return nil, err
+	}
+	eff, err := f.effective()
+	if err != nil {
+		return nil, err
+	}
+	repository, err := f.resolver()
+	if err != nil {
+		return nil, err
+	}
+	props, err := f.userProperties()
+	if err != nil {
+		return nil, err
+	}
+	root, err := resolve.Resolve(eff, &resolve.Options{Resolver: repository, Properties: props, Full: true})
+	if err != nil {
+		return nil, err
+	}
+	return &enforcer.Project{Model: m, Effective: eff, Graph: root}, nil
+}
+
+func splitList(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r == ',' })
+}
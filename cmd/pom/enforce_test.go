@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEnforce(t *testing.T) {
+	testdata := filepath.Join("..", "..", "pom", "resolve", "testdata")
+	args := []string{"enforce", "-f", filepath.Join(testdata, "pom.xml"), "-repo", filepath.Join(testdata, "repository")}
+
+	t.Run("Should print violations and fail", func(t *testing.T) {
+		code, stdout, stderr := runPom(t, args...)
+
+		if code != 1 || !strings.Contains(stdout, "dependencyConvergence: dependency org.example:core:jar: does not converge") || !strings.Contains(stdout, "\n  org.example:app:1.0.0 -> org.example:web:1.0 -> org.example:core:1.0\n") {
+			t.Errorf("Expected exit status 1 and core not to converge, but found: %d %s %s", code, stdout, stderr)
+		}
+	})
+
+	t.Run("Should check the rules asked for", func(t *testing.T) {
+		code, stdout, stderr := runPom(t, append(args, "-rules", "requireReleaseDeps", "-ban", "*:common", "-json")...)
+
+		var violations []map[string]any
+		if err := json.Unmarshal([]byte(stdout), &violations); err != nil || code != 1 || len(violations) != 1 || violations[0]["rule"] != "bannedDependencies" {
+			t.Errorf("Expected the banned dependency as JSON, but found: %d %s %s", code, stdout, stderr)
+		}
+	})
+
+	t.Run("Should pass without violations", func(t *testing.T) {
+		if code, stdout, stderr := runPom(t, append(args, "-rules", "requireReleaseDeps")...); code != 0 {
+			t.Errorf("Expected exit status 0, but found: %d %s %s", code, stdout, stderr)
+		}
+	})
+
+	t.Run("Should reject unknown rules", func(t *testing.T) {
+		if code, _, _ := runPom(t, append(args, "-rules", "requireFilesExist")...); code != 2 {
+			t.Errorf("Expected exit status 2, but found: %d", code)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+var dependencyOrders = map[string]pom.DependencyOrder{
+	"none":        pom.DependencyOrderNone,
+	"coordinates": pom.DependencyOrderCoordinates,
+	"scope":       pom.DependencyOrderScope,
+}
+
+func runFmt(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("fmt", "[file ...]", stderr)
+	write := flags.Bool("w", false, "write the result to the files instead of standard output")
+	check := flags.Bool("check", false, "list the files that are not formatted and exit with status 1 if there are any")
+	indent := flags.String("indent", "  ", "indentation of one level")
+	crlf := flags.Bool("crlf", false, "end lines with CRLF")
+	sortDeps := flags.String("sort-dependencies", "none", "sort dependencies: none, coordinates or scope")
+	sortPlugins := flags.Bool("sort-plugins", false, "sort plugins by groupId and artifactId")
+	sortProps := flags.Bool("sort-properties", false, "sort properties by name")
+	sortModules := flags.Bool("sort-modules", false, "sort modules")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	order, ok := dependencyOrders[*sortDeps]
+	if !ok {
+		fmt.Fprintf(stderr, "pom fmt: unknown dependency order %q\n", *sortDeps)
+		return 2
+	}
+
+	opts := pom.DefaultFormatOptions
+	opts.Indent = *indent
+	opts.SortDependencies = order
+	opts.SortPlugins = *sortPlugins
+	opts.SortProperties = *sortProps
+	opts.SortModules = *sortModules
+	if *crlf {
+		opts.LineEnding = "\r\n"
+	}
+
+	status := 0
+	for _, file := range files(flags) {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(stderr, "pom fmt: %s\n", err)
+			status = 2
+			continue
+		}
+
+		out, err := pom.Format(data, &opts)
+		if err != nil {
+			fmt.Fprintf(stderr, "pom fmt: %s: %s\n", file, err)
+			status = 2
+			continue
+		}
+
+		switch {
+		case *check:
+			if !bytes.Equal(data, out) {
+				fmt.Fprintln(stdout, file)
+				status = max(status, 1)
+			}
+		case *write:
+			if bytes.Equal(data, out) {
+				continue
+			}
+			if err := os.WriteFile(file, out, 0o644); err != nil {
+				fmt.Fprintf(stderr, "pom fmt: %s\n", err)
+				status = 2
+			}
+		default:
+			stdout.Write(out)
+		}
+	}
+	return status
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFmt(t *testing.T) {
+	sorted := []string{"-sort-dependencies", "scope", "-sort-plugins", "-sort-properties", "-sort-modules"}
+
+	t.Run("Should list unformatted files in check mode", func(t *testing.T) {
+		path := copyTestdata(t, filepath.Join("format", "pom.xml"))
+
+		code, stdout, _ := runPom(t, append([]string{"fmt", "-check"}, append(sorted, path)...)...)
+
+		if code != 1 || stdout != path+"\n" {
+			t.Errorf("Expected exit status 1 listing the file, but found: %d %q", code, stdout)
+		}
+	})
+
+	t.Run("Should write the formatted files", func(t *testing.T) {
+		path := copyTestdata(t, filepath.Join("format", "pom.xml"))
+
+		if code, _, stderr := runPom(t, append([]string{"fmt", "-w"}, append(sorted, path)...)...); code != 0 {
+			t.Fatalf("Expected exit status 0, but found: %d %s", code, stderr)
+		}
+
+		data, _ := os.ReadFile(path)
+		expected, _ := os.ReadFile(filepath.Join("..", "..", "pom", "testdata", "format", "sorted.xml"))
+		if string(data) != string(expected) {
+			t.Errorf("Expected:\n%s\nbut found:\n%s", expected, data)
+		}
+
+		if code, stdout, _ := runPom(t, append([]string{"fmt", "-check"}, append(sorted, path)...)...); code != 0 {
+			t.Errorf("Expected the written file to pass the check, but found: %d %s", code, stdout)
+		}
+	})
+
+	t.Run("Should reject an unknown dependency order", func(t *testing.T) {
+		if code, _, _ := runPom(t, "fmt", "-sort-dependencies", "random"); code != 2 {
+			t.Errorf("Expected exit status 2, but found: %d", code)
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+func runGet(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("get", "expression", stderr)
+	model := addModelFlags(flags)
+	effective := flags.Bool("effective", false, "evaluate against the effective model, with parents and profiles")
+	raw := flags.Bool("raw", false, "print the text of the element at the expression, a path like dependencies/dependency[g:a:jar:]/version, as written")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+	expression := flags.Arg(0)
+
+	var value string
+	var ok bool
+	switch {
+	case *raw:
+		data, err := os.ReadFile(*model.file)
+		if err != nil {
+			fmt.Fprintf(stderr, "pom get: %s\n", err)
+			return 2
+		}
+		d, err := pom.ParseDocument(data)
+		if err != nil {
+			fmt.Fprintf(stderr, "pom get: %s\n", err)
+			return 2
+		}
+		value, ok = d.Get(expression)
+	default:
+		var m *pom.Model
+		var err error
+		if *effective {
+			m, err = model.effective()
+		} else {
+			m, err = pom.ReadFile(*model.file)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "pom get: %s\n", err)
+			return 2
+		}
+		props, err := model.userProperties()
+		if err != nil {
+			fmt.Fprintf(stderr, "pom get: %s\n", err)
+			return 2
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(expression, "${"), "}")
+		value, ok = m.Evaluate(name, props)
+	}
+
+	if !ok {
+		fmt.Fprintf(stderr, "pom get: no value for %s\n", expression)
+		return 1
+	}
+	fmt.Fprintln(stdout, value)
+	return 0
+}
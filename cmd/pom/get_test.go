@@ -0,0 +1,45 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	path := filepath.Join("..", "..", "pom", "testdata", "document", "pom.xml")
+
+	t.Run("Should print the value of expressions", func(t *testing.T) {
+		for expression, expected := range map[string]string{
+			"project.version":       "1.0.0-SNAPSHOT",
+			"${project.artifactId}": "service",
+			"slf4j.version":         "2.0.9",
+		} {
+			code, stdout, stderr := runPom(t, "get", "-f", path, expression)
+			if code != 0 || stdout != expected+"\n" {
+				t.Errorf("Expected %s for %s, but found: %d %q %s", expected, expression, code, stdout, stderr)
+			}
+		}
+	})
+
+	t.Run("Should prefer user properties", func(t *testing.T) {
+		code, stdout, _ := runPom(t, "get", "-f", path, "-D", "slf4j.version=2.0.10", "slf4j.version")
+
+		if code != 0 || stdout != "2.0.10\n" {
+			t.Errorf("Expected 2.0.10, but found: %d %q", code, stdout)
+		}
+	})
+
+	t.Run("Should print elements as written in raw mode", func(t *testing.T) {
+		code, stdout, _ := runPom(t, "get", "-f", path, "-raw", "dependencies/dependency[org.slf4j:slf4j-api:jar:]/version")
+
+		if code != 0 || stdout != "${slf4j.version}\n" {
+			t.Errorf("Expected the expression, but found: %d %q", code, stdout)
+		}
+	})
+
+	t.Run("Should fail on unknown expressions", func(t *testing.T) {
+		if code, _, _ := runPom(t, "get", "-f", path, "project.unknown"); code != 1 {
+			t.Errorf("Expected exit status 1, but found: %d", code)
+		}
+	})
+}
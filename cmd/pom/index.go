@@ -0,0 +1,212 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/obscurelyme/encoding/pom"
+	"github.com/obscurelyme/encoding/pom/index"
+	"github.com/obscurelyme/encoding/pom/resolve"
+)
+
+// indexFlags are the flags of the commands querying the class index.
+type indexFlags struct {
+	path *string
+}
+
+func addIndexFlags(flags *flag.FlagSet) *indexFlags {
+	return &indexFlags{path: flags.String("index", "", "the index `file`, in the user cache directory by default")}
+}
+
+// open returns the index of repository, updated and saved.
+func (f *indexFlags) open(repository pom.LocalRepository) (*index.Index, index.Stats, error) {
+	path := *f.path
+	if path == "" {
+		cache, err := os.UserCacheDir()
+		if err != nil {
+			return nil, index.Stats{}, err
+		}
+		path = filepath.Join(cache, "pom", "index.json")
+	}
+
+	ix, err := index.Load(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		ix = index.New(repository)
+	case err != nil:
+		return nil, index.Stats{}, err
+	case ix.Dir != repository.Dir:
+		// The index is of another repository.
+		ix = index.New(repository)
+	}
+
+	stats, err := ix.Update()
+	if err != nil {
+		return nil, stats, err
+	}
+	return ix, stats, ix.Save(path)
+}
+
+// repositoryFlag adds the -repo flag of the commands that do not read a POM.
+func repositoryFlag(flags *flag.FlagSet) func() (pom.LocalRepository, error) {
+	dir := flags.String("repo", "", "the local repository `dir`, ~/.m2/repository by default")
+	return func() (pom.LocalRepository, error) {
+		if *dir != "" {
+			return pom.LocalRepository{Dir: *dir}, nil
+		}
+		return pom.DefaultLocalRepository()
+	}
+}
+
+func runIndex(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("index", "", stderr)
+	repository := repositoryFlag(flags)
+	ixFlags := addIndexFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+
+	repo, err := repository()
+	if err != nil {
+		fmt.Fprintf(stderr, "pom index: %s\n", err)
+		return 2
+	}
+	ix, stats, err := ixFlags.open(repo)
+	if err != nil {
+		fmt.Fprintf(stderr, "pom index: %s\n", err)
+		return 2
+	}
+	for _, err := range ix.Errors {
+		fmt.Fprintf(stderr, "pom index: skipped %s\n", err)
+	}
+	fmt.Fprintf(stdout, "%d jars indexed, %d unchanged, %d removed", stats.Indexed, stats.Unchanged, stats.Removed)
+	if stats.Failed > 0 {
+		fmt.Fprintf(stdout, ", %d failed", stats.Failed)
+	}
+	fmt.Fprintln(stdout)
+	return 0
+}
+
+func runWhich(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("which", "class-or-package ...", stderr)
+	repository := repositoryFlag(flags)
+	ixFlags := addIndexFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	repo, err := repository()
+	if err != nil {
+		fmt.Fprintf(stderr, "pom which: %s\n", err)
+		return 2
+	}
+	ix, _, err := ixFlags.open(repo)
+	if err != nil {
+		fmt.Fprintf(stderr, "pom which: %s\n", err)
+		return 2
+	}
+
+	status := 0
+	for _, name := range flags.Args() {
+		files := ix.Class(name)
+		if len(files) == 0 {
+			files = ix.Package(name)
+		}
+		if len(files) == 0 {
+			fmt.Fprintf(stderr, "pom which: %s: not found\n", name)
+			status = 1
+		}
+		for _, f := range files {
+			fmt.Fprintf(stdout, "%s: %s\n", name, f.Artifact)
+		}
+	}
+	return status
+}
+
+func runDuplicates(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("duplicates", "", stderr)
+	model := addModelFlags(flags)
+	ixFlags := addIndexFlags(flags)
+	scope := flags.String("scope", "test", "the `scope` of the classpath, like for classpath")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+	pathScope, err := resolve.ParsePathScope(*scope)
+	if err != nil {
+		fmt.Fprintf(stderr, "pom duplicates: %s\n", err)
+		return 2
+	}
+
+	root, err := model.resolve(false)
+	if err != nil {
+		fmt.Fprintf(stderr, "pom duplicates: %s\n", err)
+		return 2
+	}
+	repository, err := model.resolver()
+	if err != nil {
+		fmt.Fprintf(stderr, "pom duplicates: %s\n", err)
+		return 2
+	}
+	cp, err := root.Classpath(pathScope, &resolve.ClasspathOptions{Repository: repository, Basedir: filepath.Dir(*model.file)})
+	if err != nil {
+		fmt.Fprintf(stderr, "pom duplicates: %s\n", err)
+		return 2
+	}
+	ix, _, err := ixFlags.open(repository)
+	if err != nil {
+		fmt.Fprintf(stderr, "pom duplicates: %s\n", err)
+		return 2
+	}
+	duplicates, err := ix.Duplicates(cp)
+	if err != nil {
+		fmt.Fprintf(stderr, "pom duplicates: %s\n", err)
+		return 2
+	}
+
+	// Group the classes by the entries they are found in.
+	var groups []string
+	classes := make(map[string][]string)
+	for _, d := range duplicates {
+		var names []string
+		for _, e := range d.Entries {
+			if e.Node != nil {
+				names = append(names, e.Node.String())
+			} else {
+				names = append(names, e.Path)
+			}
+		}
+		group := strings.Join(names, ", ")
+		if classes[group] == nil {
+			groups = append(groups, group)
+		}
+		classes[group] = append(classes[group], d.Class)
+	}
+	for _, group := range groups {
+		fmt.Fprintf(stdout, "%s:\n", group)
+		for _, class := range classes[group] {
+			fmt.Fprintf(stdout, "   %s\n", class)
+		}
+	}
+	if len(duplicates) > 0 {
+		return 1
+	}
+	return 0
+}
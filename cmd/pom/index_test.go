@@ -0,0 +1,43 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestIndex(t *testing.T) {
+	testdata := filepath.Join("..", "..", "pom", "analyze", "testdata")
+	repository := filepath.Join(testdata, "repository")
+	ix := filepath.Join(t.TempDir(), "index.json")
+
+	t.Run("Should index the repository incrementally", func(t *testing.T) {
+		code, stdout, stderr := runPom(t, "index", "-repo", repository, "-index", ix)
+		if code != 0 || stdout != "5 jars indexed, 0 unchanged, 0 removed\n" {
+			t.Errorf("Expected 5 jars indexed, but found: %d %s %s", code, stdout, stderr)
+		}
+
+		code, stdout, stderr = runPom(t, "index", "-repo", repository, "-index", ix)
+		if code != 0 || stdout != "0 jars indexed, 5 unchanged, 0 removed\n" {
+			t.Errorf("Expected 5 jars unchanged, but found: %d %s %s", code, stdout, stderr)
+		}
+	})
+
+	t.Run("Should print the artifacts of classes and packages", func(t *testing.T) {
+		code, stdout, stderr := runPom(t, "which", "-repo", repository, "-index", ix, "org.example.impl.Impl", "org.example.junit")
+
+		if code != 0 || stdout != "org.example.impl.Impl: org.example:impl:jar:1.0\norg.example.junit: org.example:junit:jar:1.0\n" {
+			t.Errorf("Expected impl and junit, but found: %d %s %s", code, stdout, stderr)
+		}
+		if code, _, _ := runPom(t, "which", "-repo", repository, "-index", ix, "org.example.Missing"); code != 1 {
+			t.Errorf("Expected exit status 1, but found: %d", code)
+		}
+	})
+
+	t.Run("Should find no duplicates", func(t *testing.T) {
+		code, stdout, stderr := runPom(t, "duplicates", "-f", filepath.Join(testdata, "pom.xml"), "-repo", repository, "-index", ix)
+
+		if code != 0 || stdout != "" {
+			t.Errorf("Expected no duplicates, but found: %d %s %s", code, stdout, stderr)
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/obscurelyme/encoding/pom"
+	"github.com/obscurelyme/encoding/pom/lint"
+)
+
+func runLint(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("lint", "[file ...]", stderr)
+	sarif := flags.Bool("sarif", false, "write the findings as a SARIF log")
+	severities := flags.String("severity", "", "comma separated rule=severity overrides, like unused-property=off")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	l := &lint.Linter{Severity: make(map[string]lint.Severity)}
+	rules := make(map[string]bool)
+	for _, r := range lint.DefaultRules() {
+		rules[r.ID()] = true
+	}
+	for _, override := range strings.FieldsFunc(*severities, func(r rune) bool { return r == ',' }) {
+		rule, name, ok := strings.Cut(override, "=")
+		severity, err := lint.ParseSeverity(name)
+		if !ok || err != nil {
+			fmt.Fprintf(stderr, "pom lint: invalid severity %q\n", override)
+			return 2
+		}
+		if !rules[rule] {
+			fmt.Fprintf(stderr, "pom lint: unknown rule %q\n", rule)
+			return 2
+		}
+		l.Severity[rule] = severity
+	}
+
+	status := 0
+	var reports []lint.Report
+	for _, file := range files(flags) {
+		m, err := pom.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(stderr, "pom lint: %s\n", err)
+			status = 2
+			continue
+		}
+
+		findings := l.Lint(m)
+		for _, f := range findings {
+			if f.Severity == lint.SeverityError {
+				status = max(status, 1)
+			}
+			if !*sarif {
+				fmt.Fprintf(stdout, "%s: %s\n", file, f)
+			}
+		}
+		reports = append(reports, lint.Report{URI: file, Findings: findings})
+	}
+
+	if *sarif {
+		if err := l.WriteSARIF(stdout, reports...); err != nil {
+			fmt.Fprintf(stderr, "pom lint: %s\n", err)
+			return 2
+		}
+	}
+	return status
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	path := filepath.Join("..", "..", "pom", "lint", "testdata", "pom.xml")
+
+	t.Run("Should print findings and fail on errors", func(t *testing.T) {
+		code, stdout, _ := runPom(t, "lint", path)
+
+		if code != 1 || !strings.Contains(stdout, path+": error: /project/repositories/repository[internal]: ") {
+			t.Errorf("Expected exit status 1 and the insecure repository, but found: %d %s", code, stdout)
+		}
+	})
+
+	t.Run("Should apply severity overrides", func(t *testing.T) {
+		code, stdout, _ := runPom(t, "lint", "-severity", "snapshot-dependency=warning,insecure-repository=off", path)
+
+		if code != 0 || strings.Contains(stdout, "insecure-repository") {
+			t.Errorf("Expected exit status 0 without the disabled rule, but found: %d %s", code, stdout)
+		}
+		if code, _, _ := runPom(t, "lint", "-severity", "unused-property", path); code != 2 {
+			t.Errorf("Expected exit status 2 for an invalid override, but found: %d", code)
+		}
+		if code, _, stderr := runPom(t, "lint", "-severity", "snapshot-dependecy=off", path); code != 2 || !strings.Contains(stderr, `unknown rule "snapshot-dependecy"`) {
+			t.Errorf("Expected exit status 2 for an unknown rule, but found: %d %s", code, stderr)
+		}
+	})
+
+	t.Run("Should write SARIF", func(t *testing.T) {
+		_, stdout, _ := runPom(t, "lint", "-sarif", path)
+
+		var log map[string]any
+		if err := json.Unmarshal([]byte(stdout), &log); err != nil || log["version"] != "2.1.0" {
+			t.Errorf("Expected a SARIF log, but found: %s", stdout)
+		}
+	})
+}
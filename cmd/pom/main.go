@@ -0,0 +1,128 @@
+// Command pom reads and rewrites Maven pom.xml files without a JVM.
+//
+// Usage:
+//
+//	pom <command> [flags] [arguments]
+//
+// The commands are:
+//
+//	get                print the value of an expression, like project.version
+//	set                set the text of an element
+//	add-dependency     add a dependency
+//	remove-dependency  remove a dependency
+//	bump-version       increment or set the version of the project
+//	set-version        set the version of all the projects of a reactor
+//	release            prepare a release of a reactor, or continue after it
+//	check-versions     check the use of CI-friendly versions in a reactor
+//	effective          print the effective POM
+//	plan               print the plugin executions a build runs up to a phase
+//	check-plugins      check the configuration of plugins against their descriptors
+//	validate           check POMs against the schema
+//	fmt                rewrite POMs in canonical form
+//	lint               check POMs against the lint rules
+//	tree               print the dependency tree
+//	classpath          print the classpath of a scope
+//	enforce            check a POM and its dependencies against enforcer rules
+//	analyze            find used undeclared and unused declared dependencies
+//	index              index the classes of the local repository
+//	which              print the artifacts providing a class or package
+//	duplicates         print the classes found more than once on a classpath
+//	embedded           print the POMs embedded in archives and their nested archives
+//	diff               print the semantic differences between two POMs
+//
+// Commands that change a POM only rewrite the elements they concern, keeping
+// the formatting and comments of the rest of the document.
+//
+// Run pom <command> -h for the flags of a command. The exit status is 0 on
+// success, 1 when a check fails and 2 on errors.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// command is a subcommand of pom.
+type command struct {
+	name  string
+	short string
+	run   func(args []string, stdout, stderr io.Writer) int
+}
+
+var commands = []command{
+	{name: "get", short: "print the value of an expression, like project.version", run: runGet},
+	{name: "set", short: "set the text of an element", run: runSet},
+	{name: "add-dependency", short: "add a dependency", run: runAddDependency},
+	{name: "remove-dependency", short: "remove a dependency", run: runRemoveDependency},
+	{name: "bump-version", short: "increment or set the version of the project", run: runBumpVersion},
+	{name: "set-version", short: "set the version of all the projects of a reactor", run: runSetVersion},
+	{name: "release", short: "prepare a release of a reactor, or continue after it", run: runRelease},
+	{name: "check-versions", short: "check the use of CI-friendly versions in a reactor", run: runCheckVersions},
+	{name: "effective", short: "print the effective POM", run: runEffective},
+	{name: "plan", short: "print the plugin executions a build runs up to a phase", run: runPlan},
+	{name: "check-plugins", short: "check the configuration of plugins against their descriptors", run: runCheckPlugins},
+	{name: "validate", short: "check POMs against the schema", run: runValidate},
+	{name: "fmt", short: "rewrite POMs in canonical form", run: runFmt},
+	{name: "lint", short: "check POMs against the lint rules", run: runLint},
+	{name: "tree", short: "print the dependency tree", run: runTree},
+	{name: "classpath", short: "print the classpath of a scope", run: runClasspath},
+	{name: "enforce", short: "check a POM and its dependencies against enforcer rules", run: runEnforce},
+	{name: "analyze", short: "find used undeclared and unused declared dependencies", run: runAnalyze},
+	{name: "index", short: "index the classes of the local repository", run: runIndex},
+	{name: "which", short: "print the artifacts providing a class or package", run: runWhich},
+	{name: "duplicates", short: "print the classes found more than once on a classpath", run: runDuplicates},
+	{name: "embedded", short: "print the POMs embedded in archives and their nested archives", run: runEmbedded},
+	{name: "diff", short: "print the semantic differences between two POMs", run: runDiff},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(args[1:], stdout, stderr)
+		}
+	}
+
+	fmt.Fprintf(stderr, "pom: unknown command %q\n", args[0])
+	usage(stderr)
+	return 2
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: pom <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "The commands are:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-18s %s\n", c.name, c.short)
+	}
+}
+
+// newFlagSet returns the flags of a command, which print their usage to
+// stderr.
+func newFlagSet(name, arguments string, stderr io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet("pom "+name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: pom %s [flags] %s\n", name, arguments)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// files returns the files named on the command line, pom.xml by default.
+func files(flags *flag.FlagSet) []string {
+	if flags.NArg() == 0 {
+		return []string{"pom.xml"}
+	}
+	return flags.Args()
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// copyTestdata copies a file of the pom package testdata into a temporary
+// directory and returns its path there.
+func copyTestdata(t *testing.T, name string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("..", "..", "pom", "testdata", name))
+	if err != nil {
+		t.Fatalf("Expected no errors reading %s, but found: %s", name, err.Error())
+	}
+	path := filepath.Join(t.TempDir(), "pom.xml")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("Expected no errors writing %s, but found: %s", path, err.Error())
+	}
+	return path
+}
+
+func runPom(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun(t *testing.T) {
+	t.Run("Should reject unknown commands", func(t *testing.T) {
+		code, _, stderr := runPom(t, "frobnicate")
+
+		if code != 2 || !strings.Contains(stderr, "unknown command") {
+			t.Errorf("Expected exit status 2 and an error, but found: %d %s", code, stderr)
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+// executionJSON is the JSON form of a pom.MojoExecution.
+type executionJSON struct {
+	Phase       string `json:"phase"`
+	GroupId     string `json:"groupId"`
+	ArtifactId  string `json:"artifactId"`
+	Version     string `json:"version"`
+	Goal        string `json:"goal"`
+	ExecutionId string `json:"executionId"`
+	// Configuration is the configuration element as XML.
+	Configuration string `json:"configuration,omitempty"`
+}
+
+func runPlan(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("plan", "phase", stderr)
+	model := addModelFlags(flags)
+	mavenVersion := flags.String("maven-version", "3.9.9", "the Maven `version` whose Super POM and lifecycle bindings apply")
+	asJSON := flags.Bool("json", false, "write the executions with their configuration as JSON")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	executions, err := plan(model, flags.Arg(0), *mavenVersion)
+	if err != nil {
+		fmt.Fprintf(stderr, "pom plan: %s\n", err)
+		return 2
+	}
+
+	if !*asJSON {
+		for i := range executions {
+			fmt.Fprintf(stdout, "%-24s %s\n", executions[i].Phase, &executions[i])
+		}
+		return 0
+	}
+
+	found := []executionJSON{}
+	for _, e := range executions {
+		x := executionJSON{Phase: e.Phase, GroupId: e.GroupId, ArtifactId: e.ArtifactId, Version: e.Version, Goal: e.Goal, ExecutionId: e.ExecutionId}
+		if e.Configuration != nil {
+			data, err := xml.Marshal(e.Configuration)
+			if err != nil {
+				fmt.Fprintf(stderr, "pom plan: %s\n", err)
+				return 2
+			}
+			x.Configuration = string(data)
+		}
+		found = append(found, x)
+	}
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(found); err != nil {
+		fmt.Fprintf(stderr, "pom plan: %s\n", err)
+		return 2
+	}
+	return 0
+}
+
+// plan returns the executions of a build up to phase of the effective model
+// of the POM selected by the flags, with the Super POM of mavenVersion.
+func plan(model *modelFlags, phase, mavenVersion string) ([]pom.MojoExecution, error) {
+	var err error
+	if model.superPOM, err = pom.SuperPOM(mavenVersion); err != nil {
+		return nil, err
+	}
+	m, err := model.effective()
+	if err != nil {
+		return nil, err
+	}
+	return m.Plan(phase, &pom.PlanOptions{MavenVersion: mavenVersion})
+}
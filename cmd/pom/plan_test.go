@@ -0,0 +1,39 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPlan(t *testing.T) {
+	testdata := filepath.Join("..", "..", "pom", "testdata", "effective")
+	args := []string{"-f", filepath.Join(testdata, "core", "pom.xml"), "-repo", filepath.Join(testdata, "repository")}
+
+	t.Run("Should print the executions up to the phase", func(t *testing.T) {
+		code, stdout, stderr := runPom(t, append(append([]string{"plan"}, args...), "test")...)
+
+		lines := strings.Split(strings.TrimSpace(stdout), "\n")
+		if code != 0 || len(lines) != 6 || lines[5] != "test                     org.apache.maven.plugins:maven-surefire-plugin:3.2.5:test (integration)" {
+			t.Errorf("Expected the executions up to test, but found: %d %s %s", code, stdout, stderr)
+		}
+	})
+
+	t.Run("Should write JSON with the configuration", func(t *testing.T) {
+		code, stdout, stderr := runPom(t, append(append([]string{"plan", "-json"}, args...), "compile")...)
+
+		var found []map[string]string
+		if err := json.Unmarshal([]byte(stdout), &found); err != nil || code != 0 || len(found) != 2 || !strings.Contains(found[1]["configuration"], "<release>17</release>") {
+			t.Errorf("Expected the compile execution with its configuration, but found: %d %s %s", code, stdout, stderr)
+		}
+	})
+
+	t.Run("Should reject unknown phases", func(t *testing.T) {
+		code, _, stderr := runPom(t, append(append([]string{"plan"}, args...), "compiled")...)
+
+		if code != 2 || !strings.HasPrefix(stderr, "pom plan: ") {
+			t.Errorf("Expected exit status 2, but found: %d %s", code, stderr)
+		}
+	})
+}
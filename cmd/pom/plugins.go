@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/obscurelyme/encoding/pom"
+	"github.com/obscurelyme/encoding/pom/descriptor"
+)
+
+func runCheckPlugins(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("check-plugins", "", stderr)
+	model := addModelFlags(flags)
+	mavenVersion := flags.String("maven-version", "3.9.9", "the Maven `version` whose Super POM and lifecycle plugin versions apply")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+
+	var err error
+	if model.superPOM, err = pom.SuperPOM(*mavenVersion); err != nil {
+		fmt.Fprintf(stderr, "pom check-plugins: %s\n", err)
+		return 2
+	}
+	m, err := model.effective()
+	if err != nil {
+		fmt.Fprintf(stderr, "pom check-plugins: %s\n", err)
+		return 2
+	}
+	repository, err := model.resolver()
+	if err != nil {
+		fmt.Fprintf(stderr, "pom check-plugins: %s\n", err)
+		return 2
+	}
+	if m.Build == nil || m.Build.Plugins == nil {
+		return 0
+	}
+
+	bindings, err := pom.LifecycleBindings(m.Packaging, *mavenVersion)
+	if err != nil {
+		fmt.Fprintf(stderr, "pom check-plugins: %s\n", err)
+		return 2
+	}
+	versions := make(map[string]string)
+	for _, b := range bindings {
+		versions[b.GroupId+":"+b.ArtifactId] = b.Version
+	}
+
+	status := 0
+	r := descriptor.NewResolver(repository)
+	for i := range m.Build.Plugins.Plugin {
+		p := &m.Build.Plugins.Plugin[i]
+		if p.Version == "" {
+			p.Version = versions[p.Key()]
+		}
+		d, err := r.Lookup(p)
+		if err != nil {
+			fmt.Fprintf(stderr, "pom check-plugins: %s\n", err)
+			status = 2
+			continue
+		}
+		for _, problem := range d.Validate(p) {
+			fmt.Fprintf(stdout, "%s: %s\n", p.Key(), problem)
+			status = max(status, 1)
+		}
+	}
+	return status
+}
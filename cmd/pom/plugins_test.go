@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckPlugins(t *testing.T) {
+	testdata := filepath.Join("..", "..", "pom", "descriptor", "testdata")
+
+	t.Run("Should print the problems of the configuration of plugins", func(t *testing.T) {
+		code, stdout, stderr := runPom(t, "check-plugins", "-f", filepath.Join(testdata, "pom.xml"), "-repo", filepath.Join(testdata, "repository"))
+
+		lines := strings.Split(strings.TrimSpace(stdout), "\n")
+		if code != 1 || len(lines) != 6 || lines[5] != "org.example:example-maven-plugin: template of generate (default): missing required parameter" {
+			t.Errorf("Expected 6 problems, but found: %d %s %s", code, stdout, stderr)
+		}
+	})
+
+	t.Run("Should give the lifecycle versions to plugins without one", func(t *testing.T) {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, "pom.xml"), []byte(`<project>
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>org.example</groupId>
+  <artifactId>example</artifactId>
+  <version>1.0</version>
+  <build>
+    <plugins>
+      <plugin>
+        <artifactId>maven-compiler-plugin</artifactId>
+        <configuration>
+          <release>17</release>
+          <fork>true</fork>
+        </configuration>
+      </plugin>
+    </plugins>
+  </build>
+</project>`), 0o644)
+
+		code, stdout, stderr := runPom(t, "check-plugins", "-f", filepath.Join(dir, "pom.xml"), "-repo", filepath.Join(testdata, "repository"))
+
+		if code != 1 || stdout != "org.apache.maven.plugins:maven-compiler-plugin: fork: unknown parameter\n" {
+			t.Errorf("Expected the problems of the compiler plugin 3.11.0, but found: %d %s %s", code, stdout, stderr)
+		}
+	})
+
+	t.Run("Should fail on plugins without a descriptor", func(t *testing.T) {
+		code, _, stderr := runPom(t, "check-plugins", "-f", filepath.Join(testdata, "pom.xml"), "-repo", t.TempDir())
+
+		if code != 2 || !strings.HasPrefix(stderr, "pom check-plugins: ") {
+			t.Errorf("Expected exit status 2, but found: %d %s", code, stderr)
+		}
+	})
+}
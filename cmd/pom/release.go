@@ -0,0 +1,238 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"sort"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+func runSetVersion(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("set-version", "major|minor|patch|release|next|VERSION", stderr)
+	file := flags.String("f", "pom.xml", "the root POM `file` of the reactor")
+	dryRun := flags.Bool("dry-run", false, "print the changes instead of writing them")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	r, err := pom.LoadReactor(*file)
+	if err != nil {
+		fmt.Fprintf(stderr, "pom set-version: %s\n", err)
+		return 2
+	}
+	current, err := r.Version()
+	if err != nil {
+		fmt.Fprintf(stderr, "pom set-version: %s\n", err)
+		return 2
+	}
+	version, err := nextVersion(current, flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "pom set-version: %s\n", err)
+		return 2
+	}
+	if err := r.SetVersion(version); err != nil {
+		fmt.Fprintf(stderr, "pom set-version: %s\n", err)
+		return 2
+	}
+
+	if err := finish(r, nil, *dryRun, stdout); err != nil {
+		fmt.Fprintf(stderr, "pom set-version: %s\n", err)
+		return 2
+	}
+	if !*dryRun {
+		fmt.Fprintln(stdout, version)
+	}
+	return 0
+}
+
+func runRelease(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("release", "", stderr)
+	file := flags.String("f", "pom.xml", "the root POM `file` of the reactor")
+	dryRun := flags.Bool("dry-run", false, "print the changes of both steps instead of writing them")
+	resume := flags.Bool("continue", false, "set the development version once the release is tagged")
+	releaseVersion := flags.String("release-version", "", "the `version` to release, the current one without -SNAPSHOT by default")
+	developmentVersion := flags.String("development-version", "", "the `version` to continue with, the next SNAPSHOT by default")
+	tag := flags.String("tag", "", "the scm `tag` of the release, artifactId-version by default")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+
+	r, err := pom.LoadReactor(*file)
+	if err != nil {
+		fmt.Fprintf(stderr, "pom release: %s\n", err)
+		return 2
+	}
+
+	// baseline holds the properties of maven.config the changes are
+	// printed against, nil for those of the file
+	var baseline map[string]string
+	release := &pom.Release{DevelopmentVersion: *developmentVersion}
+	if !*resume {
+		release, err = r.PrepareRelease(&pom.ReleaseOptions{
+			ReleaseVersion:     *releaseVersion,
+			DevelopmentVersion: *developmentVersion,
+			Tag:                *tag,
+		})
+		if err != nil {
+			fmt.Fprintf(stderr, "pom release: %s\n", err)
+			return 2
+		}
+		if !*dryRun {
+			if err := r.Write(); err != nil {
+				fmt.Fprintf(stderr, "pom release: %s\n", err)
+				return 2
+			}
+			fmt.Fprintf(stdout, "prepared %s, tag it %s then run pom release -continue -development-version %s\n", release.Version, release.Tag, release.DevelopmentVersion)
+			return 0
+		}
+
+		fmt.Fprintf(stdout, "# release %s, tag %s\n", release.Version, release.Tag)
+		if err := printChanges(r, nil, stdout); err != nil {
+			fmt.Fprintf(stderr, "pom release: %s\n", err)
+			return 2
+		}
+		if r.Config != nil {
+			baseline = maps.Clone(r.Config.Properties)
+		}
+		for _, p := range r.Projects {
+			if p.Model, err = p.Document.Model(); err != nil {
+				fmt.Fprintf(stderr, "pom release: %s\n", err)
+				return 2
+			}
+		}
+		fmt.Fprintf(stdout, "# development %s\n", release.DevelopmentVersion)
+	}
+
+	if release.DevelopmentVersion == "" {
+		current, err := r.Version()
+		if err != nil {
+			fmt.Fprintf(stderr, "pom release: %s\n", err)
+			return 2
+		}
+		if release.DevelopmentVersion, err = pom.NextDevelopmentVersion(current); err != nil {
+			fmt.Fprintf(stderr, "pom release: %s\n", err)
+			return 2
+		}
+	}
+	if err := r.ContinueDevelopment(release); err != nil {
+		fmt.Fprintf(stderr, "pom release: %s\n", err)
+		return 2
+	}
+	if err := finish(r, baseline, *dryRun, stdout); err != nil {
+		fmt.Fprintf(stderr, "pom release: %s\n", err)
+		return 2
+	}
+	if !*dryRun && *resume {
+		fmt.Fprintln(stdout, release.DevelopmentVersion)
+	}
+	return 0
+}
+
+func runCheckVersions(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("check-versions", "", stderr)
+	file := flags.String("f", "pom.xml", "the root POM `file` of the reactor")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+
+	r, err := pom.LoadReactor(*file)
+	if err != nil {
+		fmt.Fprintf(stderr, "pom check-versions: %s\n", err)
+		return 2
+	}
+	issues, err := r.CheckVersions()
+	if err != nil {
+		fmt.Fprintf(stderr, "pom check-versions: %s\n", err)
+		return 2
+	}
+	for _, i := range issues {
+		fmt.Fprintln(stdout, i)
+	}
+	if len(issues) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// finish writes the edits of r, or prints them in dry-run mode against
+// baseline, like printChanges.
+func finish(r *pom.Reactor, baseline map[string]string, dryRun bool, stdout io.Writer) error {
+	if dryRun {
+		return printChanges(r, baseline, stdout)
+	}
+	return r.Write()
+}
+
+// printChanges prints the changes of the modified projects and maven.config
+// of r, under the path of each. The properties of maven.config are compared
+// with baseline, the properties after the previous step, or with those of
+// the file when nil.
+func printChanges(r *pom.Reactor, baseline map[string]string, w io.Writer) error {
+	if c := r.Config; c != nil && (baseline != nil || c.Modified()) {
+		if baseline == nil {
+			original, err := pom.ReadMavenConfig(c.Path)
+			if err != nil {
+				return err
+			}
+			baseline = original.Properties
+		}
+		var names []string
+		for name, value := range c.Properties {
+			if old, ok := baseline[name]; !ok || value != old {
+				names = append(names, name)
+			}
+		}
+		for name := range baseline {
+			if _, ok := c.Properties[name]; !ok {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		if len(names) > 0 {
+			fmt.Fprintf(w, "%s:\n", c.Path)
+		}
+		for _, name := range names {
+			old, inBaseline := baseline[name]
+			value, inConfig := c.Properties[name]
+			switch {
+			case !inBaseline:
+				fmt.Fprintf(w, "+ property %s %s\n", name, value)
+			case !inConfig:
+				fmt.Fprintf(w, "- property %s %s\n", name, old)
+			default:
+				fmt.Fprintf(w, "~ property %s %s -> %s\n", name, old, value)
+			}
+		}
+	}
+	for _, p := range r.Projects {
+		if !p.Modified() {
+			continue
+		}
+		changes, err := p.Changes()
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%s:\n", p.Path)
+		if err := changes.WriteText(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
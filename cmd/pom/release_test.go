@@ -0,0 +1,151 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+// copyReactor copies the reactor of the pom package testdata into a
+// temporary directory and returns the path of its root POM there.
+func copyReactor(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	src := filepath.Join("..", "..", "pom", "testdata", "reactor")
+	err := filepath.WalkDir(src, func(path string, e fs.DirEntry, err error) error {
+		if err != nil || e.IsDir() {
+			return err
+		}
+		rel, _ := filepath.Rel(src, path)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(rel)), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dir, rel), data, 0o644)
+	})
+	if err != nil {
+		t.Fatalf("Expected no errors copying the reactor, but found: %s", err.Error())
+	}
+	return filepath.Join(dir, "pom.xml")
+}
+
+func TestSetVersion(t *testing.T) {
+	t.Run("Should print the changes in dry-run mode", func(t *testing.T) {
+		path := copyReactor(t)
+		original, _ := os.ReadFile(path)
+
+		code, stdout, stderr := runPom(t, "set-version", "-f", path, "-dry-run", "minor")
+
+		if code != 0 || !strings.Contains(stdout, "~ dependency org.example:core version: 1.0.0-SNAPSHOT -> 1.1.0-SNAPSHOT") {
+			t.Errorf("Expected the changes, but found: %d %s %s", code, stdout, stderr)
+		}
+		if data, _ := os.ReadFile(path); string(data) != string(original) {
+			t.Errorf("Expected the POM to be left alone, but found:\n%s", data)
+		}
+	})
+
+	t.Run("Should write the version of every module", func(t *testing.T) {
+		path := copyReactor(t)
+
+		if code, stdout, stderr := runPom(t, "set-version", "-f", path, "2.0.0-SNAPSHOT"); code != 0 || stdout != "2.0.0-SNAPSHOT\n" {
+			t.Fatalf("Expected exit status 0, but found: %d %s %s", code, stdout, stderr)
+		}
+		data, _ := os.ReadFile(filepath.Join(filepath.Dir(path), "core", "pom.xml"))
+		if !strings.Contains(string(data), "<version>2.0.0-SNAPSHOT</version>") {
+			t.Errorf("Expected the parent version of core to be set, but found:\n%s", data)
+		}
+	})
+}
+
+func TestRelease(t *testing.T) {
+	t.Run("Should print both steps in dry-run mode", func(t *testing.T) {
+		path := copyReactor(t)
+
+		code, stdout, stderr := runPom(t, "release", "-f", path, "-dry-run", "-tag", "v1.0.0")
+
+		if code != 0 || !strings.Contains(stdout, "# release 1.0.0, tag v1.0.0\n") || !strings.Contains(stdout, "# development 1.0.1-SNAPSHOT\n") {
+			t.Errorf("Expected both steps, but found: %d %s %s", code, stdout, stderr)
+		}
+		if !strings.Contains(stdout, "~ scm tag: v1.0.0 -> HEAD") {
+			t.Errorf("Expected the tag to be reset, but found: %s", stdout)
+		}
+	})
+
+	t.Run("Should print each step of maven.config in dry-run mode", func(t *testing.T) {
+		dir := t.TempDir()
+		os.MkdirAll(filepath.Join(dir, ".mvn"), 0o755)
+		os.WriteFile(filepath.Join(dir, ".mvn", "maven.config"), []byte("-Drevision=1.0-SNAPSHOT\n"), 0o644)
+		os.WriteFile(filepath.Join(dir, "pom.xml"), []byte(`<project>
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>org.example</groupId>
+  <artifactId>example</artifactId>
+  <version>${revision}</version>
+</project>`), 0o644)
+
+		code, stdout, stderr := runPom(t, "release", "-f", filepath.Join(dir, "pom.xml"), "-dry-run")
+
+		release, development, _ := strings.Cut(stdout, "# development")
+		if code != 0 || !strings.Contains(release, "~ property revision 1.0-SNAPSHOT -> 1.0\n") || !strings.Contains(development, "~ property revision 1.0 -> 1.1-SNAPSHOT\n") {
+			t.Errorf("Expected the changes of each step, but found: %d %s %s", code, stdout, stderr)
+		}
+	})
+
+	t.Run("Should print the values maven.config gets back in dry-run mode", func(t *testing.T) {
+		path := filepath.Join("..", "..", "pom", "testdata", "cifriendly", "pom.xml")
+
+		code, stdout, stderr := runPom(t, "release", "-f", path, "-dry-run")
+
+		release, development, _ := strings.Cut(stdout, "# development")
+		if code != 0 || !strings.Contains(release, "~ property changelist -SNAPSHOT -> \n") || !strings.Contains(development, "~ property changelist  -> -SNAPSHOT\n") {
+			t.Errorf("Expected changelist to be cleared then restored, but found: %d %s %s", code, stdout, stderr)
+		}
+	})
+
+	t.Run("Should print the properties added and removed from maven.config", func(t *testing.T) {
+		r := &pom.Reactor{Config: &pom.MavenConfig{Path: "maven.config", Properties: map[string]string{"revision": "1.0", "added": "yes"}}}
+
+		var b strings.Builder
+		if err := printChanges(r, map[string]string{"revision": "1.0", "removed": "no"}, &b); err != nil {
+			t.Fatalf("Expected no errors, but found: %s", err.Error())
+		}
+		if expected := "maven.config:\n+ property added yes\n- property removed no\n"; b.String() != expected {
+			t.Errorf("Expected:\n%s\nbut found:\n%s", expected, b.String())
+		}
+	})
+
+	t.Run("Should prepare then continue", func(t *testing.T) {
+		path := copyReactor(t)
+
+		if code, stdout, stderr := runPom(t, "release", "-f", path); code != 0 || !strings.Contains(stdout, "prepared 1.0.0, tag it reactor-1.0.0") {
+			t.Fatalf("Expected the release to be prepared, but found: %d %s %s", code, stdout, stderr)
+		}
+		if code, stdout, stderr := runPom(t, "release", "-f", path, "-continue"); code != 0 || stdout != "1.0.1-SNAPSHOT\n" {
+			t.Fatalf("Expected the development version, but found: %d %s %s", code, stdout, stderr)
+		}
+		data, _ := os.ReadFile(path)
+		if !strings.Contains(string(data), "<version>1.0.1-SNAPSHOT</version>") || !strings.Contains(string(data), "<tag>HEAD</tag>") {
+			t.Errorf("Expected the development version and tag, but found:\n%s", data)
+		}
+	})
+}
+
+func TestCheckVersions(t *testing.T) {
+	path := filepath.Join("..", "..", "pom", "testdata", "cifriendly", "pom.xml")
+
+	code, stdout, stderr := runPom(t, "check-versions", "-f", path)
+
+	if code != 1 || !strings.Contains(stdout, filepath.Join("app", "pom.xml")+`: parent version "1.2.0-SNAPSHOT" should be`) {
+		t.Errorf("Expected exit status 1 and the issues of app, but found: %d %s %s", code, stdout, stderr)
+	}
+	if code, stdout, _ := runPom(t, "get", "-f", path, "-effective", "-repo", t.TempDir(), "project.version"); code != 0 || stdout != "1.2.0-SNAPSHOT\n" {
+		t.Errorf("Expected the version resolved with maven.config, but found: %d %s", code, stdout)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+func runSet(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("set", "path value", stderr)
+	file := flags.String("f", "pom.xml", "the POM `file`")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+
+	err := editFile(*file, func(d *pom.Document) error {
+		return d.Set(flags.Arg(0), flags.Arg(1))
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "pom set: %s\n", err)
+		return 2
+	}
+	return 0
+}
+
+// editFile applies edit to the POM at path and writes it back, keeping the
+// formatting of what edit does not change.
+func editFile(path string, edit func(d *pom.Document) error) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	d, err := pom.ParseDocument(data)
+	if err != nil {
+		return err
+	}
+	if err := edit(d); err != nil {
+		return err
+	}
+	return os.WriteFile(path, d.Bytes(), info.Mode().Perm())
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	t.Run("Should only change the element set", func(t *testing.T) {
+		path := copyTestdata(t, filepath.Join("document", "pom.xml"))
+		original, _ := os.ReadFile(path)
+
+		if code, _, stderr := runPom(t, "set", "-f", path, "properties/slf4j.version", "2.0.10"); code != 0 {
+			t.Fatalf("Expected exit status 0, but found: %d %s", code, stderr)
+		}
+
+		data, _ := os.ReadFile(path)
+		expected := strings.Replace(string(original), "2.0.9", "2.0.10", 1)
+		if string(data) != expected {
+			t.Errorf("Expected:\n%s\nbut found:\n%s", expected, data)
+		}
+	})
+
+	t.Run("Should fail on elements identified by a key", func(t *testing.T) {
+		path := copyTestdata(t, filepath.Join("document", "pom.xml"))
+
+		if code, _, _ := runPom(t, "set", "-f", path, "dependencies/dependency[com.google.guava:guava:jar:]/version", "1"); code != 2 {
+			t.Errorf("Expected exit status 2, but found: %d", code)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/obscurelyme/encoding/pom/resolve"
+)
+
+// treeWriters write dependency graphs by format name.
+var treeWriters = map[string]func(n *resolve.Node, w io.Writer) error{
+	"text":    (*resolve.Node).WriteText,
+	"json":    (*resolve.Node).WriteJSON,
+	"dot":     (*resolve.Node).WriteDOT,
+	"graphml": (*resolve.Node).WriteGraphML,
+	"mermaid": (*resolve.Node).WriteMermaid,
+}
+
+func runTree(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("tree", "", stderr)
+	model := addModelFlags(flags)
+	verbose := flags.Bool("verbose", false, "keep the dependencies omitted in favour of nearer ones")
+	format := flags.String("format", "text", "the output `format`: text, json, dot, graphml or mermaid")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+	if _, ok := treeWriters[*format]; !ok {
+		fmt.Fprintf(stderr, "pom tree: unknown format %q\n", *format)
+		return 2
+	}
+
+	root, err := model.resolve(*verbose)
+	if err != nil {
+		fmt.Fprintf(stderr, "pom tree: %s\n", err)
+		return 2
+	}
+	if err := treeWriters[*format](root, stdout); err != nil {
+		fmt.Fprintf(stderr, "pom tree: %s\n", err)
+		return 2
+	}
+	return 0
+}
+
+// resolve resolves the dependencies of the effective model of the POM
+// selected by the flags.
+func (f *modelFlags) resolve(verbose bool) (*resolve.Node, error) {
+	m, err := f.effective()
+	if err != nil {
+		return nil, err
+	}
+	repository, err := f.resolver()
+	if err != nil {
+		return nil, err
+	}
+	props, err := f.userProperties()
+	if err != nil {
+		return nil, err
+	}
+	return resolve.Resolve(m, &resolve.Options{Resolver: repository, Properties: props, Verbose: verbose})
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTree(t *testing.T) {
+	testdata := filepath.Join("..", "..", "pom", "resolve", "testdata")
+
+	code, stdout, stderr := runPom(t, "tree", "-f", filepath.Join(testdata, "pom.xml"), "-repo", filepath.Join(testdata, "repository"))
+
+	if code != 0 || !strings.HasPrefix(stdout, "org.example:app:jar:1.0.0\n+- org.example:web:jar:1.0:compile\n") {
+		t.Errorf("Expected the dependency tree, but found: %d %s %s", code, stdout, stderr)
+	}
+}
+
+func TestTreeFormats(t *testing.T) {
+	testdata := filepath.Join("..", "..", "pom", "resolve", "testdata")
+	args := []string{"tree", "-f", filepath.Join(testdata, "pom.xml"), "-repo", filepath.Join(testdata, "repository"), "-verbose"}
+
+	t.Run("Should write the verbose tree in the format asked for", func(t *testing.T) {
+		code, stdout, stderr := runPom(t, append(args, "-format", "mermaid")...)
+
+		if code != 0 || !strings.HasPrefix(stdout, "graph TD\n") || !strings.Contains(stdout, "omitted for conflict with 1.0") {
+			t.Errorf("Expected a Mermaid graph with the omitted dependencies, but found: %d %s %s", code, stdout, stderr)
+		}
+	})
+
+	t.Run("Should reject unknown formats", func(t *testing.T) {
+		if code, _, _ := runPom(t, append(args, "-format", "svg")...); code != 2 {
+			t.Errorf("Expected exit status 2, but found: %d", code)
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+func runValidate(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("validate", "[file ...]", stderr)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	status := 0
+	for _, file := range files(flags) {
+		problems, err := validate(file)
+		if err != nil {
+			fmt.Fprintf(stderr, "pom validate: %s\n", err)
+			status = 2
+			continue
+		}
+		for _, p := range problems {
+			fmt.Fprintln(stdout, p)
+			status = max(status, 1)
+		}
+	}
+	return status
+}
+
+// validate returns the problems of the POM at path: the elements and
+// attributes outside the schema and the missing coordinates.
+func validate(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	m := pom.New()
+	d := pom.NewDecoder(f)
+	if err := d.Decode(m); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	var problems []string
+	for _, u := range d.Unrecognized() {
+		kind := "element"
+		if u.Attr {
+			kind = "attribute"
+		}
+		problems = append(problems, fmt.Sprintf("%s:%d:%d: unrecognized %s %s", path, u.Line, u.Column, kind, u.Path))
+	}
+
+	if m.ArtifactId == "" {
+		problems = append(problems, path+": missing artifactId")
+	}
+	if m.Parent == nil {
+		if m.GroupId == "" {
+			problems = append(problems, path+": missing groupId")
+		}
+		if m.Version == "" {
+			problems = append(problems, path+": missing version")
+		}
+	}
+	return problems, nil
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("Should accept valid POMs", func(t *testing.T) {
+		if code, stdout, stderr := runPom(t, "validate", filepath.Join("..", "..", "pom", "testdata", "document", "pom.xml")); code != 0 {
+			t.Errorf("Expected exit status 0, but found: %d %s %s", code, stdout, stderr)
+		}
+	})
+
+	t.Run("Should report unrecognized elements and missing coordinates", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "pom.xml")
+		os.WriteFile(path, []byte("<project>\n  <modelVersion>4.0.0</modelVersion>\n  <artifactId>a</artifactId>\n  <dependancies/>\n</project>\n"), 0o644)
+
+		code, stdout, _ := runPom(t, "validate", path)
+
+		if code != 1 || !strings.Contains(stdout, path+":4:3: unrecognized element /project/dependancies") {
+			t.Errorf("Expected exit status 1 and the unrecognized element, but found: %d %s", code, stdout)
+		}
+		if !strings.Contains(stdout, path+": missing groupId") || !strings.Contains(stdout, path+": missing version") {
+			t.Errorf("Expected the missing coordinates, but found: %s", stdout)
+		}
+	})
+}
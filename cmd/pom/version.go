@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+var versionParts = map[string]pom.VersionPart{
+	"major": pom.Major,
+	"minor": pom.Minor,
+	"patch": pom.Incremental,
+}
+
+// nextVersion returns the version following current for arg: major, minor
+// or patch to increment that part, release to drop -SNAPSHOT, next for the
+// next development version, or the version itself.
+func nextVersion(current, arg string) (string, error) {
+	switch arg {
+	case "release":
+		return pom.ReleaseVersion(current), nil
+	case "next":
+		return pom.NextDevelopmentVersion(current)
+	case "major", "minor", "patch":
+		return pom.IncrementVersion(current, versionParts[arg])
+	}
+	return arg, nil
+}
+
+func runBumpVersion(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("bump-version", "major|minor|patch|release|next|VERSION", stderr)
+	file := flags.String("f", "pom.xml", "the POM `file`")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	var next string
+	err := editFile(*file, func(d *pom.Document) error {
+		current, ok := d.Get("version")
+		if !ok {
+			return errors.New("the project has no version of its own")
+		}
+
+		var err error
+		if next, err = nextVersion(current, flags.Arg(0)); err != nil {
+			return err
+		}
+		return d.Set("version", next)
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "pom bump-version: %s\n", err)
+		return 2
+	}
+	fmt.Fprintln(stdout, next)
+	return 0
+}
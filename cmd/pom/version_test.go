@@ -0,0 +1,26 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestBumpVersion(t *testing.T) {
+	path := copyTestdata(t, filepath.Join("document", "pom.xml"))
+
+	for _, step := range []struct{ arg, expected string }{
+		{"minor", "1.1.0-SNAPSHOT"},
+		{"patch", "1.1.1-SNAPSHOT"},
+		{"release", "1.1.1"},
+		{"major", "2.0.0"},
+		{"2.1.0-SNAPSHOT", "2.1.0-SNAPSHOT"},
+	} {
+		code, stdout, stderr := runPom(t, "bump-version", "-f", path, step.arg)
+		if code != 0 || stdout != step.expected+"\n" {
+			t.Fatalf("Expected %s after %s, but found: %d %q %s", step.expected, step.arg, code, stdout, stderr)
+		}
+		if _, version, _ := runPom(t, "get", "-f", path, "project.version"); version != step.expected+"\n" {
+			t.Errorf("Expected %s to be written, but found: %s", step.expected, version)
+		}
+	}
+}
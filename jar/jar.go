@@ -0,0 +1,110 @@
+// Package jar reads the classes of Java archives and class directories, and
+// the POMs embedded in archives by the Maven archiver.
+//
+// The class functions take an fs.FS, so that they work the same on a *zip.Reader
+// opened on a jar and on an os.DirFS of an output directory like
+// target/classes.
+package jar
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/obscurelyme/encoding/classfile"
+)
+
+const versionsDir = "META-INF/versions/"
+
+// ClassName returns the binary name of the class stored at the path of an
+// archive, like org.example.Main for org/example/Main.class, and whether
+// the path holds a class. Classes of multi-release archives, under
+// META-INF/versions/N/, have the name of the class they replace. Module
+// descriptors are not classes.
+func ClassName(p string) (string, bool) {
+	name, ok := strings.CutSuffix(p, ".class")
+	if !ok {
+		return "", false
+	}
+	if rest, ok := strings.CutPrefix(name, versionsDir); ok {
+		_, name, ok = strings.Cut(rest, "/")
+		if !ok {
+			return "", false
+		}
+	} else if strings.HasPrefix(name, "META-INF/") {
+		return "", false
+	}
+	if path.Base(name) == "module-info" {
+		return "", false
+	}
+	return classfile.BinaryName(name), true
+}
+
+// Classes returns the binary names of the classes of fsys, sorted.
+func Classes(fsys fs.FS) ([]string, error) {
+	seen := make(map[string]bool)
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name, ok := ClassName(p); ok && !d.IsDir() {
+			seen[name] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ReadClass decodes the class named name of fsys, the one for all Java
+// versions in a multi-release archive.
+func ReadClass(fsys fs.FS, name string) (*classfile.ClassFile, error) {
+	p := strings.ReplaceAll(name, ".", "/") + ".class"
+	f, err := fsys.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	c, err := classfile.Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", p, err)
+	}
+	return c, nil
+}
+
+// WalkClasses calls fn for each class file of fsys, in lexical order of
+// their paths, including those of every version of a multi-release archive.
+func WalkClasses(fsys fs.FS, fn func(name string, c *classfile.ClassFile) error) error {
+	return fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name, ok := ClassName(p)
+		if !ok || d.IsDir() {
+			return nil
+		}
+
+		f, err := fsys.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		c, err := classfile.Read(f)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		return fn(name, c)
+	})
+}
@@ -0,0 +1,69 @@
+package jar_test
+
+import (
+	"archive/zip"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/obscurelyme/encoding/classfile"
+	"github.com/obscurelyme/encoding/jar"
+)
+
+func openJar(t *testing.T) *zip.ReadCloser {
+	t.Helper()
+
+	r, err := zip.OpenReader(filepath.Join("testdata", "example.jar"))
+	if err != nil {
+		t.Fatalf("Expected no errors opening the jar, but found: %s", err.Error())
+	}
+	t.Cleanup(func() { r.Close() })
+	return r
+}
+
+func TestClassName(t *testing.T) {
+	for p, expected := range map[string]string{
+		"org/example/Main.class":                        "org.example.Main",
+		"org/example/Main$1.class":                      "org.example.Main$1",
+		"META-INF/versions/17/org/example/Main.class":   "org.example.Main",
+		"module-info.class":                             "",
+		"META-INF/versions/9/module-info.class":         "",
+		"META-INF/maven/org.example/app/pom.properties": "",
+		"org/example/messages.properties":               "",
+	} {
+		if name, ok := jar.ClassName(p); name != expected || ok != (expected != "") {
+			t.Errorf("Expected %q for %s, but found: %q %v", expected, p, name, ok)
+		}
+	}
+}
+
+func TestClasses(t *testing.T) {
+	r := openJar(t)
+
+	t.Run("Should list the classes once", func(t *testing.T) {
+		names, err := jar.Classes(r)
+		if err != nil || strings.Join(names, " ") != "org.example.a.A org.example.a.A$Inner" {
+			t.Errorf("Expected A and A$Inner, but found: %v %v", names, err)
+		}
+	})
+
+	t.Run("Should read the base version of a class", func(t *testing.T) {
+		c, err := jar.ReadClass(r, "org.example.a.A")
+		if err != nil || c.MajorVersion != 61 {
+			t.Errorf("Expected the Java 17 class, but found: %v %v", c, err)
+		}
+	})
+
+	t.Run("Should walk every version of the classes", func(t *testing.T) {
+		var refs []string
+		err := jar.WalkClasses(r, func(name string, c *classfile.ClassFile) error {
+			if name == "org.example.a.A" {
+				refs = append(refs, c.ReferencedClasses()...)
+			}
+			return nil
+		})
+		if err != nil || strings.Join(refs, " ") != "java.lang.Object org.example.c.C java.lang.Object org.example.b.B" {
+			t.Errorf("Expected the references of both versions of A, but found: %v %v", refs, err)
+		}
+	})
+}
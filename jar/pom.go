@@ -0,0 +1,254 @@
+package jar
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/obscurelyme/encoding/pom"
+	"github.com/obscurelyme/encoding/properties"
+)
+
+// EmbeddedPOM is a POM the Maven archiver stored in an archive, under
+// META-INF/maven/<groupId>/<artifactId>/, with its pom.properties.
+//
+// A nested archive that cannot be read is an EmbeddedPOM without Dir nor
+// coordinates, with the reason in Err.
+type EmbeddedPOM struct {
+	// Archive locates the archive with the POM. The path of a nested
+	// archive follows that of its outer archive after a !/ separator, like
+	// app.war!/WEB-INF/lib/library.jar.
+	Archive string
+	// Dir is the directory of the POM in the archive, like
+	// META-INF/maven/org.slf4j/slf4j-api.
+	Dir string
+	// GroupId, ArtifactId and Version are the coordinates of the POM, from
+	// pom.properties or else from Model.
+	GroupId    string
+	ArtifactId string
+	Version    string
+	// Properties are the properties of pom.properties, nil without one.
+	Properties map[string]string
+	// Model is the POM as decoded, nil without a pom.xml or when it cannot
+	// be decoded.
+	Model *pom.Model
+	// Err is why the pom.xml or pom.properties could not be decoded. The
+	// coordinates of the other are still available then.
+	Err error
+}
+
+// Coordinates returns the groupId:artifactId:version of p.
+func (p *EmbeddedPOM) Coordinates() string {
+	return p.GroupId + ":" + p.ArtifactId + ":" + p.Version
+}
+
+// nestedExtensions are the extensions of the entries read as nested
+// archives, like those of WEB-INF/lib in a WAR, BOOT-INF/lib in a Spring Boot
+// jar, or the modules of an EAR.
+var nestedExtensions = []string{".jar", ".war", ".ear", ".rar"}
+
+const (
+	// maxDepth is the number of archives nested in each other read.
+	maxDepth = 8
+	// maxNestedSize is the size of the biggest compressed nested archive
+	// read, as they are uncompressed in memory. Stored ones are read in
+	// place.
+	maxNestedSize = 512 << 20
+)
+
+// EmbeddedPOMs returns the POMs of the archive at path and of the archives
+// nested in it, the POMs of an archive first, in the order of their
+// directories, then those of its nested archives in the order of their
+// entries.
+//
+// Only an archive at path that cannot be read is an error. Nested archives
+// that cannot be read, like truncated ones, and pom.xml or pom.properties
+// files that cannot be decoded have their Err set instead, and the other
+// POMs are still read.
+func EmbeddedPOMs(path string) ([]EmbeddedPOM, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return ReadEmbeddedPOMs(f, info.Size(), path)
+}
+
+// ReadEmbeddedPOMs is like EmbeddedPOMs for the archive of size bytes read
+// from r, named name in the Archive of the POMs.
+func ReadEmbeddedPOMs(r io.ReaderAt, size int64, name string) ([]EmbeddedPOM, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("jar: %s: %w", name, err)
+	}
+	var poms []EmbeddedPOM
+	readEmbeddedPOMs(r, zr, name, 0, &poms)
+	return poms, nil
+}
+
+func readEmbeddedPOMs(r io.ReaderAt, zr *zip.Reader, name string, depth int, poms *[]EmbeddedPOM) {
+
+	found := make(map[string]*EmbeddedPOM)
+	var nested []*zip.File
+	for _, f := range zr.File {
+		dir, base := path.Split(f.Name)
+		dir = strings.TrimSuffix(dir, "/")
+		switch {
+		case isNested(f):
+			nested = append(nested, f)
+			continue
+		case base != "pom.xml" && base != "pom.properties" || !isPOMDir(dir):
+			continue
+		}
+
+		p := found[dir]
+		if p == nil {
+			p = &EmbeddedPOM{Archive: name, Dir: dir}
+			found[dir] = p
+		}
+		var err error
+		if base == "pom.properties" {
+			p.Properties, err = readProperties(f)
+		} else {
+			p.Model, err = readModel(f)
+		}
+		if err != nil {
+			p.Err = errors.Join(p.Err, fmt.Errorf("jar: %s!/%s: %w", name, f.Name, err))
+		}
+	}
+
+	dirs := make([]string, 0, len(found))
+	for dir := range found {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		p := found[dir]
+		p.coordinates()
+		*poms = append(*poms, *p)
+	}
+
+	if depth+1 >= maxDepth {
+		return
+	}
+	for _, f := range nested {
+		nestedName := name + "!/" + f.Name
+		if err := readNested(r, f, nestedName, depth+1, poms); err != nil {
+			*poms = append(*poms, EmbeddedPOM{Archive: nestedName, Err: err})
+		}
+	}
+}
+
+// isPOMDir reports whether dir is a META-INF/maven/<groupId>/<artifactId>
+// directory.
+func isPOMDir(dir string) bool {
+	parts := strings.Split(dir, "/")
+	return len(parts) == 4 && parts[0] == "META-INF" && parts[1] == "maven"
+}
+
+func isNested(f *zip.File) bool {
+	for _, ext := range nestedExtensions {
+		if strings.HasSuffix(strings.ToLower(f.Name), ext) && !f.FileInfo().IsDir() {
+			return true
+		}
+	}
+	return false
+}
+
+// readNested reads the POMs of the nested archive f of the archive read from
+// r, in place when it is stored without compression, like the libraries of
+// Spring Boot jars. It returns an error, before adding any POM, when the
+// nested archive cannot be read.
+func readNested(r io.ReaderAt, f *zip.File, name string, depth int, poms *[]EmbeddedPOM) error {
+	size := int64(f.UncompressedSize64)
+	if f.Method == zip.Store {
+		offset, err := f.DataOffset()
+		if err != nil {
+			return fmt.Errorf("jar: %s: %w", name, err)
+		}
+		section := io.NewSectionReader(r, offset, size)
+		zr, err := zip.NewReader(section, size)
+		if err != nil {
+			return fmt.Errorf("jar: %s: %w", name, err)
+		}
+		readEmbeddedPOMs(section, zr, name, depth, poms)
+		return nil
+	}
+
+	if size > maxNestedSize {
+		return fmt.Errorf("jar: %s: nested archive too large: %d bytes", name, size)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("jar: %s: %w", name, err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return fmt.Errorf("jar: %s: %w", name, err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("jar: %s: %w", name, err)
+	}
+	readEmbeddedPOMs(bytes.NewReader(data), zr, name, depth, poms)
+	return nil
+}
+
+func readModel(f *zip.File) (*pom.Model, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return pom.Read(rc)
+}
+
+// readProperties reads the pom.properties entry f.
+func readProperties(f *zip.File) (map[string]string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	p, err := properties.Read(rc)
+	if err != nil {
+		return nil, err
+	}
+	return p.Map(), nil
+}
+
+// coordinates sets the coordinates of p from its properties, or else its
+// model and the parent of its model.
+func (p *EmbeddedPOM) coordinates() {
+	p.GroupId, p.ArtifactId, p.Version = p.Properties["groupId"], p.Properties["artifactId"], p.Properties["version"]
+	if m := p.Model; m != nil {
+		if p.GroupId == "" {
+			p.GroupId = m.GroupId
+			if p.GroupId == "" && m.Parent != nil {
+				p.GroupId = m.Parent.GroupId
+			}
+		}
+		if p.ArtifactId == "" {
+			p.ArtifactId = m.ArtifactId
+		}
+		if p.Version == "" {
+			p.Version = m.Version
+			if p.Version == "" && m.Parent != nil {
+				p.Version = m.Parent.Version
+			}
+		}
+	}
+}
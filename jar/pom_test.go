@@ -0,0 +1,152 @@
+package jar_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/obscurelyme/encoding/jar"
+)
+
+// entry is an entry of an archive built by archive.
+type entry struct {
+	name    string
+	content []byte
+	stored  bool
+}
+
+func archive(t *testing.T, entries ...entry) []byte {
+	t.Helper()
+
+	var b bytes.Buffer
+	w := zip.NewWriter(&b)
+	for _, e := range entries {
+		method := zip.Deflate
+		if e.stored {
+			method = zip.Store
+		}
+		fw, err := w.CreateHeader(&zip.FileHeader{Name: e.name, Method: method})
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write(e.content)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func embeddedPOM(groupId, artifactId, version string) []entry {
+	dir := "META-INF/maven/" + groupId + "/" + artifactId + "/"
+	return []entry{
+		{name: dir + "pom.xml", content: []byte("<project><modelVersion>4.0.0</modelVersion><groupId>" + groupId + "</groupId><artifactId>" + artifactId + "</artifactId><version>" + version + "</version></project>")},
+		{name: dir + "pom.properties", content: []byte("#Generated by Maven\ngroupId=" + groupId + "\nartifactId=" + artifactId + "\nversion=" + version + "\n")},
+	}
+}
+
+func TestEmbeddedPOMs(t *testing.T) {
+	library := archive(t, append(embeddedPOM("org.slf4j", "slf4j-api", "2.0.9"), entry{name: "org/slf4j/Logger.class"})...)
+	broken := archive(t,
+		entry{name: "META-INF/maven/org.example/broken/pom.xml", content: []byte("<project>")},
+		entry{name: "META-INF/maven/org.example/broken/pom.properties", content: []byte("groupId=org.example\nartifactId=broken\nversion=0.1\n")},
+	)
+	boot := archive(t, append(embeddedPOM("org.example", "boot", "1.0"),
+		entry{name: "BOOT-INF/lib/slf4j-api-2.0.9.jar", content: library, stored: true},
+		entry{name: "BOOT-INF/lib/broken-0.1.jar", content: broken, stored: true},
+	)...)
+	war := archive(t,
+		entry{name: "META-INF/maven/org.example/web/pom.xml", content: []byte("<project><parent><groupId>org.example</groupId><artifactId>parent</artifactId><version>3</version></parent><artifactId>web</artifactId></project>")},
+		entry{name: "WEB-INF/lib/boot.jar", content: boot},
+		entry{name: "WEB-INF/lib/"},
+	)
+
+	path := filepath.Join(t.TempDir(), "app.war")
+	if err := os.WriteFile(path, war, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	poms, err := jar.EmbeddedPOMs(path)
+	if err != nil {
+		t.Fatalf("Expected no errors reading the POMs, but found: %s", err.Error())
+	}
+
+	t.Run("Should find the POMs of nested archives", func(t *testing.T) {
+		var found []string
+		for _, p := range poms {
+			found = append(found, strings.TrimPrefix(p.Archive, path)+" "+p.Coordinates())
+		}
+		expected := []string{
+			" org.example:web:3",
+			"!/WEB-INF/lib/boot.jar org.example:boot:1.0",
+			"!/WEB-INF/lib/boot.jar!/BOOT-INF/lib/slf4j-api-2.0.9.jar org.slf4j:slf4j-api:2.0.9",
+			"!/WEB-INF/lib/boot.jar!/BOOT-INF/lib/broken-0.1.jar org.example:broken:0.1",
+		}
+		if strings.Join(found, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("Expected:\n%s\nbut found:\n%s", strings.Join(expected, "\n"), strings.Join(found, "\n"))
+		}
+	})
+
+	t.Run("Should decode the POMs", func(t *testing.T) {
+		if m := poms[2].Model; m == nil || m.ArtifactId != "slf4j-api" || poms[2].Dir != "META-INF/maven/org.slf4j/slf4j-api" {
+			t.Errorf("Expected the model of slf4j-api, but found: %+v", poms[2])
+		}
+		if poms[0].Properties != nil {
+			t.Errorf("Expected no properties for web, but found: %v", poms[0].Properties)
+		}
+	})
+
+	t.Run("Should keep the coordinates of POMs that cannot be decoded", func(t *testing.T) {
+		if p := poms[3]; p.Err == nil || p.Model != nil {
+			t.Errorf("Expected an error decoding the broken POM, but found: %+v", p)
+		}
+	})
+
+	t.Run("Should keep scanning past archives that cannot be read", func(t *testing.T) {
+		corrupt := archive(t, append(embeddedPOM("org.example", "app", "1.0"),
+			entry{name: "lib/corrupt.jar", content: []byte("not a zip")},
+			entry{name: "lib/truncated.jar", content: library[:len(library)/2], stored: true},
+			entry{name: "lib/slf4j-api-2.0.9.jar", content: library},
+		)...)
+		poms, err := jar.ReadEmbeddedPOMs(bytes.NewReader(corrupt), int64(len(corrupt)), "app.ear")
+		if err != nil {
+			t.Fatalf("Expected no errors reading the POMs, but found: %s", err.Error())
+		}
+
+		var found []string
+		for _, p := range poms {
+			found = append(found, p.Archive+" "+p.Coordinates())
+		}
+		expected := []string{
+			"app.ear org.example:app:1.0",
+			"app.ear!/lib/corrupt.jar ::",
+			"app.ear!/lib/truncated.jar ::",
+			"app.ear!/lib/slf4j-api-2.0.9.jar org.slf4j:slf4j-api:2.0.9",
+		}
+		if strings.Join(found, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("Expected:\n%s\nbut found:\n%s", strings.Join(expected, "\n"), strings.Join(found, "\n"))
+		}
+		if len(poms) == 4 && (poms[1].Err == nil || !strings.Contains(poms[1].Err.Error(), "app.ear!/lib/corrupt.jar") || poms[2].Err == nil) {
+			t.Errorf("Expected errors naming the nested archives, but found: %v %v", poms[1].Err, poms[2].Err)
+		}
+	})
+
+	t.Run("Should keep the POMs with a malformed pom.properties", func(t *testing.T) {
+		malformed := archive(t,
+			entry{name: "META-INF/maven/org.example/bad/pom.xml", content: []byte("<project><groupId>org.example</groupId><artifactId>bad</artifactId><version>2</version></project>")},
+			entry{name: "META-INF/maven/org.example/bad/pom.properties", content: []byte("groupId=\\uZZZZ\n")},
+		)
+		poms, err := jar.ReadEmbeddedPOMs(bytes.NewReader(malformed), int64(len(malformed)), "bad.jar")
+		if err != nil || len(poms) != 1 || poms[0].Err == nil || poms[0].Coordinates() != "org.example:bad:2" {
+			t.Errorf("Expected the POM with an error, but found: %+v %v", poms, err)
+		}
+	})
+
+	t.Run("Should report archives that cannot be read", func(t *testing.T) {
+		if _, err := jar.ReadEmbeddedPOMs(bytes.NewReader([]byte("not a zip")), 9, "app.ear"); err == nil {
+			t.Errorf("Expected an error for the archive")
+		}
+	})
+}
@@ -0,0 +1,233 @@
+package manifest
+
+import (
+	"strings"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+// jarPlugin is the key of the Maven jar plugin.
+const jarPlugin = "org.apache.maven.plugins:maven-jar-plugin"
+
+// ArchiverOptions are the manifest options of the archive configuration of
+// the Maven archiver, used by the jar, war and ear plugins. Their zero value
+// is its default configuration.
+type ArchiverOptions struct {
+	// SkipDefaultEntries leaves out Created-By and Build-Jdk-Spec, like
+	// addDefaultEntries false does.
+	SkipDefaultEntries bool
+	// CreatedBy is the Created-By entry, Maven JAR Plugin by default.
+	CreatedBy string
+	// BuildJdkSpec is the specification version of the JDK of the build,
+	// like 17, left out when empty.
+	BuildJdkSpec string
+
+	// AddDefaultImplementationEntries adds the Implementation-Title,
+	// Implementation-Version and Implementation-Vendor of the project.
+	AddDefaultImplementationEntries bool
+	// AddDefaultSpecificationEntries adds the Specification-Title,
+	// Specification-Version and Specification-Vendor of the project.
+	AddDefaultSpecificationEntries bool
+
+	// MainClass is the Main-Class entry.
+	MainClass string
+	// AddClasspath adds a Class-Path entry with ClassPath, each prefixed by
+	// ClasspathPrefix, like lib/.
+	AddClasspath    bool
+	ClasspathPrefix string
+	// ClassPath are the file names of the runtime dependencies of the
+	// project, in classpath order.
+	ClassPath []string
+
+	// Entries are the manifestEntries, added last. An entry with an empty
+	// value removes the attribute instead.
+	Entries Attributes
+	// Sections are the manifestSections.
+	Sections []*Section
+}
+
+// FromModel returns the manifest the Maven archiver writes for the project of
+// m, an effective model, with opts, or the archive configuration of the jar
+// plugin of m when opts is nil.
+func FromModel(m *pom.Model, opts *ArchiverOptions) (*Manifest, error) {
+	if opts == nil {
+		opts = JarPluginOptions(m)
+	}
+
+	name, version, vendor := orDefault(m.Name, m.ArtifactId), m.Version, ""
+	if version == "" && m.Parent != nil {
+		version = m.Parent.Version
+	}
+	if m.Organization != nil {
+		vendor = m.Organization.Name
+	}
+
+	var entries Attributes
+	if !opts.SkipDefaultEntries {
+		entries = append(entries,
+			Attribute{"Created-By", orDefault(opts.CreatedBy, "Maven JAR Plugin")},
+			Attribute{"Build-Jdk-Spec", opts.BuildJdkSpec})
+	}
+	if opts.AddDefaultSpecificationEntries {
+		entries = append(entries,
+			Attribute{"Specification-Title", name},
+			Attribute{"Specification-Version", specificationVersion(version)},
+			Attribute{"Specification-Vendor", vendor})
+	}
+	if opts.AddDefaultImplementationEntries {
+		entries = append(entries,
+			Attribute{"Implementation-Title", name},
+			Attribute{"Implementation-Version", version},
+			Attribute{"Implementation-Vendor", vendor})
+	}
+	entries = append(entries, Attribute{"Main-Class", opts.MainClass})
+	if opts.AddClasspath && len(opts.ClassPath) > 0 {
+		paths := make([]string, len(opts.ClassPath))
+		for i, p := range opts.ClassPath {
+			paths[i] = opts.ClasspathPrefix + p
+		}
+		entries = append(entries, Attribute{"Class-Path", strings.Join(paths, " ")})
+	}
+
+	mf := New()
+	for _, e := range entries {
+		if e.Value == "" {
+			continue
+		}
+		if err := mf.Main.Set(e.Name, e.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, e := range opts.Entries {
+		if strings.TrimSpace(e.Value) == "" {
+			mf.Main.Delete(e.Name)
+			continue
+		}
+		if err := mf.Main.Set(e.Name, e.Value); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range opts.Sections {
+		section := mf.AddSection(s.Name)
+		for _, e := range s.Attributes {
+			if err := section.Attributes.Set(e.Name, e.Value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return mf, nil
+}
+
+// specificationVersion returns the major.minor of version, like the Maven
+// archiver does, or version when it does not start with numbers.
+func specificationVersion(version string) string {
+	numbers := strings.FieldsFunc(version, func(r rune) bool { return r == '.' || r == '-' })
+	if len(numbers) < 2 || !isNumber(numbers[0]) {
+		return version
+	}
+	if !isNumber(numbers[1]) {
+		return numbers[0] + ".0"
+	}
+	return numbers[0] + "." + numbers[1]
+}
+
+func isNumber(s string) bool {
+	for _, c := range []byte(s) {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// JarPluginOptions returns the options of the archive configuration of the
+// jar plugin of the build of m, an effective model. The options are the
+// defaults when m does not configure the plugin.
+func JarPluginOptions(m *pom.Model) *ArchiverOptions {
+	opts := &ArchiverOptions{}
+	if m.Build == nil || m.Build.Plugins == nil {
+		return opts
+	}
+	for _, p := range m.Build.Plugins.Plugin {
+		if p.Key() != jarPlugin {
+			continue
+		}
+		if p.Version != "" {
+			opts.CreatedBy = "Maven JAR Plugin " + p.Version
+		}
+		if archive := child(p.Configuration, "archive"); archive != nil {
+			archiveOptions(archive, opts)
+		}
+	}
+	return opts
+}
+
+// archiveOptions sets opts from the archive element of a plugin
+// configuration.
+func archiveOptions(archive *pom.DOM, opts *ArchiverOptions) {
+	if manifest := child(archive, "manifest"); manifest != nil {
+		for _, c := range manifest.Children {
+			switch c.XMLName.Local {
+			case "addDefaultEntries":
+				opts.SkipDefaultEntries = c.Value == "false"
+			case "addDefaultImplementationEntries":
+				opts.AddDefaultImplementationEntries = c.Value == "true"
+			case "addDefaultSpecificationEntries":
+				opts.AddDefaultSpecificationEntries = c.Value == "true"
+			case "addClasspath":
+				opts.AddClasspath = c.Value == "true"
+			case "classpathPrefix":
+				opts.ClasspathPrefix = c.Value
+			case "mainClass":
+				opts.MainClass = c.Value
+			}
+		}
+	}
+	if entries := child(archive, "manifestEntries"); entries != nil {
+		opts.Entries = domAttributes(entries)
+	}
+	if sections := child(archive, "manifestSections"); sections != nil {
+		for i := range sections.Children {
+			s := &sections.Children[i]
+			if s.XMLName.Local != "manifestSection" {
+				continue
+			}
+			section := &Section{}
+			if name := child(s, "name"); name != nil {
+				section.Name = name.Value
+			}
+			if entries := child(s, "manifestEntries"); entries != nil {
+				section.Attributes = domAttributes(entries)
+			}
+			opts.Sections = append(opts.Sections, section)
+		}
+	}
+}
+
+func domAttributes(d *pom.DOM) Attributes {
+	attrs := make(Attributes, len(d.Children))
+	for i, c := range d.Children {
+		attrs[i] = Attribute{Name: c.XMLName.Local, Value: c.Value}
+	}
+	return attrs
+}
+
+func child(d *pom.DOM, name string) *pom.DOM {
+	if d == nil {
+		return nil
+	}
+	for i := range d.Children {
+		if d.Children[i].XMLName.Local == name {
+			return &d.Children[i]
+		}
+	}
+	return nil
+}
+
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
@@ -0,0 +1,92 @@
+package manifest
+
+import "strings"
+
+// MainClass returns the Main-Class of m, the class run by java -jar.
+func (m *Manifest) MainClass() string {
+	return strings.TrimSpace(m.Main.Get("Main-Class"))
+}
+
+// ClassPath returns the Class-Path of m: the URLs, relative to the jar, of
+// the jars and directories it needs.
+func (m *Manifest) ClassPath() []string {
+	return strings.Fields(m.Main.Get("Class-Path"))
+}
+
+// AutomaticModuleName returns the Automatic-Module-Name of m, the name of
+// the jar on the module path when it has no module descriptor.
+func (m *Manifest) AutomaticModuleName() string {
+	return strings.TrimSpace(m.Main.Get("Automatic-Module-Name"))
+}
+
+// ImplementationTitle returns the Implementation-Title of m.
+func (m *Manifest) ImplementationTitle() string {
+	return m.Main.Get("Implementation-Title")
+}
+
+// ImplementationVersion returns the Implementation-Version of m.
+func (m *Manifest) ImplementationVersion() string {
+	return m.Main.Get("Implementation-Version")
+}
+
+// ImplementationVendor returns the Implementation-Vendor of m.
+func (m *Manifest) ImplementationVendor() string {
+	return m.Main.Get("Implementation-Vendor")
+}
+
+// MultiRelease reports whether m has Multi-Release true, for jars with
+// classes for specific Java versions under META-INF/versions.
+func (m *Manifest) MultiRelease() bool {
+	return strings.EqualFold(strings.TrimSpace(m.Main.Get("Multi-Release")), "true")
+}
+
+// IsBundle reports whether m is the manifest of an OSGi bundle, with a
+// Bundle-SymbolicName.
+func (m *Manifest) IsBundle() bool {
+	_, ok := m.Main.Lookup("Bundle-SymbolicName")
+	return ok
+}
+
+// BundleSymbolicName returns the clause of the Bundle-SymbolicName of m,
+// whose only path is the name of the bundle, with directives like
+// singleton. It is nil without one.
+func (m *Manifest) BundleSymbolicName() (*Clause, error) {
+	clauses, err := m.Clauses("Bundle-SymbolicName")
+	if err != nil || len(clauses) == 0 {
+		return nil, err
+	}
+	return &clauses[0], nil
+}
+
+// BundleVersion returns the Bundle-Version of m, 0.0.0 by default.
+func (m *Manifest) BundleVersion() string {
+	if v := strings.TrimSpace(m.Main.Get("Bundle-Version")); v != "" {
+		return v
+	}
+	return "0.0.0"
+}
+
+// ExportPackage returns the clauses of the Export-Package of m.
+func (m *Manifest) ExportPackage() ([]Clause, error) {
+	return m.Clauses("Export-Package")
+}
+
+// ImportPackage returns the clauses of the Import-Package of m.
+func (m *Manifest) ImportPackage() ([]Clause, error) {
+	return m.Clauses("Import-Package")
+}
+
+// RequireBundle returns the clauses of the Require-Bundle of m.
+func (m *Manifest) RequireBundle() ([]Clause, error) {
+	return m.Clauses("Require-Bundle")
+}
+
+// Clauses parses the attribute named name of the main section of m as an
+// OSGi header. It is nil when m has no such attribute.
+func (m *Manifest) Clauses(name string) ([]Clause, error) {
+	value, ok := m.Main.Lookup(name)
+	if !ok {
+		return nil, nil
+	}
+	return ParseClauses(value)
+}
@@ -0,0 +1,328 @@
+// Package manifest reads and writes JAR manifests, the META-INF/MANIFEST.MF
+// files of jars, in the format of java.util.jar.Manifest.
+//
+// A manifest has a main section, with the attributes of the archive, and
+// sections for some of its entries, each starting with a Name attribute.
+// Lines are at most 72 bytes long: longer attributes continue on lines
+// starting with a space.
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxLineLength is the length in bytes of the longest line of a manifest,
+// without its line terminator.
+const maxLineLength = 72
+
+// SyntaxError is returned for a manifest that cannot be read.
+type SyntaxError struct {
+	Line int
+	Msg  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("manifest: line %d: %s", e.Line, e.Msg)
+}
+
+// Attribute is a header of a section of a manifest.
+type Attribute struct {
+	Name  string
+	Value string
+}
+
+// Attributes are the attributes of a section, in order. Names are case
+// insensitive.
+type Attributes []Attribute
+
+// Get returns the value of the attribute named name, or an empty string.
+func (a Attributes) Get(name string) string {
+	value, _ := a.Lookup(name)
+	return value
+}
+
+// Lookup returns the value of the attribute named name, and whether a has
+// one.
+func (a Attributes) Lookup(name string) (string, bool) {
+	if i := a.index(name); i >= 0 {
+		return a[i].Value, true
+	}
+	return "", false
+}
+
+func (a Attributes) index(name string) int {
+	for i := range a {
+		if strings.EqualFold(a[i].Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Set sets the value of the attribute named name, in place when a has one
+// and at the end otherwise. An invalid name or a value with a line break is
+// an error.
+func (a *Attributes) Set(name, value string) error {
+	if !ValidName(name) {
+		return fmt.Errorf("manifest: invalid attribute name %q", name)
+	}
+	if !validValue(value) {
+		return fmt.Errorf("manifest: invalid value of %s: %q", name, value)
+	}
+	if i := a.index(name); i >= 0 {
+		(*a)[i].Value = value
+		return nil
+	}
+	*a = append(*a, Attribute{Name: name, Value: value})
+	return nil
+}
+
+// Delete removes the attribute named name.
+func (a *Attributes) Delete(name string) {
+	if i := a.index(name); i >= 0 {
+		*a = append((*a)[:i], (*a)[i+1:]...)
+	}
+}
+
+// ValidName reports whether name is a valid attribute name: 1 to 70
+// letters, digits, dashes and underscores.
+func ValidName(name string) bool {
+	if len(name) == 0 || len(name) > 70 {
+		return false
+	}
+	for _, c := range []byte(name) {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+func validValue(value string) bool {
+	return !strings.ContainsAny(value, "\r\n\x00") && utf8.ValidString(value)
+}
+
+// Section is the section of an entry of the archive.
+type Section struct {
+	// Name is the path of the entry in the archive, like
+	// org/example/Main.class, or a package directory like org/example/.
+	Name       string
+	Attributes Attributes
+}
+
+// Manifest is the content of a MANIFEST.MF file.
+type Manifest struct {
+	Main     Attributes
+	Sections []*Section
+}
+
+// New returns a manifest with the Manifest-Version 1.0 only.
+func New() *Manifest {
+	return &Manifest{Main: Attributes{{Name: "Manifest-Version", Value: "1.0"}}}
+}
+
+// Section returns the section named name, or nil.
+func (m *Manifest) Section(name string) *Section {
+	for _, s := range m.Sections {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// AddSection returns the section named name, added at the end when m has
+// none.
+func (m *Manifest) AddSection(name string) *Section {
+	if s := m.Section(name); s != nil {
+		return s
+	}
+	s := &Section{Name: name}
+	m.Sections = append(m.Sections, s)
+	return s
+}
+
+// Read decodes the manifest read from r.
+func Read(r io.Reader) (*Manifest, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// ReadFile decodes the manifest file at path.
+func ReadFile(path string) (*Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	m, err := Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// Parse decodes the manifest data. Like java.util.jar.Manifest, an attribute
+// defined twice in a section keeps its last value, and sections with the
+// same name are merged.
+func Parse(data []byte) (*Manifest, error) {
+	m := &Manifest{}
+	// section is the section being read, nil for the main section and
+	// between sections.
+	var section *Section
+	main := true
+	var header *Attribute
+	var headerLine int
+
+	flush := func() error {
+		if header == nil {
+			return nil
+		}
+		h := *header
+		header = nil
+		if !validValue(h.Value) {
+			return &SyntaxError{Line: headerLine, Msg: "invalid value of " + h.Name}
+		}
+		switch {
+		case main:
+			m.Main.Set(h.Name, h.Value)
+		case section == nil:
+			if !strings.EqualFold(h.Name, "Name") {
+				return &SyntaxError{Line: headerLine, Msg: "section without a Name attribute"}
+			}
+			section = m.AddSection(h.Value)
+		default:
+			section.Attributes.Set(h.Name, h.Value)
+		}
+		return nil
+	}
+
+	for n := 1; len(data) > 0; n++ {
+		line, rest := splitLine(data)
+		data = rest
+
+		switch {
+		case line == "":
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			main, section = false, nil
+		case line[0] == ' ':
+			if header == nil {
+				return nil, &SyntaxError{Line: n, Msg: "continuation line without an attribute"}
+			}
+			header.Value += line[1:]
+		default:
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			name, value, ok := strings.Cut(line, ": ")
+			if !ok || !ValidName(name) {
+				return nil, &SyntaxError{Line: n, Msg: fmt.Sprintf("invalid header %q", line)}
+			}
+			header, headerLine = &Attribute{Name: name, Value: value}, n
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// splitLine returns the first line of data, without its line terminator,
+// and the rest of data. Lines end with \r\n, \n or \r.
+func splitLine(data []byte) (string, []byte) {
+	i := bytes.IndexAny(data, "\r\n")
+	if i < 0 {
+		return string(data), nil
+	}
+	line, rest := string(data[:i]), data[i+1:]
+	if data[i] == '\r' && len(rest) > 0 && rest[0] == '\n' {
+		rest = rest[1:]
+	}
+	return line, rest
+}
+
+// Bytes encodes m with \r\n line terminators, the Manifest-Version first in
+// the main section and lines wrapped at 72 bytes. Invalid attributes are an
+// error.
+func (m *Manifest) Bytes() ([]byte, error) {
+	var b bytes.Buffer
+	if version, ok := m.Main.Lookup("Manifest-Version"); ok {
+		if err := writeAttribute(&b, "Manifest-Version", version); err != nil {
+			return nil, err
+		}
+	}
+	for _, a := range m.Main {
+		if strings.EqualFold(a.Name, "Manifest-Version") {
+			continue
+		}
+		if err := writeAttribute(&b, a.Name, a.Value); err != nil {
+			return nil, err
+		}
+	}
+	b.WriteString("\r\n")
+
+	for _, s := range m.Sections {
+		if err := writeAttribute(&b, "Name", s.Name); err != nil {
+			return nil, err
+		}
+		for _, a := range s.Attributes {
+			if strings.EqualFold(a.Name, "Name") {
+				continue
+			}
+			if err := writeAttribute(&b, a.Name, a.Value); err != nil {
+				return nil, err
+			}
+		}
+		b.WriteString("\r\n")
+	}
+	return b.Bytes(), nil
+}
+
+// WriteTo writes m encoded by Bytes to w.
+func (m *Manifest) WriteTo(w io.Writer) (int64, error) {
+	data, err := m.Bytes()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// writeAttribute writes the attribute name with value, wrapped at 72 bytes
+// without splitting characters, like Java 11 and later do.
+func writeAttribute(b *bytes.Buffer, name, value string) error {
+	if !ValidName(name) {
+		return fmt.Errorf("manifest: invalid attribute name %q", name)
+	}
+	if !validValue(value) {
+		return fmt.Errorf("manifest: invalid value of %s: %q", name, value)
+	}
+
+	line := name + ": " + value
+	limit := maxLineLength
+	for len(line) > limit {
+		i := limit
+		for i > 0 && !utf8.RuneStart(line[i]) {
+			i--
+		}
+		b.WriteString(line[:i])
+		b.WriteString("\r\n ")
+		line = line[i:]
+		// The space of continuation lines counts.
+		limit = maxLineLength - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+	return nil
+}
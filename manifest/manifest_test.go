@@ -0,0 +1,273 @@
+package manifest_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/obscurelyme/encoding/manifest"
+	"github.com/obscurelyme/encoding/pom"
+)
+
+func readFixture(t *testing.T) (*manifest.Manifest, []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", "MANIFEST.MF")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := manifest.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected no errors reading the manifest, but found: %s", err.Error())
+	}
+	return m, data
+}
+
+func TestRead(t *testing.T) {
+	m, data := readFixture(t)
+
+	t.Run("Should join continuation lines", func(t *testing.T) {
+		expected := "lib/slf4j-api-2.0.9.jar lib/commons-lang3-3.14.0.jar lib/jackson-databind-2.16.1.jar lib/jackson-core-2.16.1.jar"
+		if found := m.Main.Get("Class-Path"); found != expected {
+			t.Errorf("Expected %s, but found: %s", expected, found)
+		}
+		expected = "Exemple élégant ✓ — un nom assez long pour être coupé au milieu d’un caractère"
+		if found := m.Main.Get("bundle-name"); found != expected {
+			t.Errorf("Expected %s, but found: %s", expected, found)
+		}
+	})
+
+	t.Run("Should read the sections of entries", func(t *testing.T) {
+		if len(m.Sections) != 2 {
+			t.Fatalf("Expected 2 sections, but found: %d", len(m.Sections))
+		}
+		if s := m.Section("org/example/"); s == nil || s.Attributes.Get("Sealed") != "true" {
+			t.Errorf("Expected the sealed org/example/ section, but found: %v", s)
+		}
+		if _, ok := m.Main.Lookup("Sealed"); ok {
+			t.Errorf("Expected no Sealed attribute in the main section")
+		}
+	})
+
+	t.Run("Should give typed access to common attributes", func(t *testing.T) {
+		if m.MainClass() != "org.example.Main" || m.AutomaticModuleName() != "org.example" || m.ImplementationVersion() != "1.2.0-SNAPSHOT" || !m.MultiRelease() {
+			t.Errorf("Expected the attributes of the fixture, but found: %s %s %s %v", m.MainClass(), m.AutomaticModuleName(), m.ImplementationVersion(), m.MultiRelease())
+		}
+		if cp := m.ClassPath(); len(cp) != 4 || cp[3] != "lib/jackson-core-2.16.1.jar" {
+			t.Errorf("Expected 4 Class-Path entries, but found: %v", cp)
+		}
+	})
+
+	t.Run("Should round trip unchanged", func(t *testing.T) {
+		found, err := m.Bytes()
+		if err != nil {
+			t.Fatalf("Expected no errors, but found: %s", err.Error())
+		}
+		if !bytes.Equal(found, data) {
+			t.Errorf("Expected:\n%q\nbut found:\n%q", data, found)
+		}
+	})
+
+	t.Run("Should accept other line terminators and merge sections", func(t *testing.T) {
+		m, err := manifest.Parse([]byte("Manifest-Version: 1.0\nA: 1\rA: 2\n\nName: x\nB: 1\n\n\nName: x\nC: 2"))
+		if err != nil {
+			t.Fatalf("Expected no errors, but found: %s", err.Error())
+		}
+		if m.Main.Get("A") != "2" || len(m.Main) != 2 {
+			t.Errorf("Expected A: 2, but found: %v", m.Main)
+		}
+		if len(m.Sections) != 1 || len(m.Sections[0].Attributes) != 2 {
+			t.Errorf("Expected one section x with B and C, but found: %v", m.Sections)
+		}
+	})
+
+	t.Run("Should reject malformed manifests", func(t *testing.T) {
+		for _, text := range []string{
+			"Manifest-Version 1.0\r\n",
+			" continued\r\n",
+			"Manifest-Version: 1.0\r\n\r\nSealed: true\r\n",
+			"Bad Name: 1\r\n",
+		} {
+			var syntaxErr *manifest.SyntaxError
+			if _, err := manifest.Parse([]byte(text)); !errors.As(err, &syntaxErr) {
+				t.Errorf("Expected a syntax error for %q, but found: %v", text, err)
+			}
+		}
+	})
+}
+
+func TestWrite(t *testing.T) {
+	t.Run("Should wrap long lines at 72 bytes", func(t *testing.T) {
+		m := manifest.New()
+		m.Main.Set("Class-Path", strings.Repeat("a", 100))
+		data, err := m.Bytes()
+		if err != nil {
+			t.Fatalf("Expected no errors, but found: %s", err.Error())
+		}
+		expected := "Manifest-Version: 1.0\r\nClass-Path: " + strings.Repeat("a", 60) + "\r\n " + strings.Repeat("a", 40) + "\r\n\r\n"
+		if string(data) != expected {
+			t.Errorf("Expected %q, but found: %q", expected, data)
+		}
+	})
+
+	t.Run("Should write the Manifest-Version first", func(t *testing.T) {
+		m := &manifest.Manifest{}
+		m.Main.Set("Created-By", "test")
+		m.Main.Set("Manifest-Version", "1.0")
+		m.AddSection("a/").Attributes.Set("Sealed", "true")
+		data, _ := m.Bytes()
+		expected := "Manifest-Version: 1.0\r\nCreated-By: test\r\n\r\nName: a/\r\nSealed: true\r\n\r\n"
+		if string(data) != expected {
+			t.Errorf("Expected %q, but found: %q", expected, data)
+		}
+	})
+
+	t.Run("Should reject invalid attributes", func(t *testing.T) {
+		m := manifest.New()
+		if err := m.Main.Set("Not valid", "x"); err == nil {
+			t.Errorf("Expected an error for an invalid name")
+		}
+		if err := m.Main.Set("Valid", "line\nbreak"); err == nil {
+			t.Errorf("Expected an error for a value with a line break")
+		}
+		m.Main = append(m.Main, manifest.Attribute{Name: strings.Repeat("x", 71), Value: "x"})
+		if _, err := m.Bytes(); err == nil {
+			t.Errorf("Expected an error for a name of 71 characters")
+		}
+	})
+}
+
+func TestClauses(t *testing.T) {
+	m, _ := readFixture(t)
+
+	t.Run("Should parse OSGi headers", func(t *testing.T) {
+		bsn, err := m.BundleSymbolicName()
+		if err != nil || bsn.Paths[0] != "org.example" || bsn.Directive("singleton") != "true" {
+			t.Errorf("Expected the singleton org.example, but found: %v %v", bsn, err)
+		}
+		if !m.IsBundle() || m.BundleVersion() != "1.2.0.SNAPSHOT" {
+			t.Errorf("Expected the bundle version 1.2.0.SNAPSHOT, but found: %s", m.BundleVersion())
+		}
+
+		exports, err := m.ExportPackage()
+		if err != nil || len(exports) != 2 {
+			t.Fatalf("Expected 2 exported packages, but found: %v %v", exports, err)
+		}
+		if exports[0].Directive("uses") != "org.slf4j,org.example.spi" || exports[0].Attribute("version") != "1.2.0" {
+			t.Errorf("Expected the uses and version of org.example.api, but found: %v", exports[0])
+		}
+		if p := exports[1].Parameters[0]; p.Type != "Version" || p.Value != "1.2.0" {
+			t.Errorf("Expected a typed version attribute, but found: %v", p)
+		}
+
+		imports, err := m.ImportPackage()
+		if err != nil || len(imports) != 2 || imports[0].Attribute("version") != "[2.0,3)" || imports[0].Directive("resolution") != "optional" || imports[1].Paths[0] != "javax.annotation" {
+			t.Errorf("Expected the imports of the fixture, but found: %v %v", imports, err)
+		}
+	})
+
+	t.Run("Should format clauses", func(t *testing.T) {
+		value := "a;b;version=1.0;x:List<String>=\"1,2\";resolution:=optional,c"
+		clauses, err := manifest.ParseClauses(value)
+		if err != nil {
+			t.Fatalf("Expected no errors, but found: %s", err.Error())
+		}
+		if found := manifest.FormatClauses(clauses); found != value {
+			t.Errorf("Expected %s, but found: %s", value, found)
+		}
+	})
+
+	t.Run("Should reject malformed clauses", func(t *testing.T) {
+		for _, value := range []string{`a;version="1.0`, `a;version=1;b`, `;version=1`, `a,,b`} {
+			if _, err := manifest.ParseClauses(value); err == nil {
+				t.Errorf("Expected an error for %s", value)
+			}
+		}
+	})
+}
+
+func TestFromModel(t *testing.T) {
+	m, err := pom.Read(strings.NewReader(`<project>
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>org.example</groupId>
+  <artifactId>example</artifactId>
+  <version>1.2.0-SNAPSHOT</version>
+  <name>Example</name>
+  <organization><name>Example Inc.</name></organization>
+  <build>
+    <plugins>
+      <plugin>
+        <artifactId>maven-jar-plugin</artifactId>
+        <version>3.4.1</version>
+        <configuration>
+          <archive>
+            <manifest>
+              <addDefaultImplementationEntries>true</addDefaultImplementationEntries>
+              <addDefaultSpecificationEntries>true</addDefaultSpecificationEntries>
+              <mainClass>org.example.Main</mainClass>
+            </manifest>
+            <manifestEntries>
+              <Automatic-Module-Name>org.example</Automatic-Module-Name>
+              <Specification-Vendor></Specification-Vendor>
+            </manifestEntries>
+            <manifestSections>
+              <manifestSection>
+                <name>org/example/</name>
+                <manifestEntries>
+                  <Sealed>true</Sealed>
+                </manifestEntries>
+              </manifestSection>
+            </manifestSections>
+          </archive>
+        </configuration>
+      </plugin>
+    </plugins>
+  </build>
+</project>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Should write the entries of the Maven archiver", func(t *testing.T) {
+		mf, err := manifest.FromModel(m, nil)
+		if err != nil {
+			t.Fatalf("Expected no errors, but found: %s", err.Error())
+		}
+		data, _ := mf.Bytes()
+		expected := "Manifest-Version: 1.0\r\n" +
+			"Created-By: Maven JAR Plugin 3.4.1\r\n" +
+			"Specification-Title: Example\r\n" +
+			"Specification-Version: 1.2\r\n" +
+			"Implementation-Title: Example\r\n" +
+			"Implementation-Version: 1.2.0-SNAPSHOT\r\n" +
+			"Implementation-Vendor: Example Inc.\r\n" +
+			"Main-Class: org.example.Main\r\n" +
+			"Automatic-Module-Name: org.example\r\n" +
+			"\r\n" +
+			"Name: org/example/\r\n" +
+			"Sealed: true\r\n" +
+			"\r\n"
+		if string(data) != expected {
+			t.Errorf("Expected:\n%s\nbut found:\n%s", expected, data)
+		}
+	})
+
+	t.Run("Should add the classpath with its prefix", func(t *testing.T) {
+		mf, err := manifest.FromModel(m, &manifest.ArchiverOptions{
+			SkipDefaultEntries: true,
+			AddClasspath:       true,
+			ClasspathPrefix:    "lib/",
+			ClassPath:          []string{"slf4j-api-2.0.9.jar", "commons-lang3-3.14.0.jar"},
+		})
+		if err != nil {
+			t.Fatalf("Expected no errors, but found: %s", err.Error())
+		}
+		if found := strings.Join(mf.ClassPath(), " "); found != "lib/slf4j-api-2.0.9.jar lib/commons-lang3-3.14.0.jar" || len(mf.Main) != 2 {
+			t.Errorf("Expected the Class-Path only, but found: %v", mf.Main)
+		}
+	})
+}
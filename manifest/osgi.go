@@ -0,0 +1,194 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Clause is a clause of an OSGi header, like Export-Package or
+// Import-Package: paths, like package names, that share parameters, as in
+//
+//	org.example.api;org.example.spi;version="1.2.0";uses:="org.slf4j"
+type Clause struct {
+	Paths      []string
+	Parameters []Parameter
+}
+
+// Parameter is an attribute or a directive of a clause.
+type Parameter struct {
+	Name string
+	// Type is the type of a typed attribute, like Version in
+	// version:Version=1.2, or empty.
+	Type  string
+	Value string
+	// Directive is whether the parameter is a directive, written with :=
+	// and read by the framework, rather than an attribute, matched by
+	// requirements.
+	Directive bool
+}
+
+// Attribute returns the value of the attribute named name of c, or an empty
+// string.
+func (c *Clause) Attribute(name string) string {
+	return c.parameter(name, false)
+}
+
+// Directive returns the value of the directive named name of c, or an empty
+// string.
+func (c *Clause) Directive(name string) string {
+	return c.parameter(name, true)
+}
+
+func (c *Clause) parameter(name string, directive bool) string {
+	for _, p := range c.Parameters {
+		if p.Name == name && p.Directive == directive {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+// String returns the clause as written in a header, quoting the values that
+// need it.
+func (c Clause) String() string {
+	parts := append([]string(nil), c.Paths...)
+	for _, p := range c.Parameters {
+		name := p.Name
+		switch {
+		case p.Directive:
+			name += ":"
+		case p.Type != "":
+			name += ":" + p.Type
+		}
+		parts = append(parts, name+"="+quote(p.Value))
+	}
+	return strings.Join(parts, ";")
+}
+
+// FormatClauses returns the value of a header with clauses.
+func FormatClauses(clauses []Clause) string {
+	s := make([]string, len(clauses))
+	for i, c := range clauses {
+		s[i] = c.String()
+	}
+	return strings.Join(s, ",")
+}
+
+// ParseClauses parses the value of an OSGi header, clauses separated by
+// commas, each with paths then parameters separated by semicolons. Values
+// may be quoted, to hold commas and semicolons like version ranges do.
+func ParseClauses(value string) ([]Clause, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	texts, err := split(value, ',')
+	if err != nil {
+		return nil, err
+	}
+
+	clauses := make([]Clause, 0, len(texts))
+	for _, text := range texts {
+		parts, err := split(text, ';')
+		if err != nil {
+			return nil, err
+		}
+		var c Clause
+		for _, part := range parts {
+			part = strings.TrimSpace(part)
+			p, ok, err := parseParameter(part)
+			switch {
+			case err != nil:
+				return nil, fmt.Errorf("manifest: invalid clause %q: %w", strings.TrimSpace(text), err)
+			case ok:
+				c.Parameters = append(c.Parameters, p)
+			case part == "":
+				return nil, fmt.Errorf("manifest: invalid clause %q: empty path", strings.TrimSpace(text))
+			case len(c.Parameters) > 0:
+				return nil, fmt.Errorf("manifest: invalid clause %q: path %s after parameters", strings.TrimSpace(text), part)
+			default:
+				c.Paths = append(c.Paths, part)
+			}
+		}
+		if len(c.Paths) == 0 {
+			return nil, fmt.Errorf("manifest: invalid clause %q: no path", strings.TrimSpace(text))
+		}
+		clauses = append(clauses, c)
+	}
+	return clauses, nil
+}
+
+// split splits s at the occurrences of sep outside of quoted strings.
+func split(s string, sep byte) ([]string, error) {
+	var parts []string
+	start, quoted := 0, false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quoted && c == '\\':
+			i++
+		case c == '"':
+			quoted = !quoted
+		case !quoted && c == sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("manifest: unterminated quoted string in %q", s)
+	}
+	return append(parts, s[start:]), nil
+}
+
+// parseParameter parses part as a parameter, and reports whether it is one
+// rather than a path.
+func parseParameter(part string) (Parameter, bool, error) {
+	i := strings.IndexByte(part, '=')
+	if q := strings.IndexByte(part, '"'); i < 0 || q >= 0 && q < i {
+		return Parameter{}, false, nil
+	}
+
+	p := Parameter{Name: strings.TrimSpace(part[:i])}
+	if name, ok := strings.CutSuffix(p.Name, ":"); ok {
+		p.Name, p.Directive = name, true
+	} else if name, typ, ok := strings.Cut(p.Name, ":"); ok {
+		p.Name, p.Type = name, strings.TrimSpace(typ)
+	}
+	if p.Name == "" {
+		return p, false, fmt.Errorf("parameter without a name")
+	}
+	p.Value = unquote(strings.TrimSpace(part[i+1:]))
+	return p, true, nil
+}
+
+func unquote(s string) string {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return s
+	}
+	s = s[1 : len(s)-1]
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// quote returns s quoted unless it is an extended token, made of letters,
+// digits, dots, dashes and underscores.
+func quote(s string) string {
+	extended := s != ""
+	for _, c := range []byte(s) {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '-' || c == '_') {
+			extended = false
+			break
+		}
+	}
+	if extended {
+		return s
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,217 @@
+// Package analyze finds the dependencies a project uses without declaring
+// them, and those it declares without using, like the analyze goal of the
+// Maven dependency plugin.
+//
+// The classes the project uses are those its compiled classes refer to, read
+// from its output directories, and they are mapped to the dependencies of its
+// resolved graph by the classes of their jars in the local repository. Like
+// the dependency plugin, the analysis only sees the references that remain
+// in class files: constants inlined by the compiler and classes loaded by
+// reflection are not seen.
+package analyze
+
+import (
+	"archive/zip"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/obscurelyme/encoding/classfile"
+	"github.com/obscurelyme/encoding/jar"
+	"github.com/obscurelyme/encoding/pom"
+	"github.com/obscurelyme/encoding/pom/resolve"
+)
+
+// Options configures Analyze.
+type Options struct {
+	// Repository holds the jars of the dependencies.
+	Repository pom.LocalRepository
+	// Basedir is the directory of the project, where its output directories
+	// are.
+	Basedir string
+}
+
+// Dependency is a dependency of the analyzed project.
+type Dependency struct {
+	Node *resolve.Node
+	// Scope is the scope the project needs the dependency in: compile when
+	// its main classes use it, test when only its test classes do, and empty
+	// when it is unused.
+	Scope string
+	// Classes are the classes of the dependency the project uses, sorted.
+	Classes []string
+}
+
+func (d Dependency) String() string {
+	return d.Node.String()
+}
+
+// Analysis is the result of Analyze. Dependencies are in the order of the
+// graph.
+type Analysis struct {
+	// UsedDeclared are the dependencies the project declares and uses.
+	UsedDeclared []Dependency
+	// UsedUndeclared are the dependencies the project uses but only gets
+	// transitively, which it should declare with their Scope.
+	UsedUndeclared []Dependency
+	// UnusedDeclared are the dependencies the project declares without using
+	// them. Dependencies only needed at runtime are among them.
+	UnusedDeclared []Dependency
+	// TestOnly are the dependencies of UsedDeclared only the test classes
+	// use, although they are not declared in a test scope.
+	TestOnly []Dependency
+}
+
+// Analyze analyzes the dependencies of the project at the root of a graph
+// returned by resolve.Resolve, as described in the package documentation.
+// The output directories of the project may be missing, for projects without
+// classes or tests.
+func Analyze(root *resolve.Node, opts *Options) (*Analysis, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	output, testOutput := resolve.OutputDirectories(root.Model, opts.Basedir)
+	own := make(map[string]bool)
+	mainRefs, err := references(output, own)
+	if err != nil {
+		return nil, err
+	}
+	testRefs, err := references(testOutput, own)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Analysis{}
+	var walkErr error
+	root.Walk(func(n *resolve.Node) {
+		d := &n.Dependency
+		if n == root || n.Omitted != "" || walkErr != nil || !pom.LookupArtifactHandler(d.Type).AddedToClasspath {
+			return
+		}
+		classes, err := artifactClasses(opts.Repository.ArtifactPath(d))
+		if err != nil {
+			walkErr = err
+			return
+		}
+
+		dep := Dependency{Node: n}
+		usedByMain := false
+		for _, class := range classes {
+			if own[class] {
+				continue
+			}
+			if mainRefs[class] {
+				usedByMain = true
+			}
+			if mainRefs[class] || testRefs[class] {
+				dep.Classes = append(dep.Classes, class)
+			}
+			// The first dependency on the classpath with a class is the one
+			// the class is loaded from.
+			own[class] = true
+		}
+		switch {
+		case usedByMain:
+			dep.Scope = pom.ScopeCompile
+		case len(dep.Classes) > 0:
+			dep.Scope = pom.ScopeTest
+		}
+
+		declared := n.Parent() == root
+		switch {
+		case declared && dep.Scope == "":
+			a.UnusedDeclared = append(a.UnusedDeclared, dep)
+		case declared:
+			a.UsedDeclared = append(a.UsedDeclared, dep)
+			if dep.Scope == pom.ScopeTest && !isTestScope(d.Scope) {
+				a.TestOnly = append(a.TestOnly, dep)
+			}
+		case dep.Scope != "":
+			a.UsedUndeclared = append(a.UsedUndeclared, dep)
+		}
+	})
+	if walkErr != nil {
+		return nil, walkErr
+	}
+	return a, nil
+}
+
+// references returns the classes the classes of dir refer to, adding the
+// latter to own. A missing dir has no classes.
+func references(dir string, own map[string]bool) (map[string]bool, error) {
+	refs := make(map[string]bool)
+	if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		return refs, nil
+	}
+
+	err := jar.WalkClasses(os.DirFS(dir), func(name string, c *classfile.ClassFile) error {
+		own[name] = true
+		for _, ref := range c.ReferencedClasses() {
+			refs[ref] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("analyze: %s: %w", dir, err)
+	}
+	return refs, nil
+}
+
+// artifactClasses returns the classes of the jar or class directory at
+// path, sorted.
+func artifactClasses(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("analyze: %w", err)
+	}
+	if info.IsDir() {
+		return jar.Classes(os.DirFS(path))
+	}
+
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("analyze: %s: %w", path, err)
+	}
+	defer r.Close()
+	return jar.Classes(r)
+}
+
+func isTestScope(scope string) bool {
+	return scope == pom.ScopeTest || scope == pom.ScopeTestOnly || scope == pom.ScopeTestRuntime
+}
+
+// Problems reports whether a has used undeclared, unused declared or test
+// only dependencies.
+func (a *Analysis) Problems() bool {
+	return len(a.UsedUndeclared) > 0 || len(a.UnusedDeclared) > 0 || len(a.TestOnly) > 0
+}
+
+// WriteText writes the problems of a like the dependency plugin does, with
+// the classes used of each dependency, or nothing when there are none.
+func (a *Analysis) WriteText(w io.Writer) error {
+	b := bufio.NewWriter(w)
+	for _, section := range []struct {
+		title        string
+		dependencies []Dependency
+	}{
+		{"Used undeclared dependencies found:", a.UsedUndeclared},
+		{"Unused declared dependencies found:", a.UnusedDeclared},
+		{"Non-test scoped test only dependencies found:", a.TestOnly},
+	} {
+		if len(section.dependencies) == 0 {
+			continue
+		}
+		fmt.Fprintln(b, section.title)
+		for _, d := range section.dependencies {
+			fmt.Fprintf(b, "   %s\n", d)
+			for _, class := range d.Classes {
+				fmt.Fprintf(b, "      class %s\n", class)
+			}
+		}
+	}
+	return b.Flush()
+}
@@ -0,0 +1,101 @@
+package analyze_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+	"github.com/obscurelyme/encoding/pom/analyze"
+	"github.com/obscurelyme/encoding/pom/resolve"
+)
+
+var repository = pom.LocalRepository{Dir: filepath.Join("testdata", "repository")}
+
+func resolveProject(t *testing.T) *resolve.Node {
+	t.Helper()
+
+	m, err := pom.ReadFile(filepath.Join("testdata", "pom.xml"))
+	if err != nil {
+		t.Fatalf("Expected no errors reading pom, but found: %s", err.Error())
+	}
+	root, err := resolve.Resolve(m, &resolve.Options{Resolver: repository})
+	if err != nil {
+		t.Fatalf("Expected no errors resolving dependencies, but found: %s", err.Error())
+	}
+	return root
+}
+
+func artifactIds(dependencies []analyze.Dependency) string {
+	var ids []string
+	for _, d := range dependencies {
+		ids = append(ids, d.Node.Dependency.ArtifactId+":"+d.Scope)
+	}
+	return strings.Join(ids, " ")
+}
+
+func TestAnalyze(t *testing.T) {
+	root := resolveProject(t)
+	a, err := analyze.Analyze(root, &analyze.Options{Repository: repository, Basedir: "testdata"})
+	if err != nil {
+		t.Fatalf("Expected no errors analyzing dependencies, but found: %s", err.Error())
+	}
+
+	t.Run("Should sort dependencies by use", func(t *testing.T) {
+		for name, c := range map[string]struct {
+			found    []analyze.Dependency
+			expected string
+		}{
+			"used declared":   {a.UsedDeclared, "api:compile testonly:test junit:test"},
+			"used undeclared": {a.UsedUndeclared, "impl:compile"},
+			"unused declared": {a.UnusedDeclared, "unused:"},
+			"test only":       {a.TestOnly, "testonly:test"},
+		} {
+			if ids := artifactIds(c.found); ids != c.expected {
+				t.Errorf("Expected %s dependencies %s, but found: %s", name, c.expected, ids)
+			}
+		}
+		if !a.Problems() {
+			t.Errorf("Expected problems")
+		}
+	})
+
+	t.Run("Should list the classes used", func(t *testing.T) {
+		if classes := a.UsedDeclared[2].Classes; len(classes) != 1 || classes[0] != "org.example.junit.Assert" {
+			t.Errorf("Expected the Assert class of junit, but found: %v", classes)
+		}
+	})
+
+	t.Run("Should write the problems", func(t *testing.T) {
+		var b strings.Builder
+		if err := a.WriteText(&b); err != nil {
+			t.Fatalf("Expected no errors, but found: %s", err.Error())
+		}
+
+		expected := `Used undeclared dependencies found:
+   org.example:impl:jar:1.0:compile
+      class org.example.impl.Impl
+Unused declared dependencies found:
+   org.example:unused:jar:1.0:compile
+Non-test scoped test only dependencies found:
+   org.example:testonly:jar:1.0:compile
+      class org.example.testonly.Helper
+`
+		if b.String() != expected {
+			t.Errorf("Expected:\n%s\nbut found:\n%s", expected, b.String())
+		}
+	})
+
+	t.Run("Should analyze projects without classes", func(t *testing.T) {
+		a, err := analyze.Analyze(root, &analyze.Options{Repository: repository, Basedir: t.TempDir()})
+		if err != nil || len(a.UnusedDeclared) != 4 {
+			t.Errorf("Expected every dependency to be unused, but found: %v %v", a, err)
+		}
+	})
+
+	t.Run("Should report missing jars", func(t *testing.T) {
+		if _, err := analyze.Analyze(root, &analyze.Options{Repository: pom.LocalRepository{Dir: t.TempDir()}, Basedir: "testdata"}); err == nil {
+			t.Errorf("Expected an error")
+		}
+	})
+}
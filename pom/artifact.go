@@ -0,0 +1,54 @@
+package pom
+
+// ArtifactHandler describes the files of a dependency type, like Maven's
+// artifact handlers do.
+type ArtifactHandler struct {
+	// Extension is the extension of the files, jar for test-jar.
+	Extension string
+	// Classifier is the classifier the type implies, tests for test-jar.
+	Classifier string
+	// AddedToClasspath is whether the files go on the classpath or module
+	// path of the projects depending on them.
+	AddedToClasspath bool
+}
+
+// artifactHandlers are the handlers of the types Maven knows of, including
+// the modular-jar and classpath-jar types of Maven 4.
+var artifactHandlers = map[string]ArtifactHandler{
+	"pom":           {Extension: "pom"},
+	"bom":           {Extension: "pom"},
+	"jar":           {Extension: "jar", AddedToClasspath: true},
+	"test-jar":      {Extension: "jar", Classifier: "tests", AddedToClasspath: true},
+	"maven-plugin":  {Extension: "jar", AddedToClasspath: true},
+	"ejb":           {Extension: "jar", AddedToClasspath: true},
+	"ejb-client":    {Extension: "jar", Classifier: "client", AddedToClasspath: true},
+	"bundle":        {Extension: "jar", AddedToClasspath: true},
+	"modular-jar":   {Extension: "jar", AddedToClasspath: true},
+	"classpath-jar": {Extension: "jar", AddedToClasspath: true},
+	"processor":     {Extension: "jar", AddedToClasspath: true},
+	"java-source":   {Extension: "jar", Classifier: "sources"},
+	"javadoc":       {Extension: "jar", Classifier: "javadoc"},
+	"war":           {Extension: "war"},
+	"ear":           {Extension: "ear"},
+	"rar":           {Extension: "rar"},
+}
+
+// LookupArtifactHandler returns the handler of a dependency type, jar when
+// empty. Unknown types have files with the type as extension that are not
+// added to the classpath.
+func LookupArtifactHandler(typ string) ArtifactHandler {
+	if h, ok := artifactHandlers[orDefault(typ, "jar")]; ok {
+		return h
+	}
+	return ArtifactHandler{Extension: typ}
+}
+
+// ArtifactPath returns the location of the file of d within r, given its
+// type and classifier. The file of a system dependency is its systemPath.
+func (r LocalRepository) ArtifactPath(d *Dependency) string {
+	if d.Scope == ScopeSystem {
+		return d.SystemPath
+	}
+	h := LookupArtifactHandler(d.Type)
+	return r.Path(d.GroupId, d.ArtifactId, d.Version, orDefault(d.Classifier, h.Classifier), h.Extension)
+}
@@ -0,0 +1,252 @@
+package pom
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// charset converts a document between its declared encoding and UTF-8.
+type charset struct {
+	decode func([]byte) []byte
+	encode func([]byte) ([]byte, error)
+}
+
+var (
+	latin1 = singleByte(nil)
+
+	latin9 = singleByte(map[byte]rune{
+		0xA4: 0x20AC, 0xA6: 0x0160, 0xA8: 0x0161, 0xB4: 0x017D,
+		0xB8: 0x017E, 0xBC: 0x0152, 0xBD: 0x0153, 0xBE: 0x0178,
+	})
+
+	windows1252 = singleByte(map[byte]rune{
+		0x80: 0x20AC, 0x82: 0x201A, 0x83: 0x0192, 0x84: 0x201E,
+		0x85: 0x2026, 0x86: 0x2020, 0x87: 0x2021, 0x88: 0x02C6,
+		0x89: 0x2030, 0x8A: 0x0160, 0x8B: 0x2039, 0x8C: 0x0152,
+		0x8E: 0x017D, 0x91: 0x2018, 0x92: 0x2019, 0x93: 0x201C,
+		0x94: 0x201D, 0x95: 0x2022, 0x96: 0x2013, 0x97: 0x2014,
+		0x98: 0x02DC, 0x99: 0x2122, 0x9A: 0x0161, 0x9B: 0x203A,
+		0x9C: 0x0153, 0x9E: 0x017E, 0x9F: 0x0178,
+	})
+
+	usASCII = &charset{
+		decode: latin1.decode,
+		encode: func(b []byte) ([]byte, error) {
+			return encodeRunes(b, func(r rune) (byte, bool) { return byte(r), r < 0x80 })
+		},
+	}
+
+	utf16LE = utf16Charset(binary.LittleEndian)
+	utf16BE = utf16Charset(binary.BigEndian)
+)
+
+var charsets = map[string]*charset{
+	"iso-8859-1":   latin1,
+	"iso8859-1":    latin1,
+	"iso_8859-1":   latin1,
+	"latin1":       latin1,
+	"l1":           latin1,
+	"cp819":        latin1,
+	"ibm819":       latin1,
+	"iso-8859-15":  latin9,
+	"iso8859-15":   latin9,
+	"iso_8859-15":  latin9,
+	"latin9":       latin9,
+	"windows-1252": windows1252,
+	"cp1252":       windows1252,
+	"x-cp1252":     windows1252,
+	"us-ascii":     usASCII,
+	"ascii":        usASCII,
+	"utf-16":       utf16LE,
+	"utf-16le":     utf16LE,
+	"utf-16be":     utf16BE,
+}
+
+func lookupCharset(name string) (*charset, bool) {
+	cs, ok := charsets[strings.ToLower(strings.TrimSpace(name))]
+	return cs, ok
+}
+
+func isUTF8(name string) bool {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return name == "" || name == "utf-8" || name == "utf8"
+}
+
+// singleByte builds a charset that agrees with ISO-8859-1 except for the
+// bytes listed in overrides.
+func singleByte(overrides map[byte]rune) *charset {
+	var table [256]rune
+	for i := range table {
+		table[i] = rune(i)
+	}
+	for b, r := range overrides {
+		table[b] = r
+	}
+
+	reverse := make(map[rune]byte, len(overrides))
+	for b, r := range overrides {
+		reverse[r] = b
+	}
+
+	return &charset{
+		decode: func(b []byte) []byte {
+			out := make([]byte, 0, len(b))
+			for _, c := range b {
+				out = utf8.AppendRune(out, table[c])
+			}
+			return out
+		},
+		encode: func(b []byte) ([]byte, error) {
+			return encodeRunes(b, func(r rune) (byte, bool) {
+				if c, ok := reverse[r]; ok {
+					return c, true
+				}
+				if r < 0x100 && table[r] == r {
+					return byte(r), true
+				}
+				return 0, false
+			})
+		},
+	}
+}
+
+// encodeRunes converts UTF-8 to a single byte encoding, replacing runes the
+// encoding cannot represent with numeric character references. References
+// are not recognized in comments and processing instructions, a rune the
+// encoding cannot represent there is an *EncodingError with its position.
+func encodeRunes(b []byte, lookup func(rune) (byte, bool)) ([]byte, error) {
+	out := make([]byte, 0, len(b))
+	line, column := 1, 0
+	// end closes the comment or processing instruction b is in, if any
+	var end string
+	for len(b) > 0 {
+		switch {
+		case end != "" && bytes.HasPrefix(b, []byte(end)):
+			end = ""
+		case end == "" && bytes.HasPrefix(b, []byte("<!--")):
+			end = "-->"
+		case end == "" && bytes.HasPrefix(b, []byte("<?")):
+			end = "?>"
+		}
+
+		r, size := utf8.DecodeRune(b)
+		b = b[size:]
+		if column++; r == '\n' {
+			line, column = line+1, 0
+		}
+
+		if c, ok := lookup(r); ok {
+			out = append(out, c)
+			continue
+		}
+		if end != "" {
+			return nil, &EncodingError{Rune: r, Line: line, Column: column}
+		}
+		out = fmt.Appendf(out, "&#%d;", r)
+	}
+	return out, nil
+}
+
+type byteOrder interface {
+	binary.ByteOrder
+	binary.AppendByteOrder
+}
+
+func utf16Charset(order byteOrder) *charset {
+	return &charset{
+		decode: func(b []byte) []byte {
+			units := make([]uint16, 0, len(b)/2)
+			for i := 0; i+1 < len(b); i += 2 {
+				units = append(units, order.Uint16(b[i:]))
+			}
+
+			out := make([]byte, 0, len(units))
+			for _, r := range utf16.Decode(units) {
+				out = utf8.AppendRune(out, r)
+			}
+			return out
+		},
+		encode: func(b []byte) ([]byte, error) {
+			units := utf16.Encode([]rune(string(b)))
+
+			out := make([]byte, 0, 2*len(units))
+			for _, u := range units {
+				out = order.AppendUint16(out, u)
+			}
+			return out, nil
+		},
+	}
+}
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// sniffer detects byte order marks and UTF-16 documents without one before
+// the XML decoder sees the input, and records the encoding that was used.
+type sniffer struct {
+	// transcoded is set once the input has already been converted to UTF-8,
+	// so the encoding named by the XML declaration must not be applied again.
+	transcoded bool
+	encoding   string
+}
+
+func (s *sniffer) reader(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(4)
+
+	var cs *charset
+	switch {
+	case bytes.HasPrefix(head, bomUTF8):
+		br.Discard(len(bomUTF8))
+		return br, nil
+	case bytes.HasPrefix(head, bomUTF16LE):
+		br.Discard(len(bomUTF16LE))
+		cs, s.encoding = utf16LE, "UTF-16"
+	case bytes.HasPrefix(head, bomUTF16BE):
+		br.Discard(len(bomUTF16BE))
+		cs, s.encoding = utf16BE, "UTF-16BE"
+	case bytes.Equal(head, []byte{'<', 0, '?', 0}):
+		cs, s.encoding = utf16LE, "UTF-16LE"
+	case bytes.Equal(head, []byte{0, '<', 0, '?'}):
+		cs, s.encoding = utf16BE, "UTF-16BE"
+	default:
+		return br, nil
+	}
+
+	data, err := io.ReadAll(br)
+	if err != nil {
+		return nil, err
+	}
+	s.transcoded = true
+
+	return bytes.NewReader(cs.decode(data)), nil
+}
+
+// charsetReader is installed as the xml.Decoder's CharsetReader.
+func (s *sniffer) charsetReader(label string, input io.Reader) (io.Reader, error) {
+	if s.transcoded {
+		return input, nil
+	}
+
+	cs, ok := lookupCharset(label)
+	if !ok {
+		return nil, &EncodingError{Encoding: label}
+	}
+	s.encoding = label
+
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(cs.decode(data)), nil
+}
@@ -0,0 +1,134 @@
+package pom_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+func TestCharset(t *testing.T) {
+	t.Run("Should read and write back ISO-8859-1", func(t *testing.T) {
+		doc := []byte("<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>\n<project><name>Caf\xe9</name></project>")
+
+		m, err := pom.Read(bytes.NewReader(doc))
+		if err != nil {
+			t.Errorf("Expected no errors reading pom, but found: %s", err.Error())
+			return
+		}
+		if m.Name != "Café" {
+			t.Errorf("Expected name Café, but found: %s", m.Name)
+		}
+		if m.Encoding != "ISO-8859-1" {
+			t.Errorf("Expected encoding ISO-8859-1, but found: %s", m.Encoding)
+		}
+
+		var buf bytes.Buffer
+		if err := m.Write(&buf, nil); err != nil {
+			t.Errorf("Expected no errors writing pom, but found: %s", err.Error())
+			return
+		}
+		if !bytes.HasPrefix(buf.Bytes(), []byte("<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>")) {
+			t.Errorf("Expected an ISO-8859-1 declaration, but found: %s", buf.String())
+		}
+		if !bytes.Contains(buf.Bytes(), []byte("<name>Caf\xe9</name>")) {
+			t.Errorf("Expected a latin1 encoded name, but found: %q", buf.String())
+		}
+	})
+
+	t.Run("Should read windows-1252 specific characters", func(t *testing.T) {
+		doc := []byte("<?xml version=\"1.0\" encoding=\"Cp1252\"?><project><name>\x80 \x93q\x94</name></project>")
+
+		m, err := pom.Read(bytes.NewReader(doc))
+		if err != nil {
+			t.Errorf("Expected no errors reading pom, but found: %s", err.Error())
+			return
+		}
+		if m.Name != "€ “q”" {
+			t.Errorf("Expected name € “q”, but found: %s", m.Name)
+		}
+	})
+
+	t.Run("Should skip a UTF-8 byte order mark", func(t *testing.T) {
+		doc := append([]byte{0xEF, 0xBB, 0xBF}, testPomFile...)
+
+		if _, err := pom.Read(bytes.NewReader(doc)); err != nil {
+			t.Errorf("Expected no errors reading pom, but found: %s", err.Error())
+		}
+	})
+
+	t.Run("Should read and write back UTF-16", func(t *testing.T) {
+		var doc []byte
+		doc = append(doc, 0xFF, 0xFE)
+		for _, u := range utf16.Encode([]rune("<?xml version=\"1.0\" encoding=\"UTF-16\"?><project><name>Ωmega</name></project>")) {
+			doc = binary.LittleEndian.AppendUint16(doc, u)
+		}
+
+		m, err := pom.Read(bytes.NewReader(doc))
+		if err != nil {
+			t.Errorf("Expected no errors reading pom, but found: %s", err.Error())
+			return
+		}
+		if m.Name != "Ωmega" {
+			t.Errorf("Expected name Ωmega, but found: %s", m.Name)
+		}
+
+		var buf bytes.Buffer
+		if err := m.Write(&buf, nil); err != nil {
+			t.Errorf("Expected no errors writing pom, but found: %s", err.Error())
+			return
+		}
+		if !bytes.HasPrefix(buf.Bytes(), []byte{0xFF, 0xFE, '<', 0}) {
+			t.Errorf("Expected UTF-16LE output with a byte order mark, but found: %q", buf.String())
+		}
+
+		again, err := pom.Read(&buf)
+		if err != nil {
+			t.Errorf("Expected no errors reading written pom, but found: %s", err.Error())
+			return
+		}
+		if again.Name != "Ωmega" {
+			t.Errorf("Expected name Ωmega, but found: %s", again.Name)
+		}
+	})
+
+	t.Run("Should escape characters the output encoding cannot represent", func(t *testing.T) {
+		m := pom.New()
+		m.Name = "Ωmega"
+
+		var buf bytes.Buffer
+		if err := m.Write(&buf, &pom.WriteOptions{Encoding: "ISO-8859-1"}); err != nil {
+			t.Errorf("Expected no errors writing pom, but found: %s", err.Error())
+			return
+		}
+		if !bytes.Contains(buf.Bytes(), []byte("<name>&#937;mega</name>")) {
+			t.Errorf("Expected a character reference, but found: %s", buf.String())
+		}
+	})
+
+	t.Run("Should not escape characters in comments", func(t *testing.T) {
+		m := pom.New()
+		m.Modules = &pom.Modules{Comment: " Ωmega ", Module: []string{"core"}}
+
+		var buf bytes.Buffer
+		err := m.Write(&buf, &pom.WriteOptions{Encoding: "ISO-8859-1"})
+		var encErr *pom.EncodingError
+		if !errors.As(err, &encErr) || encErr.Rune != 'Ω' || encErr.Line != 2 || encErr.Column != 24 {
+			t.Errorf("Expected an EncodingError for Ω, but found: %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("Expected nothing written, but found: %s", buf.String())
+		}
+	})
+
+	t.Run("Should report unsupported encodings without a position", func(t *testing.T) {
+		err := pom.New().Write(io.Discard, &pom.WriteOptions{Encoding: "EBCDIC"})
+		if err == nil || err.Error() != `pom: unsupported encoding "EBCDIC"` {
+			t.Errorf("Expected an unsupported encoding, but found: %v", err)
+		}
+	})
+}
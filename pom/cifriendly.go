@@ -0,0 +1,313 @@
+package pom
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CIFriendlyProperties are the properties Maven allows in the versions of the
+// projects of a reactor, so that builds can set the version with -D options
+// or in .mvn/maven.config, like ${revision}${sha1}${changelist}.
+var CIFriendlyProperties = []string{"revision", "sha1", "changelist"}
+
+const flattenPlugin = "org.codehaus.mojo:flatten-maven-plugin"
+
+// versionSegment is a literal or a ${...} placeholder of a version.
+type versionSegment struct {
+	text        string
+	placeholder bool
+}
+
+func versionSegments(version string) []versionSegment {
+	var segments []versionSegment
+	for version != "" {
+		start := strings.Index(version, "${")
+		end := -1
+		if start >= 0 {
+			end = strings.IndexByte(version[start:], '}')
+		}
+		if start < 0 || end < 0 {
+			return append(segments, versionSegment{text: version})
+		}
+		if start > 0 {
+			segments = append(segments, versionSegment{text: version[:start]})
+		}
+		segments = append(segments, versionSegment{text: version[start+2 : start+end], placeholder: true})
+		version = version[start+end+1:]
+	}
+	return segments
+}
+
+func isCIFriendlyProperty(name string) bool {
+	for _, p := range CIFriendlyProperties {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// usesCIFriendly reports whether version has a CI-friendly placeholder.
+func usesCIFriendly(version string) bool {
+	for _, s := range versionSegments(version) {
+		if s.placeholder && isCIFriendlyProperty(s.text) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCIFriendly reports whether the placeholders of version are all CI-friendly
+// ones, and it has at least one.
+func isCIFriendly(version string) bool {
+	found := false
+	for _, s := range versionSegments(version) {
+		if s.placeholder {
+			if !isCIFriendlyProperty(s.text) {
+				return false
+			}
+			found = true
+		}
+	}
+	return found
+}
+
+// models decodes the current documents of the projects of r.
+func (r *Reactor) models() (map[*Project]*Model, error) {
+	models := make(map[*Project]*Model)
+	for _, p := range r.Projects {
+		m, err := p.Document.Model()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p.Path, err)
+		}
+		models[p] = m
+	}
+	return models, nil
+}
+
+// lookup finds the properties of p: those of r, then those of Config, then
+// those of p and its parents within the reactor.
+func (r *Reactor) lookup(p *Project, models map[*Project]*Model) func(name string) (string, bool) {
+	return func(name string) (string, bool) {
+		if value, ok := r.Properties[name]; ok {
+			return value, true
+		}
+		if r.Config != nil {
+			if value, ok := r.Config.Properties[name]; ok {
+				return value, true
+			}
+		}
+		if owner := r.propertyOwner(p, models, name); owner != nil {
+			return models[owner].Properties.Fields[name], true
+		}
+		return "", false
+	}
+}
+
+// ResolveVersion returns the version of p with the edits made so far and its
+// expressions resolved, like 1.2.0-SNAPSHOT for ${revision}${changelist}.
+func (r *Reactor) ResolveVersion(p *Project) (string, error) {
+	models, err := r.models()
+	if err != nil {
+		return "", err
+	}
+	return r.resolveVersion(p, models)
+}
+
+// Version returns the resolved version of the root of r.
+func (r *Reactor) Version() (string, error) {
+	return r.ResolveVersion(r.Root())
+}
+
+func (r *Reactor) resolveVersion(p *Project, models map[*Project]*Model) (string, error) {
+	version := versionOf(models[p])
+	resolved := newInterpolator(r.lookup(p, models)).expand(version)
+	if resolved == "" || strings.Contains(resolved, "${") {
+		return "", fmt.Errorf("pom: %s: cannot resolve version %q", p.Path, version)
+	}
+	return resolved, nil
+}
+
+// setCIFriendly sets the CI-friendly properties of expression, the version
+// of the root, so that it resolves to version. The revision gets what the
+// other properties do not hold, and these are cleared when version does not
+// end with their values, like changelist for a release. The changelist is
+// set to -SNAPSHOT for SNAPSHOT versions, as Maven recommends.
+func (r *Reactor) setCIFriendly(expression, version string, models map[*Project]*Model) error {
+	segments := versionSegments(expression)
+	revisions := 0
+	for _, s := range segments {
+		if s.placeholder && s.text == "revision" {
+			revisions++
+		}
+	}
+	if !isCIFriendly(expression) || revisions != 1 {
+		return fmt.Errorf("pom: cannot set version %q, it should have one ${revision} and no other expressions than ${sha1} and ${changelist}", expression)
+	}
+
+	lookup := r.lookup(r.Root(), models)
+	current := make(map[string]string)
+	for _, name := range CIFriendlyProperties {
+		current[name], _ = lookup(name)
+	}
+
+	// match returns the values of the properties that make expression
+	// resolve to version, keeping those of values but the revision.
+	match := func(values map[string]string) (map[string]string, bool) {
+		var prefix, suffix strings.Builder
+		b := &prefix
+		for _, s := range segments {
+			switch {
+			case s.placeholder && s.text == "revision":
+				b = &suffix
+			case s.placeholder:
+				b.WriteString(values[s.text])
+			default:
+				b.WriteString(s.text)
+			}
+		}
+		rest, ok := strings.CutPrefix(version, prefix.String())
+		if !ok {
+			return nil, false
+		}
+		revision, ok := strings.CutSuffix(rest, suffix.String())
+		if !ok || revision == "" {
+			return nil, false
+		}
+
+		matched := map[string]string{"revision": revision}
+		for _, name := range CIFriendlyProperties[1:] {
+			matched[name] = values[name]
+		}
+		return matched, true
+	}
+
+	candidates := []map[string]string{current, {}}
+	if strings.HasSuffix(version, snapshotSuffix) && strings.Contains(expression, "${changelist}") {
+		// The changelist holds -SNAPSHOT by convention.
+		snapshot := map[string]string{"sha1": current["sha1"], "changelist": snapshotSuffix}
+		candidates = append([]map[string]string{snapshot}, candidates...)
+	}
+
+	var values map[string]string
+	for _, c := range candidates {
+		if matched, ok := match(c); ok {
+			values = matched
+			break
+		}
+	}
+	if values == nil {
+		return fmt.Errorf("pom: cannot set version %q to %s", expression, version)
+	}
+
+	for _, name := range CIFriendlyProperties {
+		if values[name] != current[name] {
+			if err := r.setProperty(name, values[name], models); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setProperty sets a property of the root where it is defined: in Config
+// first, then in the root or the closest of its parents within the reactor.
+// Undefined properties are added to the root unless value is empty.
+func (r *Reactor) setProperty(name, value string, models map[*Project]*Model) error {
+	if _, ok := r.Properties[name]; ok {
+		r.Properties[name] = value
+	}
+	if r.Config != nil {
+		if _, ok := r.Config.Properties[name]; ok {
+			r.Config.Set(name, value)
+			return nil
+		}
+	}
+
+	owner := r.propertyOwner(r.Root(), models, name)
+	if owner == nil {
+		if value == "" {
+			return nil
+		}
+		owner = r.Root()
+	}
+	return owner.Document.Set("/project/properties/"+name, value)
+}
+
+// VersionIssue is a use of CI-friendly properties that Maven does not
+// support, or that is likely to break when the version changes.
+type VersionIssue struct {
+	// Path is the file of the POM.
+	Path    string
+	Message string
+}
+
+func (i VersionIssue) String() string {
+	return i.Path + ": " + i.Message
+}
+
+// CheckVersions returns the issues with the versions of the projects of r:
+// versions that cannot be resolved, CI-friendly properties outside of
+// versions, parent versions that do not use the same placeholders as the
+// parent, modules repeating the version of their parent, and roots that
+// need the flatten-maven-plugin to install or deploy POMs with resolved
+// versions.
+func (r *Reactor) CheckVersions() ([]VersionIssue, error) {
+	models, err := r.models()
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []VersionIssue
+	report := func(p *Project, format string, args ...any) {
+		issues = append(issues, VersionIssue{Path: p.Path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, p := range r.Projects {
+		m := models[p]
+		if _, err := r.resolveVersion(p, models); err != nil {
+			report(p, "version %q cannot be resolved, the properties it uses are not defined", versionOf(m))
+		}
+		if usesCIFriendly(m.GroupId) || usesCIFriendly(m.ArtifactId) {
+			report(p, "CI-friendly properties are only supported in versions")
+		}
+
+		if m.Parent == nil {
+			continue
+		}
+		parent := r.project(m.Parent.GroupId, m.Parent.ArtifactId)
+		if parent == nil {
+			continue
+		}
+		expression := versionOf(models[parent])
+		switch {
+		case usesCIFriendly(expression) && m.Parent.Version != expression:
+			report(p, "parent version %q should be %q, like the version of the parent", m.Parent.Version, expression)
+		case !usesCIFriendly(expression) && usesCIFriendly(m.Parent.Version):
+			report(p, "parent version %q uses CI-friendly properties, but the version of the parent is %q", m.Parent.Version, expression)
+		}
+		if usesCIFriendly(expression) && m.Version != "" && !usesCIFriendly(m.Version) {
+			if resolved, err := r.resolveVersion(parent, models); err == nil && resolved == m.Version {
+				report(p, "version %q repeats the version of the parent, remove it to inherit %q", m.Version, expression)
+			}
+		}
+	}
+
+	root := models[r.Root()]
+	if usesCIFriendly(versionOf(root)) && root.ModelVersion != ModelVersion410 && !hasBuildPlugin(root, flattenPlugin) {
+		report(r.Root(), "version %q needs the flatten-maven-plugin, or installed and deployed POMs keep the properties", versionOf(root))
+	}
+	return issues, nil
+}
+
+func hasBuildPlugin(m *Model, key string) bool {
+	if m.Build == nil || m.Build.Plugins == nil {
+		return false
+	}
+	for i := range m.Build.Plugins.Plugin {
+		if m.Build.Plugins.Plugin[i].Key() == key {
+			return true
+		}
+	}
+	return false
+}
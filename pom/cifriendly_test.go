@@ -0,0 +1,81 @@
+package pom_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCIFriendly(t *testing.T) {
+	t.Run("Should resolve versions with maven.config taking precedence", func(t *testing.T) {
+		r := copyReactor(t, "cifriendly")
+
+		if r.Config == nil || r.Config.Properties["changelist"] != "-SNAPSHOT" {
+			t.Fatalf("Expected the properties of maven.config, but found: %v", r.Config)
+		}
+		for _, p := range r.Projects {
+			if v, err := r.ResolveVersion(p); err != nil || v != "1.2.0-SNAPSHOT" {
+				t.Errorf("Expected 1.2.0-SNAPSHOT for %s, but found: %s %v", p.Model.ArtifactId, v, err)
+			}
+		}
+
+		r.Properties = map[string]string{"sha1": "abc", "revision": "9"}
+		if v, _ := r.Version(); v != "9-SNAPSHOT" {
+			t.Errorf("Expected user properties to take precedence, but found: %s", v)
+		}
+	})
+
+	t.Run("Should set the properties where they are defined", func(t *testing.T) {
+		r := copyReactor(t, "cifriendly")
+
+		if err := r.SetVersion("1.3.0"); err != nil {
+			t.Fatalf("Expected no errors setting the version, but found: %s", err.Error())
+		}
+		if v, _ := r.Version(); v != "1.3.0" {
+			t.Errorf("Expected 1.3.0, but found: %s", v)
+		}
+
+		root := projectText(t, r, "ci")
+		if !strings.Contains(root, "<version>${revision}${changelist}</version>") || !strings.Contains(root, "<revision>1.3.0</revision>") {
+			t.Errorf("Expected the revision to be set and the placeholders kept, but found:\n%s", root)
+		}
+		if string(r.Config.Bytes()) != "--batch-mode\n-Dchangelist=\n" {
+			t.Errorf("Expected the changelist to be cleared in maven.config, but found: %q", r.Config.Bytes())
+		}
+		if app := projectText(t, r, "app"); strings.Count(app, "<version>1.3.0</version>") != 2 {
+			t.Errorf("Expected the literal versions of app to be set, but found:\n%s", app)
+		}
+
+		if err := r.SetVersion("1.3.1-SNAPSHOT"); err != nil {
+			t.Fatalf("Expected no errors setting the version, but found: %s", err.Error())
+		}
+		if string(r.Config.Bytes()) != "--batch-mode\n-Dchangelist=-SNAPSHOT\n" || !strings.Contains(projectText(t, r, "ci"), "<revision>1.3.1</revision>") {
+			t.Errorf("Expected the changelist to be set back, but found: %q", r.Config.Bytes())
+		}
+
+		if err := r.Write(); err != nil {
+			t.Fatalf("Expected no errors writing the reactor, but found: %s", err.Error())
+		}
+		if data, _ := os.ReadFile(r.Config.Path); string(data) != "--batch-mode\n-Dchangelist=-SNAPSHOT\n" {
+			t.Errorf("Expected maven.config to be written, but found: %q", data)
+		}
+	})
+
+	t.Run("Should report inconsistent placeholders", func(t *testing.T) {
+		r := copyReactor(t, "cifriendly")
+
+		issues, err := r.CheckVersions()
+		if err != nil {
+			t.Fatalf("Expected no errors checking versions, but found: %s", err.Error())
+		}
+		var messages []string
+		for _, i := range issues {
+			messages = append(messages, i.Message)
+		}
+		expected := `parent version "1.2.0-SNAPSHOT" should be "${revision}${changelist}", like the version of the parent
+version "1.2.0-SNAPSHOT" repeats the version of the parent, remove it to inherit "${revision}${changelist}"`
+		if strings.Join(messages, "\n") != expected {
+			t.Errorf("Expected:\n%s\nbut found:\n%s", expected, strings.Join(messages, "\n"))
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package pom
+
+import "reflect"
+
+// Clone returns a deep copy of m that shares no memory with it.
+func (m *Model) Clone() *Model {
+	if m == nil {
+		return nil
+	}
+
+	var c Model
+	deepCopy(reflect.ValueOf(&c).Elem(), reflect.ValueOf(m).Elem())
+	return &c
+}
+
+// Clone returns a deep copy of p, including the document order of its keys.
+func (p *Properties) Clone() *Properties {
+	if p == nil {
+		return nil
+	}
+
+	c := &Properties{
+		Comment: p.Comment,
+		Fields:  make(map[string]string, len(p.Fields)),
+		order:   append([]string(nil), p.order...),
+	}
+	for k, v := range p.Fields {
+		c.Fields[k] = v
+	}
+	return c
+}
+
+var propertiesType = reflect.TypeOf(&Properties{})
+
+func deepCopy(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		if src.Type() == propertiesType {
+			dst.Set(reflect.ValueOf(src.Interface().(*Properties).Clone()))
+			return
+		}
+		dst.Set(reflect.New(src.Type().Elem()))
+		deepCopy(dst.Elem(), src.Elem())
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		dst.Set(reflect.MakeSlice(src.Type(), src.Len(), src.Len()))
+		for i := 0; i < src.Len(); i++ {
+			deepCopy(dst.Index(i), src.Index(i))
+		}
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		dst.Set(reflect.MakeMapWithSize(src.Type(), src.Len()))
+		iter := src.MapRange()
+		for iter.Next() {
+			v := reflect.New(src.Type().Elem()).Elem()
+			deepCopy(v, iter.Value())
+			dst.SetMapIndex(iter.Key(), v)
+		}
+	case reflect.Struct:
+		// copy unexported fields as is, then replace the exported ones
+		dst.Set(src)
+		for i := 0; i < src.NumField(); i++ {
+			if src.Type().Field(i).IsExported() {
+				deepCopy(dst.Field(i), src.Field(i))
+			}
+		}
+	default:
+		dst.Set(src)
+	}
+}
@@ -0,0 +1,260 @@
+package pom_test
+
+import (
+	"encoding/xml"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+type xsdSchema struct {
+	Elements     []xsdElement     `xml:"element"`
+	ComplexTypes []xsdComplexType `xml:"complexType"`
+}
+
+type xsdComplexType struct {
+	Name       string         `xml:"name,attr"`
+	All        []xsdElement   `xml:"all>element"`
+	Sequence   []xsdElement   `xml:"sequence>element"`
+	Any        *struct{}      `xml:"sequence>any"`
+	Attributes []xsdAttribute `xml:"attribute"`
+}
+
+type xsdElement struct {
+	Name        string          `xml:"name,attr"`
+	Type        string          `xml:"type,attr"`
+	ComplexType *xsdComplexType `xml:"complexType"`
+}
+
+type xsdAttribute struct {
+	Name string `xml:"name,attr"`
+}
+
+func (ct *xsdComplexType) element(name string) (xsdElement, bool) {
+	for _, el := range append(ct.All, ct.Sequence...) {
+		if el.Name == name {
+			return el, true
+		}
+	}
+	return xsdElement{}, false
+}
+
+func (ct *xsdComplexType) hasAttribute(name string) bool {
+	for _, attr := range ct.Attributes {
+		if attr.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func loadSchema(t *testing.T, path string) (*xsdSchema, map[string]*xsdComplexType) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected no errors reading %s, but found: %s", path, err.Error())
+	}
+
+	var schema xsdSchema
+	if err := xml.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("Expected no errors unmarshalling %s, but found: %s", path, err.Error())
+	}
+
+	types := make(map[string]*xsdComplexType)
+	for i := range schema.ComplexTypes {
+		types[schema.ComplexTypes[i].Name] = &schema.ComplexTypes[i]
+	}
+
+	return &schema, types
+}
+
+type goField struct {
+	name string
+	attr bool
+	typ  reflect.Type
+}
+
+// xmlFields lists the elements and attributes a struct decodes, including
+// those of embedded structs.
+func xmlFields(t reflect.Type) []goField {
+	var fields []goField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, hasTag := f.Tag.Lookup("xml")
+
+		if f.Anonymous && !hasTag {
+			fields = append(fields, xmlFields(f.Type)...)
+			continue
+		}
+		if f.Name == "XMLName" || tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			continue
+		}
+		fields = append(fields, goField{name: name, attr: strings.Contains(opts, "attr"), typ: f.Type})
+	}
+	return fields
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return t
+}
+
+var unmarshaler = reflect.TypeOf((*xml.Unmarshaler)(nil)).Elem()
+
+// modelVersion410 lists the struct tags added for modelVersion 4.1.0, which
+// the 4.0.0 schema does not define, keyed by struct name and tag.
+var modelVersion410 = map[string]bool{
+	"Model.root":           true,
+	"Model.subprojects":    true,
+	"Profile.subprojects":  true,
+	"Build.sources":        true,
+	"Activation.condition": true,
+	"Execution.priority":   true,
+}
+
+// checkTags walks a Go struct and the schema type it decodes side by side,
+// reporting every struct tag the schema does not define and every schema
+// element or attribute without a struct field.
+func checkTags(t *testing.T, types map[string]*xsdComplexType, goType reflect.Type, ct *xsdComplexType, path string) {
+	if ct.Any != nil {
+		return
+	}
+
+	fields := xmlFields(goType)
+	declared := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		declared[f.name] = true
+	}
+	for _, el := range append(ct.All, ct.Sequence...) {
+		if !declared[el.Name] {
+			t.Errorf("Expected element %s/%s to have a struct field in %s", path, el.Name, goType.Name())
+		}
+	}
+	for _, attr := range ct.Attributes {
+		if !declared[attr.Name] {
+			t.Errorf("Expected attribute %s/@%s to have a struct field in %s", path, attr.Name, goType.Name())
+		}
+	}
+
+	for _, f := range fields {
+		fieldPath := path + "/" + f.name
+		if modelVersion410[goType.Name()+"."+f.name] {
+			continue
+		}
+
+		if f.attr {
+			if !ct.hasAttribute(f.name) {
+				t.Errorf("Expected attribute %s to be defined by the schema", fieldPath)
+			}
+			continue
+		}
+
+		el, ok := ct.element(f.name)
+		if !ok {
+			t.Errorf("Expected element %s to be defined by the schema", fieldPath)
+			continue
+		}
+
+		child := indirect(f.typ)
+		if child.Kind() != reflect.Struct || reflect.PointerTo(child).Implements(unmarshaler) {
+			continue
+		}
+
+		switch {
+		case el.ComplexType != nil:
+			checkTags(t, types, child, el.ComplexType, fieldPath)
+		case types[el.Type] != nil:
+			checkTags(t, types, child, types[el.Type], fieldPath)
+		default:
+			t.Errorf("Expected element %s to have simple type %s, but found struct %s", fieldPath, el.Type, child.Name())
+		}
+	}
+}
+
+// schemaNodes lists every element and attribute the schema type named name
+// defines, keyed by type name. Anonymous types are named after the element
+// holding them.
+func schemaNodes(types map[string]*xsdComplexType, ct *xsdComplexType, name string, nodes map[string]bool) {
+	if ct.Any != nil {
+		return
+	}
+	for _, attr := range ct.Attributes {
+		nodes[name+"/@"+attr.Name] = true
+	}
+	for _, el := range append(ct.All, ct.Sequence...) {
+		nodes[name+"/"+el.Name] = true
+		if el.ComplexType != nil {
+			schemaNodes(types, el.ComplexType, name+"/"+el.Name, nodes)
+		}
+	}
+}
+
+type docNode struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Children []docNode  `xml:",any"`
+}
+
+// documentNodes marks the schema nodes used by a document element of type
+// ct, named as in schemaNodes.
+func documentNodes(types map[string]*xsdComplexType, ct *xsdComplexType, name string, node docNode, nodes map[string]bool) {
+	if ct.Any != nil {
+		return
+	}
+	for _, attr := range node.Attrs {
+		nodes[name+"/@"+attr.Name.Local] = true
+	}
+	for _, child := range node.Children {
+		nodes[name+"/"+child.XMLName.Local] = true
+
+		el, ok := ct.element(child.XMLName.Local)
+		switch {
+		case !ok:
+		case el.ComplexType != nil:
+			documentNodes(types, el.ComplexType, name+"/"+el.Name, child, nodes)
+		case types[el.Type] != nil:
+			documentNodes(types, types[el.Type], el.Type, child, nodes)
+		}
+	}
+}
+
+func TestConformance(t *testing.T) {
+	schema, types := loadSchema(t, "testdata/maven-4.0.0.xsd")
+
+	t.Run("Should match the maven-4.0.0 schema exactly", func(t *testing.T) {
+		checkTags(t, types, reflect.TypeOf(pom.Model{}), types["Model"], "/project")
+	})
+
+	t.Run("Should cover every schema element in the round trip corpus", func(t *testing.T) {
+		expected := make(map[string]bool)
+		for name, ct := range types {
+			schemaNodes(types, ct, name, expected)
+		}
+
+		data, err := os.ReadFile("testdata/roundtrip/full.xml")
+		if err != nil {
+			t.Fatalf("Expected no errors reading the corpus, but found: %s", err.Error())
+		}
+		var root docNode
+		if err := xml.Unmarshal(data, &root); err != nil {
+			t.Fatalf("Expected no errors unmarshalling the corpus, but found: %s", err.Error())
+		}
+
+		found := make(map[string]bool)
+		documentNodes(types, types[schema.Elements[0].Type], schema.Elements[0].Type, root, found)
+		for node := range expected {
+			if !found[node] {
+				t.Errorf("Expected %s to appear in testdata/roundtrip/full.xml", node)
+			}
+		}
+	})
+}
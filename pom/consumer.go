@@ -0,0 +1,157 @@
+package pom
+
+import (
+	"reflect"
+)
+
+// ConsumerMode selects what a consumer POM keeps of the build POM, following
+// the flatten modes of the flatten-maven-plugin.
+type ConsumerMode int
+
+const (
+	// ConsumerDefaults keeps the coordinates, packaging, licenses, repositories
+	// and the dependencies that are not test scoped.
+	ConsumerDefaults ConsumerMode = iota
+	// ConsumerOSSRH also keeps the name, description, url, developers and scm
+	// required to publish to Maven Central.
+	ConsumerOSSRH
+	// ConsumerBOM also keeps the dependency management and properties of a
+	// bill of materials, and keeps the parent reference by default.
+	ConsumerBOM
+	// ConsumerResolveCiFriendliesOnly keeps the whole POM and only replaces the
+	// ${revision}, ${sha1} and ${changelist} expressions.
+	ConsumerResolveCiFriendliesOnly
+)
+
+// ParentPolicy selects what a consumer POM does with the parent reference.
+type ParentPolicy int
+
+const (
+	// ParentDefault keeps the parent in ConsumerBOM mode and resolves it
+	// otherwise.
+	ParentDefault ParentPolicy = iota
+	// ParentResolve takes everything inherited from the parent into the
+	// consumer POM and removes the reference.
+	ParentResolve
+	// ParentKeep keeps the reference, with its coordinates interpolated, so
+	// that consumers still inherit from the parent.
+	ParentKeep
+)
+
+// ConsumerOptions configures Consumer.
+type ConsumerOptions struct {
+	Mode ConsumerMode
+	// Parent is ignored in ConsumerResolveCiFriendliesOnly mode, which always
+	// keeps the parent.
+	Parent ParentPolicy
+	// EffectiveOptions locate the parents, supply the values of expressions
+	// like ${revision} and activate profiles.
+	EffectiveOptions
+}
+
+// Consumer returns the POM to deploy for consumers of the project built by
+// m, like the flatten-maven-plugin and Maven 4 produce: expressions resolved,
+// managed dependency versions filled in, and the build, reporting, profiles
+// and modules removed. A 4.1.0 model is converted to 4.0.0 so that consumers
+// using Maven 3 can read it. m is not modified.
+//
+// A nil opts is the same as empty ConsumerOptions.
+func (m *Model) Consumer(opts *ConsumerOptions) (*Model, error) {
+	if opts == nil {
+		opts = &ConsumerOptions{}
+	}
+
+	eff, err := m.Effective(&opts.EffectiveOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Mode == ConsumerResolveCiFriendliesOnly {
+		return resolveCiFriendlies(m, eff, opts.Properties), nil
+	}
+
+	keepParent := m.Parent != nil &&
+		(opts.Parent == ParentKeep || opts.Parent == ParentDefault && opts.Mode == ConsumerBOM)
+
+	c := &Model{
+		ModelVersion: eff.ModelVersion,
+		GroupId:      eff.GroupId,
+		ArtifactId:   eff.ArtifactId,
+		Version:      eff.Version,
+		Packaging:    eff.Packaging,
+		Licenses:     eff.Licenses,
+		Dependencies: consumerDependencies(eff.Dependencies),
+		Repositories: eff.Repositories,
+		Encoding:     m.Encoding,
+	}
+
+	if opts.Mode == ConsumerOSSRH || opts.Mode == ConsumerBOM {
+		c.Name = eff.Name
+		c.Description = eff.Description
+		c.Url = eff.Url
+		c.Developers = eff.Developers
+		c.Scm = eff.Scm
+	}
+
+	if opts.Mode == ConsumerBOM {
+		c.DependencyManagement = eff.DependencyManagement
+		c.Properties = eff.Properties
+	}
+
+	if keepParent {
+		// the parent keeps providing what it manages, so only what the
+		// project declares itself is kept, imports included
+		own := m.Clone()
+		interpolateModel(own, eff, opts.Properties)
+
+		c.Parent = &Parent{GroupId: own.Parent.GroupId, ArtifactId: own.Parent.ArtifactId, Version: own.Parent.Version}
+		if opts.Mode == ConsumerBOM {
+			c.DependencyManagement = own.DependencyManagement
+			c.Properties = own.Properties
+		}
+	}
+
+	if c.ModelVersion == ModelVersion410 {
+		// the issues only concern elements a consumer POM does not have
+		c, _, _ = c.Convert(ModelVersion400)
+	}
+
+	return c, nil
+}
+
+// consumerDependencies returns the dependencies that matter to consumers,
+// leaving out those only used by the tests of the project.
+func consumerDependencies(deps *Dependencies) *Dependencies {
+	if deps == nil {
+		return nil
+	}
+
+	kept := &Dependencies{}
+	for _, d := range deps.Dependency {
+		switch d.Scope {
+		case ScopeTest, ScopeTestOnly, ScopeTestRuntime:
+			continue
+		}
+		kept.Dependency = append(kept.Dependency, d)
+	}
+	if len(kept.Dependency) == 0 {
+		return nil
+	}
+	return kept
+}
+
+// resolveCiFriendlies returns a copy of m where only the CI friendly
+// expressions are replaced, with their values taken from eff.
+func resolveCiFriendlies(m, eff *Model, props map[string]string) *Model {
+	full := newInterpolator(modelLookup(eff, props))
+	in := newInterpolator(func(name string) (string, bool) {
+		if !isCIFriendlyProperty(name) {
+			return "", false
+		}
+		return full.value(name)
+	})
+
+	c := m.Clone()
+	eachString(reflect.ValueOf(c), in.expand)
+	return c
+}
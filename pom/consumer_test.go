@@ -0,0 +1,126 @@
+package pom_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+func consumerCore(t *testing.T, opts pom.ConsumerOptions) *pom.Model {
+	t.Helper()
+
+	opts.Dir = filepath.Join("testdata", "effective", "core")
+	opts.Resolver = effectiveRepository
+	c, err := readCore(t).Consumer(&opts)
+	if err != nil {
+		t.Fatalf("Expected no errors building the consumer pom, but found: %s", err.Error())
+	}
+	return c
+}
+
+func TestConsumer(t *testing.T) {
+	t.Run("Should flatten the pom by default", func(t *testing.T) {
+		c := consumerCore(t, pom.ConsumerOptions{
+			EffectiveOptions: pom.EffectiveOptions{Properties: map[string]string{"revision": "1.2.0"}},
+		})
+
+		if c.Parent != nil || c.Build != nil || c.Profiles != nil || c.Name != "" {
+			t.Errorf("Expected parent, build, profiles and name to be removed, but found: %+v", c)
+		}
+		if c.GroupId != "org.example" || c.Version != "1.2.0" {
+			t.Errorf("Expected org.example 1.2.0, but found: %s %s", c.GroupId, c.Version)
+		}
+		if c.Licenses == nil {
+			t.Errorf("Expected the inherited licenses")
+		}
+		if findDependency(c, "junit-jupiter") != nil {
+			t.Errorf("Expected test dependencies to be removed")
+		}
+		if d := findDependency(c, "guava"); d == nil || d.Version != "33.0.0-jre" {
+			t.Errorf("Expected guava with its managed version, but found: %+v", d)
+		}
+
+		var buf bytes.Buffer
+		if err := c.Write(&buf, nil); err != nil {
+			t.Fatalf("Expected no errors writing pom, but found: %s", err.Error())
+		}
+		decodeStrict(t, buf.Bytes())
+	})
+
+	t.Run("Should keep the elements required by OSSRH", func(t *testing.T) {
+		c := consumerCore(t, pom.ConsumerOptions{Mode: pom.ConsumerOSSRH})
+
+		if c.Name != "Example core" || c.Description != "Parent of the example projects" || c.Developers == nil {
+			t.Errorf("Expected name, description and developers, but found: %q %q %+v", c.Name, c.Description, c.Developers)
+		}
+		if c.Scm == nil || c.Scm.Url != "https://example.org/project/core" {
+			t.Errorf("Expected the scm of the module, but found: %+v", c.Scm)
+		}
+		if c.DependencyManagement != nil {
+			t.Errorf("Expected no dependency management")
+		}
+	})
+
+	t.Run("Should keep the parent and own dependency management of a bom", func(t *testing.T) {
+		m, err := pom.ReadFile(filepath.Join("testdata", "effective", "pom.xml"))
+		if err != nil {
+			t.Fatalf("Expected no errors reading pom, but found: %s", err.Error())
+		}
+
+		c, err := m.Consumer(&pom.ConsumerOptions{
+			Mode:             pom.ConsumerBOM,
+			EffectiveOptions: pom.EffectiveOptions{Resolver: effectiveRepository},
+		})
+		if err != nil {
+			t.Fatalf("Expected no errors building the consumer pom, but found: %s", err.Error())
+		}
+
+		if c.Parent == nil || c.Parent.ArtifactId != "corporate" || c.Version != "1.2.0-SNAPSHOT" {
+			t.Errorf("Expected the corporate parent to be kept, but found: %+v %s", c.Parent, c.Version)
+		}
+		deps := c.DependencyManagement.Dependencies.Dependency
+		if len(deps) != 2 || deps[0].Version != "2.0.9" || deps[1].Scope != pom.ScopeImport {
+			t.Errorf("Expected the interpolated own dependency management, but found: %+v", deps)
+		}
+		if c.Properties.Fields["java.release"] != "" || c.Properties.Fields["revision"] != "1.2.0-SNAPSHOT" {
+			t.Errorf("Expected only the own properties, but found: %v", c.Properties.Fields)
+		}
+		if c.Modules != nil {
+			t.Errorf("Expected modules to be removed")
+		}
+	})
+
+	t.Run("Should only resolve CI friendly versions", func(t *testing.T) {
+		c := consumerCore(t, pom.ConsumerOptions{Mode: pom.ConsumerResolveCiFriendliesOnly})
+
+		if c.Parent == nil || c.Parent.Version != "1.2.0-SNAPSHOT" {
+			t.Errorf("Expected the parent version to be resolved, but found: %+v", c.Parent)
+		}
+		if c.Build == nil || c.Profiles == nil || c.GroupId != "" {
+			t.Errorf("Expected the rest of the pom to be kept")
+		}
+		if d := findDependency(c, "guava"); d.Version != "" {
+			t.Errorf("Expected managed versions not to be filled in, but found: %s", d.Version)
+		}
+	})
+
+	t.Run("Should convert a 4.1.0 pom to 4.0.0", func(t *testing.T) {
+		m := readVersion410(t)
+
+		c, err := m.Consumer(nil)
+		if err != nil {
+			t.Fatalf("Expected no errors building the consumer pom, but found: %s", err.Error())
+		}
+		if c.ModelVersion != pom.ModelVersion400 || c.Packaging != "pom" || c.Root != "" {
+			t.Errorf("Expected a 4.0.0 pom packaged pom, but found: %s %s", c.ModelVersion, c.Packaging)
+		}
+		if d := findDependency(c, "lombok"); d == nil || d.Scope != pom.ScopeProvided {
+			t.Errorf("Expected compile-only to become provided, but found: %+v", d)
+		}
+		if findDependency(c, "junit-jupiter-engine") != nil {
+			t.Errorf("Expected test-runtime dependencies to be removed")
+		}
+	})
+}
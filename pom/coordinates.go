@@ -0,0 +1,36 @@
+package pom
+
+// DefaultPluginGroupId is the groupId of plugins that do not declare one.
+const DefaultPluginGroupId = "org.apache.maven.plugins"
+
+// DefaultExecutionId is the id of executions that do not declare one.
+const DefaultExecutionId = "default"
+
+// ManagementKey returns the groupId:artifactId:type:classifier identity of d
+// that matches it with its dependency management, with the type defaulting to
+// jar.
+func (d *Dependency) ManagementKey() string {
+	return d.GroupId + ":" + d.ArtifactId + ":" + orDefault(d.Type, "jar") + ":" + d.Classifier
+}
+
+// Key returns the groupId:artifactId identity of p, with the groupId
+// defaulting to DefaultPluginGroupId.
+func (p *Plugin) Key() string {
+	return pluginKey(p.GroupId, p.ArtifactId)
+}
+
+// Key returns the groupId:artifactId identity of p, like Plugin.Key.
+func (p *ReportPlugin) Key() string {
+	return pluginKey(p.GroupId, p.ArtifactId)
+}
+
+func pluginKey(groupId, artifactId string) string {
+	return orDefault(groupId, DefaultPluginGroupId) + ":" + artifactId
+}
+
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
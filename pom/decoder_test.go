@@ -0,0 +1,123 @@
+package pom_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+const misspelledPom = `<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <modelVersion>4.0.0</modelVersion>
+  <artifactId>demo</artifactId>
+  <dependencies>
+    <dependancy>
+      <groupId>junit</groupId>
+    </dependancy>
+    <dependency scoped="yes">
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api<extra/></artifactId>
+    </dependency>
+  </dependencies>
+  <build>
+    <plugins>
+      <plugin>
+        <configuration><anything goes="here"/></configuration>
+      </plugin>
+    </plugins>
+  </build>
+</project>`
+
+func TestDecoder(t *testing.T) {
+	t.Run("Should report every unrecognized node in strict mode", func(t *testing.T) {
+		d := pom.NewDecoder(strings.NewReader(misspelledPom))
+		d.Mode = pom.Strict
+
+		var m pom.Model
+		err := d.Decode(&m)
+
+		var unErr *pom.UnrecognizedError
+		if !errors.As(err, &unErr) {
+			t.Errorf("Expected an UnrecognizedError, but found: %v", err)
+			return
+		}
+
+		expected := []pom.Unrecognized{
+			{Path: "/project/dependencies/dependancy", Line: 5, Column: 5},
+			{Path: "/project/dependencies/dependency/@scoped", Attr: true, Line: 8, Column: 5},
+			{Path: "/project/dependencies/dependency/artifactId/extra", Line: 10, Column: 28},
+		}
+		if len(unErr.Nodes) != len(expected) {
+			t.Errorf("Expected %d unrecognized nodes, but found: %+v", len(expected), unErr.Nodes)
+			return
+		}
+		for i, n := range unErr.Nodes {
+			if n != expected[i] {
+				t.Errorf("Expected %+v, but found: %+v", expected[i], n)
+			}
+		}
+	})
+
+	t.Run("Should accept a valid pom in strict mode", func(t *testing.T) {
+		d := pom.NewDecoder(bytes.NewReader(testPomFile))
+		d.Mode = pom.Strict
+
+		var m pom.Model
+		if err := d.Decode(&m); err != nil {
+			t.Errorf("Expected no errors decoding pom, but found: %s", err.Error())
+		}
+	})
+
+	t.Run("Should preserve unrecognized nodes in lenient mode", func(t *testing.T) {
+		d := pom.NewDecoder(strings.NewReader(misspelledPom))
+
+		var m pom.Model
+		if err := d.Decode(&m); err != nil {
+			t.Errorf("Expected no errors decoding pom, but found: %s", err.Error())
+			return
+		}
+		if len(d.Unrecognized()) != 3 {
+			t.Errorf("Expected 3 unrecognized nodes, but found: %+v", d.Unrecognized())
+		}
+		if len(m.AnyAttrs) != 0 {
+			t.Errorf("Expected namespace declarations to be dropped, but found: %+v", m.AnyAttrs)
+		}
+
+		var buf bytes.Buffer
+		if err := m.Write(&buf, nil); err != nil {
+			t.Errorf("Expected no errors writing pom, but found: %s", err.Error())
+			return
+		}
+
+		out := buf.String()
+		for _, want := range []string{"<dependancy>", "<groupId>junit</groupId>", `<dependency scoped="yes">`, `<anything goes="here"></anything>`} {
+			if !strings.Contains(out, want) {
+				t.Errorf("Expected output to contain %s, but found: %s", want, out)
+			}
+		}
+	})
+
+	t.Run("Should decode fields whose tags were misspelled", func(t *testing.T) {
+		m, err := pom.Read(strings.NewReader(`<project>
+  <developers><developer><timezone>Europe/Berlin</timezone></developer></developers>
+  <repositories><repository><releases><updatePolicy>daily</updatePolicy></releases></repository></repositories>
+  <pluginRepositories><pluginRepository><id>central</id></pluginRepository></pluginRepositories>
+</project>`))
+		if err != nil {
+			t.Errorf("Expected no errors reading pom, but found: %s", err.Error())
+			return
+		}
+
+		if tz := m.Developers.Developer[0].Timezone; tz != "Europe/Berlin" {
+			t.Errorf("Expected timezone Europe/Berlin, but found: %s", tz)
+		}
+		if policy := m.Repositories.Repository[0].Releases.UpdatePolicy; policy != "daily" {
+			t.Errorf("Expected updatePolicy daily, but found: %s", policy)
+		}
+		if id := m.PluginRepositories.PluginRepository[0].Id; id != "central" {
+			t.Errorf("Expected plugin repository central, but found: %s", id)
+		}
+	})
+}
@@ -0,0 +1,194 @@
+// Package descriptor reads Maven plugin descriptors, the
+// META-INF/maven/plugin.xml files of plugin jars that describe their goals,
+// and checks the configuration of the plugins of a POM against them.
+//
+// The descriptor of a plugin of a POM is found in the local repository by
+// Lookup, or through a Resolver that keeps the descriptors it read and
+// gives the default phases of goals to pom.Model.Plan:
+//
+//	r := descriptor.NewResolver(repository)
+//	d, err := r.Lookup(plugin)
+//	...
+//	for _, p := range d.Validate(plugin) {
+//		fmt.Println(p)
+//	}
+package descriptor
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+// Path is the location of the descriptor in a plugin jar.
+const Path = "META-INF/maven/plugin.xml"
+
+// Descriptor is a plugin descriptor.
+type Descriptor struct {
+	XMLName              xml.Name     `xml:"plugin"`
+	Name                 string       `xml:"name,omitempty"`
+	Description          string       `xml:"description,omitempty"`
+	GroupId              string       `xml:"groupId"`
+	ArtifactId           string       `xml:"artifactId"`
+	Version              string       `xml:"version"`
+	GoalPrefix           string       `xml:"goalPrefix,omitempty"`
+	IsolatedRealm        bool         `xml:"isolatedRealm,omitempty"`
+	InheritedByDefault   bool         `xml:"inheritedByDefault,omitempty"`
+	RequiredJavaVersion  string       `xml:"requiredJavaVersion,omitempty"`
+	RequiredMavenVersion string       `xml:"requiredMavenVersion,omitempty"`
+	Mojos                []Mojo       `xml:"mojos>mojo"`
+	Dependencies         []Dependency `xml:"dependencies>dependency,omitempty"`
+}
+
+// Mojo is a goal of a plugin.
+type Mojo struct {
+	Goal        string `xml:"goal"`
+	Description string `xml:"description,omitempty"`
+	// RequiresDependencyResolution and RequiresDependencyCollection are the
+	// scope of the dependencies of the project the goal needs resolved, or
+	// only collected, like compile or test.
+	RequiresDependencyResolution string `xml:"requiresDependencyResolution,omitempty"`
+	RequiresDependencyCollection string `xml:"requiresDependencyCollection,omitempty"`
+	RequiresDirectInvocation     bool   `xml:"requiresDirectInvocation,omitempty"`
+	RequiresProject              bool   `xml:"requiresProject,omitempty"`
+	RequiresReports              bool   `xml:"requiresReports,omitempty"`
+	RequiresOnline               bool   `xml:"requiresOnline,omitempty"`
+	Aggregator                   bool   `xml:"aggregator,omitempty"`
+	InheritedByDefault           bool   `xml:"inheritedByDefault,omitempty"`
+	// Phase is the phase the goal runs in when its execution sets none.
+	Phase string `xml:"phase,omitempty"`
+	// ExecutePhase, ExecuteGoal and ExecuteLifecycle are what the goal forks
+	// before it runs.
+	ExecutePhase          string `xml:"executePhase,omitempty"`
+	ExecuteGoal           string `xml:"executeGoal,omitempty"`
+	ExecuteLifecycle      string `xml:"executeLifecycle,omitempty"`
+	Implementation        string `xml:"implementation"`
+	Language              string `xml:"language,omitempty"`
+	InstantiationStrategy string `xml:"instantiationStrategy,omitempty"`
+	ExecutionStrategy     string `xml:"executionStrategy,omitempty"`
+	// ThreadSafe is whether the goal supports parallel builds.
+	ThreadSafe bool        `xml:"threadSafe,omitempty"`
+	Since      string      `xml:"since,omitempty"`
+	Deprecated string      `xml:"deprecated,omitempty"`
+	Parameters []Parameter `xml:"parameters>parameter"`
+	// Configuration holds the expressions and default values of the
+	// parameters, in elements named after them.
+	Configuration *pom.DOM `xml:"configuration,omitempty"`
+}
+
+// Parameter is a parameter of a goal.
+type Parameter struct {
+	Name  string `xml:"name"`
+	Alias string `xml:"alias,omitempty"`
+	// Type is the Java type of the parameter, like java.lang.String,
+	// boolean or java.util.List.
+	Type string `xml:"type"`
+	// Required is whether the parameter must have a value, given by the
+	// configuration of the plugin, its expression or its default value.
+	Required bool `xml:"required"`
+	// Editable is false for read-only parameters, which cannot be
+	// configured, and true by default.
+	Editable    string `xml:"editable,omitempty"`
+	Description string `xml:"description,omitempty"`
+	Since       string `xml:"since,omitempty"`
+	Deprecated  string `xml:"deprecated,omitempty"`
+}
+
+// ReadOnly reports whether p cannot be configured.
+func (p *Parameter) ReadOnly() bool {
+	return strings.TrimSpace(p.Editable) == "false"
+}
+
+// Dependency is a dependency of a plugin.
+type Dependency struct {
+	GroupId    string `xml:"groupId"`
+	ArtifactId string `xml:"artifactId"`
+	Type       string `xml:"type,omitempty"`
+	Version    string `xml:"version"`
+}
+
+// Mojo returns the goal named goal of d, or nil.
+func (d *Descriptor) Mojo(goal string) *Mojo {
+	for i := range d.Mojos {
+		if d.Mojos[i].Goal == goal {
+			return &d.Mojos[i]
+		}
+	}
+	return nil
+}
+
+// Parameter returns the parameter of m named or aliased name, or nil.
+func (m *Mojo) Parameter(name string) *Parameter {
+	for i := range m.Parameters {
+		if p := &m.Parameters[i]; p.Name == name || p.Alias != "" && p.Alias == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// Expression returns the expression of the parameter named name of m, like
+// ${maven.compiler.release}, and its default value, like
+// ${project.build.outputDirectory}. Both are empty when m gives none.
+func (m *Mojo) Expression(name string) (expression, defaultValue string) {
+	if m.Configuration == nil {
+		return "", ""
+	}
+	for _, c := range m.Configuration.Children {
+		if c.XMLName.Local != name {
+			continue
+		}
+		for _, attr := range c.Attrs {
+			if attr.Name.Local == "default-value" {
+				defaultValue = attr.Value
+			}
+		}
+		return c.Value, defaultValue
+	}
+	return "", ""
+}
+
+// Read decodes the descriptor read from r.
+func Read(r io.Reader) (*Descriptor, error) {
+	var d Descriptor
+	if err := xml.NewDecoder(r).Decode(&d); err != nil {
+		return nil, fmt.Errorf("descriptor: %w", err)
+	}
+	return &d, nil
+}
+
+// ReadFile decodes the descriptor of the plugin jar at path, or the
+// plugin.xml file at path.
+func ReadFile(path string) (*Descriptor, error) {
+	if strings.HasSuffix(path, ".xml") {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return Read(f)
+	}
+
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("descriptor: %s: %w", path, err)
+	}
+	defer r.Close()
+
+	f, err := r.Open(Path)
+	if err != nil {
+		return nil, fmt.Errorf("descriptor: %s: %w", path, err)
+	}
+	defer f.Close()
+
+	d, err := Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return d, nil
+}
@@ -0,0 +1,112 @@
+package descriptor_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+	"github.com/obscurelyme/encoding/pom/descriptor"
+)
+
+var repository = pom.LocalRepository{Dir: filepath.Join("testdata", "repository")}
+
+func readProject(t *testing.T) *pom.Model {
+	t.Helper()
+
+	m, err := pom.ReadFile(filepath.Join("testdata", "pom.xml"))
+	if err != nil {
+		t.Fatalf("Expected no errors reading pom, but found: %s", err.Error())
+	}
+	return m
+}
+
+func TestLookup(t *testing.T) {
+	m := readProject(t)
+	compiler := &m.Build.Plugins.Plugin[0]
+
+	t.Run("Should read the descriptor of a plugin from its jar", func(t *testing.T) {
+		d, err := descriptor.Lookup(compiler, repository)
+		if err != nil {
+			t.Fatalf("Expected no errors, but found: %s", err.Error())
+		}
+		if d.GoalPrefix != "compiler" || len(d.Mojos) != 2 || len(d.Dependencies) != 1 {
+			t.Errorf("Expected the compiler descriptor, but found: %v", d)
+		}
+		mojo := d.Mojo("compile")
+		if mojo == nil || mojo.Phase != "compile" || mojo.RequiresDependencyResolution != "compile" || !mojo.ThreadSafe {
+			t.Fatalf("Expected the compile goal, but found: %v", mojo)
+		}
+		if p := mojo.Parameter("outputDirectory"); p == nil || !p.Required || !p.ReadOnly() {
+			t.Errorf("Expected a required read-only outputDirectory, but found: %v", p)
+		}
+		if p := d.Mojo("testCompile").Parameter("maven.test.skip"); p == nil || p.Name != "skip" {
+			t.Errorf("Expected the skip parameter by its alias, but found: %v", p)
+		}
+		expression, defaultValue := mojo.Expression("showWarnings")
+		if expression != "${maven.compiler.showWarnings}" || defaultValue != "true" {
+			t.Errorf("Expected the expression and default value of showWarnings, but found: %s %s", expression, defaultValue)
+		}
+	})
+
+	t.Run("Should reject plugins without a version", func(t *testing.T) {
+		if _, err := descriptor.Lookup(&pom.Plugin{ArtifactId: "maven-compiler-plugin"}, repository); err == nil {
+			t.Errorf("Expected an error")
+		}
+	})
+
+	t.Run("Should give the default phases of goals", func(t *testing.T) {
+		r := descriptor.NewResolver(repository)
+		phase, err := r.DefaultPhase(&m.Build.Plugins.Plugin[1], "generate")
+		if err != nil || phase != "generate-sources" {
+			t.Errorf("Expected generate-sources, but found: %s %v", phase, err)
+		}
+		if _, err := r.DefaultPhase(compiler, "missing"); err == nil {
+			t.Errorf("Expected an error for an unknown goal")
+		}
+
+		plan, err := m.Plan("compile", &pom.PlanOptions{DefaultPhases: r})
+		if err != nil {
+			t.Fatalf("Expected no errors, but found: %s", err.Error())
+		}
+		var found []string
+		for _, x := range plan {
+			found = append(found, x.Goal+":"+x.Phase)
+		}
+		expected := "generate:generate-sources resources:process-resources compile:compile compile:compile"
+		if strings.Join(found, " ") != expected {
+			t.Errorf("Expected %s, but found: %v", expected, found)
+		}
+	})
+}
+
+func TestValidate(t *testing.T) {
+	m := readProject(t)
+	r := descriptor.NewResolver(repository)
+
+	var found []string
+	for i := range m.Build.Plugins.Plugin {
+		p := &m.Build.Plugins.Plugin[i]
+		d, err := r.Lookup(p)
+		if err != nil {
+			t.Fatalf("Expected no errors, but found: %s", err.Error())
+		}
+		for _, problem := range d.Validate(p) {
+			found = append(found, problem.String())
+		}
+	}
+
+	t.Run("Should report parameters that do not match the descriptor", func(t *testing.T) {
+		expected := []string{
+			"showWarnings of compile: expected boolean, but found yes",
+			"fork: unknown parameter",
+			"outputDirectory of compile (java-21): read-only parameter",
+			"maven.test.skip (java-21): unknown parameter",
+			"count of generate (default): expected int, but found many",
+			"template of generate (default): missing required parameter",
+		}
+		if strings.Join(found, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("Expected:\n%s\nbut found:\n%s", strings.Join(expected, "\n"), strings.Join(found, "\n"))
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package descriptor
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+// Lookup reads the descriptor of plugin p, a plugin of a POM, from its jar
+// in repository. The plugin must have a version: effective models only give
+// versions to the plugins managed by their parents or the Super POM, the
+// plugins of the lifecycles declared without one get theirs from
+// pom.LifecycleBindings, like pom.Model.Plan gives them.
+func Lookup(p *pom.Plugin, repository pom.LocalRepository) (*Descriptor, error) {
+	if p.Version == "" {
+		return nil, fmt.Errorf("descriptor: %s has no version", p.Key())
+	}
+	d := &pom.Dependency{GroupId: p.GroupId, ArtifactId: p.ArtifactId, Version: p.Version, Type: "maven-plugin"}
+	if d.GroupId == "" {
+		d.GroupId = pom.DefaultPluginGroupId
+	}
+	return ReadFile(repository.ArtifactPath(d))
+}
+
+// Resolver looks up the descriptors of plugins in a local repository, reading
+// each one once. It is a pom.DefaultPhaseResolver.
+type Resolver struct {
+	Repository  pom.LocalRepository
+	descriptors map[string]*Descriptor
+}
+
+// NewResolver returns a Resolver of the descriptors of repository.
+func NewResolver(repository pom.LocalRepository) *Resolver {
+	return &Resolver{Repository: repository, descriptors: make(map[string]*Descriptor)}
+}
+
+// Lookup returns the descriptor of p, like the Lookup function.
+func (r *Resolver) Lookup(p *pom.Plugin) (*Descriptor, error) {
+	key := p.Key() + ":" + p.Version
+	if d, ok := r.descriptors[key]; ok {
+		return d, nil
+	}
+	d, err := Lookup(p, r.Repository)
+	if err != nil {
+		return nil, err
+	}
+	r.descriptors[key] = d
+	return d, nil
+}
+
+// DefaultPhase returns the phase of the goal of p given by its descriptor.
+// A goal the descriptor does not have is an error.
+func (r *Resolver) DefaultPhase(p *pom.Plugin, goal string) (string, error) {
+	d, err := r.Lookup(p)
+	if err != nil {
+		return "", err
+	}
+	m := d.Mojo(goal)
+	if m == nil {
+		return "", errors.New("no such goal in " + p.Key() + ":" + p.Version)
+	}
+	return m.Phase, nil
+}
@@ -0,0 +1,166 @@
+package descriptor
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+// Problem is a parameter in the configuration of a plugin that does not
+// match its descriptor.
+type Problem struct {
+	// ExecutionId is the id of the execution configuring the parameter, or
+	// empty for the configuration of the plugin.
+	ExecutionId string
+	// Goal is the goal of the parameter, or empty for an unknown parameter
+	// in the configuration of the plugin, which configures all its goals.
+	Goal      string
+	Parameter string
+	Message   string
+}
+
+// String returns the parameter, goal and execution of p, and its message.
+func (p Problem) String() string {
+	s := p.Parameter
+	if p.Goal != "" {
+		s += " of " + p.Goal
+	}
+	if p.ExecutionId != "" {
+		s += " (" + p.ExecutionId + ")"
+	}
+	return s + ": " + p.Message
+}
+
+// Validate checks the configuration of p, a plugin described by d, and
+// returns its problems:
+//
+//   - parameters that no goal of the plugin has, or no goal of the execution
+//     configuring them;
+//   - read-only parameters that are configured;
+//   - required parameters of the goals of the executions of p that are
+//     neither configured nor given a value by an expression or a default
+//     value;
+//   - boolean and number parameters with values that are not.
+//
+// Values with expressions like ${release} are not checked, they are only
+// known when Maven runs. p should be a plugin of an effective model, with
+// the configurations of its plugin management.
+func (d *Descriptor) Validate(p *pom.Plugin) []Problem {
+	var problems []Problem
+	if p.Configuration != nil {
+		for _, c := range p.Configuration.Children {
+			name := c.XMLName.Local
+			found := false
+			for i := range d.Mojos {
+				m := &d.Mojos[i]
+				if param := m.Parameter(name); param != nil {
+					found = true
+					problems = append(problems, checkParameter(param, &c, "", m.Goal)...)
+				}
+			}
+			if !found {
+				problems = append(problems, Problem{Parameter: name, Message: "unknown parameter"})
+			}
+		}
+	}
+
+	if p.Executions == nil {
+		return problems
+	}
+	for _, x := range p.Executions.Execution {
+		id := x.Id
+		if id == "" {
+			id = pom.DefaultExecutionId
+		}
+		var goals []*Mojo
+		if x.Goals != nil {
+			for _, g := range x.Goals.Goal {
+				m := d.Mojo(g)
+				if m == nil {
+					problems = append(problems, Problem{ExecutionId: id, Goal: g, Message: "unknown goal"})
+					continue
+				}
+				goals = append(goals, m)
+			}
+		}
+
+		if x.Configuration != nil {
+			for _, c := range x.Configuration.Children {
+				name := c.XMLName.Local
+				found := false
+				for _, m := range goals {
+					if param := m.Parameter(name); param != nil {
+						found = true
+						problems = append(problems, checkParameter(param, &c, id, m.Goal)...)
+					}
+				}
+				if !found && len(goals) > 0 {
+					problems = append(problems, Problem{ExecutionId: id, Parameter: name, Message: "unknown parameter"})
+				}
+			}
+		}
+
+		for _, m := range goals {
+			for i := range m.Parameters {
+				param := &m.Parameters[i]
+				if !param.Required || configured(param, x.Configuration) || configured(param, p.Configuration) {
+					continue
+				}
+				if expression, defaultValue := m.Expression(param.Name); expression != "" || defaultValue != "" {
+					continue
+				}
+				problems = append(problems, Problem{ExecutionId: id, Goal: m.Goal, Parameter: param.Name, Message: "missing required parameter"})
+			}
+		}
+	}
+	return problems
+}
+
+// configured reports whether configuration sets param, by its name or alias.
+func configured(param *Parameter, configuration *pom.DOM) bool {
+	if configuration == nil {
+		return false
+	}
+	for _, c := range configuration.Children {
+		if name := c.XMLName.Local; name == param.Name || param.Alias != "" && name == param.Alias {
+			return true
+		}
+	}
+	return false
+}
+
+// checkParameter checks c, the configuration of param of goal.
+func checkParameter(param *Parameter, c *pom.DOM, executionId, goal string) []Problem {
+	problem := func(message string) []Problem {
+		return []Problem{{ExecutionId: executionId, Goal: goal, Parameter: param.Name, Message: message}}
+	}
+	if param.ReadOnly() {
+		return problem("read-only parameter")
+	}
+	if len(c.Children) > 0 || strings.Contains(c.Value, "${") {
+		return nil
+	}
+
+	var err error
+	switch param.Type {
+	case "boolean", "java.lang.Boolean":
+		if v := strings.ToLower(c.Value); v != "true" && v != "false" {
+			return problem("expected boolean, but found " + c.Value)
+		}
+	case "byte", "java.lang.Byte":
+		_, err = strconv.ParseInt(c.Value, 10, 8)
+	case "short", "java.lang.Short":
+		_, err = strconv.ParseInt(c.Value, 10, 16)
+	case "int", "java.lang.Integer":
+		_, err = strconv.ParseInt(c.Value, 10, 32)
+	case "long", "java.lang.Long":
+		_, err = strconv.ParseInt(c.Value, 10, 64)
+	case "float", "java.lang.Float", "double", "java.lang.Double":
+		_, err = strconv.ParseFloat(c.Value, 64)
+	}
+	if err != nil {
+		return problem("expected " + strings.TrimPrefix(param.Type, "java.lang.") + ", but found " + c.Value)
+	}
+	return nil
+}
@@ -0,0 +1,556 @@
+package pom
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// ChangeKind tells whether an element or value was added, removed or changed.
+type ChangeKind string
+
+const (
+	Added   ChangeKind = "added"
+	Removed ChangeKind = "removed"
+	Changed ChangeKind = "changed"
+)
+
+// Change is a single difference between two models.
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+	// Path locates the element or value, with the elements of lists given by
+	// their key in brackets, such as
+	// /project/dependencies/dependency[org.slf4j:slf4j-api:jar:]/version.
+	Path string `json:"path"`
+	// Profile is the id of the profile the change is in, if any.
+	Profile string `json:"profile,omitempty"`
+	// Element is the kind of element that changed, such as dependency,
+	// managed dependency, plugin, execution, property, parent or profile, or
+	// project for the values of the project itself.
+	Element string `json:"element"`
+	// Key identifies the element among its siblings: groupId:artifactId:
+	// type:classifier for dependencies, groupId:artifactId for plugins,
+	// groupId:artifactId/id for executions and the name for properties.
+	Key string `json:"key,omitempty"`
+	// Field is the path of the changed value within the element, empty when
+	// the element as a whole was added or removed.
+	Field string `json:"field,omitempty"`
+	// Old and New are the values before and after. For elements added or
+	// removed as a whole they summarize the element, such as the version of
+	// a dependency.
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+}
+
+func (c Change) String() string {
+	var b strings.Builder
+
+	switch c.Kind {
+	case Added:
+		b.WriteString("+ ")
+	case Removed:
+		b.WriteString("- ")
+	default:
+		b.WriteString("~ ")
+	}
+	if c.Profile != "" && c.Element != "profile" {
+		fmt.Fprintf(&b, "profile %s: ", c.Profile)
+	}
+	b.WriteString(c.Element)
+	if c.Key != "" {
+		b.WriteString(" " + displayKey(c.Key))
+	}
+	if c.Field != "" {
+		b.WriteString(" " + c.Field + ":")
+	}
+
+	switch c.Kind {
+	case Added:
+		if c.New != "" {
+			b.WriteString(" " + c.New)
+		}
+	case Removed:
+		if c.Old != "" {
+			b.WriteString(" " + c.Old)
+		}
+	default:
+		fmt.Fprintf(&b, " %s -> %s", orDefault(c.Old, `""`), orDefault(c.New, `""`))
+	}
+
+	return b.String()
+}
+
+// displayKey shortens dependency keys by leaving out the default type and
+// an empty classifier.
+func displayKey(key string) string {
+	if short, ok := strings.CutSuffix(key, ":jar:"); ok {
+		return short
+	}
+	return key
+}
+
+// Changes are the differences between two models, in document order.
+type Changes []Change
+
+func (c Changes) String() string {
+	var b strings.Builder
+	c.WriteText(&b)
+	return b.String()
+}
+
+// WriteText writes the changes one per line, marked with + when added, -
+// when removed and ~ when changed.
+func (c Changes) WriteText(w io.Writer) error {
+	for _, change := range c {
+		if _, err := fmt.Fprintln(w, change.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes the changes as an indented JSON array.
+func (c Changes) WriteJSON(w io.Writer) error {
+	if c == nil {
+		c = Changes{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c)
+}
+
+// Diff returns the semantic differences between a and b. Elements of lists
+// with an identity, like dependencies, plugins, executions, repositories and
+// profiles, are matched by their key rather than their position, so
+// reordering them is not a change; neither are comments or the order of
+// properties. A nil model is the same as an empty one.
+func Diff(a, b *Model) Changes {
+	if a == nil {
+		a = New()
+	}
+	if b == nil {
+		b = New()
+	}
+
+	d := &differ{}
+	d.value(diffContext{path: "/project", element: "project"}, reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem())
+	return d.changes
+}
+
+type diffContext struct {
+	path    string
+	profile string
+	element string
+	key     string
+	field   string
+	// managed is set within dependency and plugin management.
+	managed bool
+}
+
+// child returns the context of the child element or attribute name. The
+// elements of the project itself each start a new element context, so that
+// a changed <scm> url is reported as the url of scm.
+func (ctx diffContext) child(name string, element bool) diffContext {
+	c := ctx
+	c.path += "/" + name
+
+	switch {
+	case name == "dependencyManagement" || name == "pluginManagement":
+		c.managed = true
+		c.field = ""
+	case element && ctx.element == "project" && ctx.key == "" && ctx.field == "":
+		c.element = name
+		c.field = ""
+	case ctx.field == "":
+		c.field = name
+	default:
+		c.field = ctx.field + "/" + name
+	}
+	return c
+}
+
+// item returns the context of the element of a list with the given key.
+// Elements within another identified element, like the executions of a
+// plugin, have the key of that element as a prefix.
+func (ctx diffContext) item(name, key string, v reflect.Value) diffContext {
+	c := ctx
+	if prefix, ok := strings.CutSuffix(c.path, "/*"); ok {
+		c.path = prefix + "/" + name
+	}
+	c.path += "[" + key + "]"
+	c.field = ""
+	c.element = name
+
+	owned := ctx.key != "" && ctx.element != "profile"
+	if ctx.managed && !owned && (name == "dependency" || name == "plugin") {
+		c.element = "managed " + name
+	}
+	if _, ok := v.Addr().Interface().(*Profile); ok {
+		c.profile = key
+		c.managed = false
+	}
+	if owned {
+		key = ctx.key + "/" + key
+	}
+	c.key = key
+	return c
+}
+
+type differ struct {
+	changes Changes
+}
+
+func (d *differ) add(ctx diffContext, kind ChangeKind, old, new string) {
+	d.changes = append(d.changes, Change{
+		Kind:    kind,
+		Path:    ctx.path,
+		Profile: ctx.profile,
+		Element: ctx.element,
+		Key:     ctx.key,
+		Field:   ctx.field,
+		Old:     old,
+		New:     new,
+	})
+}
+
+func (d *differ) scalar(ctx diffContext, a, b string) {
+	switch {
+	case a == b:
+	case a == "":
+		d.add(ctx, Added, "", b)
+	case b == "":
+		d.add(ctx, Removed, a, "")
+	default:
+		d.add(ctx, Changed, a, b)
+	}
+}
+
+func (d *differ) value(ctx diffContext, a, b reflect.Value) {
+	switch a.Kind() {
+	case reflect.String:
+		d.scalar(ctx, a.String(), b.String())
+	case reflect.Bool:
+		d.scalar(ctx, fmt.Sprint(a.Bool()), fmt.Sprint(b.Bool()))
+	case reflect.Pointer:
+		d.pointer(ctx, a, b)
+	case reflect.Struct:
+		d.fields(ctx, a, b)
+	case reflect.Slice:
+		d.slice(ctx, a, b)
+	}
+}
+
+func (d *differ) pointer(ctx diffContext, a, b reflect.Value) {
+	if a.IsNil() && b.IsNil() {
+		return
+	}
+
+	switch a.Interface().(type) {
+	case *Properties:
+		d.properties(ctx, a.Interface().(*Properties), b.Interface().(*Properties))
+		return
+	case *DOM:
+		d.scalar(ctx, domString(a.Interface().(*DOM)), domString(b.Interface().(*DOM)))
+		return
+	}
+
+	// list wrappers like <dependencies> compare their elements, other
+	// elements are added or removed as a whole
+	if a.IsNil() || b.IsNil() {
+		if !isListWrapper(a.Type().Elem()) {
+			if a.IsNil() {
+				d.add(ctx, Added, "", summary(b.Elem()))
+			} else {
+				d.add(ctx, Removed, summary(a.Elem()), "")
+			}
+			return
+		}
+		if a.IsNil() {
+			a = reflect.New(a.Type().Elem())
+		} else {
+			b = reflect.New(b.Type().Elem())
+		}
+	}
+	d.value(ctx, a.Elem(), b.Elem())
+}
+
+// isListWrapper reports whether t only holds a list, like Dependencies.
+func isListWrapper(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	lists := 0
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Name == "Comment" || f.Name == "Any" || f.Name == "AnyAttrs" {
+			continue
+		}
+		if f.Type.Kind() != reflect.Slice {
+			return false
+		}
+		lists++
+	}
+	return lists == 1
+}
+
+func (d *differ) fields(ctx diffContext, a, b reflect.Value) {
+	t := a.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if isEmbedded(f) {
+			d.fields(ctx, a.Field(i), b.Field(i))
+			continue
+		}
+		name, ok := fieldName(f)
+		if !ok {
+			continue
+		}
+		element := f.Type.Kind() != reflect.String
+		d.value(ctx.child(name, element), a.Field(i), b.Field(i))
+	}
+}
+
+// isEmbedded reports whether f is an embedded struct whose fields are those
+// of the element, like the BuildBase of Build.
+func isEmbedded(f reflect.StructField) bool {
+	_, hasTag := f.Tag.Lookup("xml")
+	return f.Anonymous && !hasTag
+}
+
+// fieldName returns the name of f in paths: the element name, @name for
+// attributes, * for unknown elements and @* for unknown attributes. It
+// returns false for fields that are not part of the document content.
+func fieldName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("xml")
+	if !f.IsExported() || f.Name == "XMLName" || tag == "-" || tag == ",comment" {
+		return "", false
+	}
+
+	name, opts, _ := strings.Cut(tag, ",")
+	switch {
+	case hasOption(opts, "any") && hasOption(opts, "attr"):
+		name = "@*"
+	case hasOption(opts, "any"):
+		name = "*"
+	case hasOption(opts, "attr"):
+		name = "@" + name
+	}
+	return name, true
+}
+
+func (d *differ) slice(ctx diffContext, a, b reflect.Value) {
+	switch a.Type().Elem() {
+	case reflect.TypeOf(""):
+		d.stringSet(ctx, a.Interface().([]string), b.Interface().([]string))
+		return
+	case reflect.TypeOf(xml.Attr{}):
+		d.attrs(ctx, a.Interface().([]xml.Attr), b.Interface().([]xml.Attr))
+		return
+	}
+
+	name := ctx.path[strings.LastIndexByte(ctx.path, '/')+1:]
+	parent := ctx
+
+	aKeys, aItems := diffItems(a)
+	bKeys, bItems := diffItems(b)
+
+	for _, key := range aKeys {
+		if _, ok := bItems[key]; !ok {
+			v := aItems[key]
+			d.add(parent.item(itemName(name, v), key, v), Removed, summary(v), "")
+		}
+	}
+	for _, key := range bKeys {
+		v := bItems[key]
+		ctx := parent.item(itemName(name, v), key, v)
+		if old, ok := aItems[key]; ok {
+			d.value(ctx, old, v)
+		} else {
+			d.add(ctx, Added, "", summary(v))
+		}
+	}
+}
+
+// itemName returns the element name of a list element, which for unknown
+// elements is their own name.
+func itemName(name string, v reflect.Value) string {
+	if dom, ok := v.Addr().Interface().(*DOM); ok {
+		return dom.XMLName.Local
+	}
+	return name
+}
+
+// diffItems returns the keys of the elements of list in order, and the
+// elements by key. Elements without an identity are keyed by their position.
+func diffItems(list reflect.Value) ([]string, map[string]reflect.Value) {
+	keys := make([]string, 0, list.Len())
+	items := make(map[string]reflect.Value, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		key := diffKey(list.Index(i))
+		if key == "" {
+			key = fmt.Sprint(i)
+		}
+		for n := 2; ; n++ {
+			if _, dup := items[key]; !dup {
+				break
+			}
+			key = fmt.Sprintf("%s#%d", diffKey(list.Index(i)), n)
+		}
+		keys = append(keys, key)
+		items[key] = list.Index(i)
+	}
+	return keys, items
+}
+
+func diffKey(v reflect.Value) string {
+	if key, _, ok := mergeKey(v); ok {
+		return key
+	}
+
+	switch e := v.Addr().Interface().(type) {
+	case *Profile:
+		return orDefault(e.Id, DefaultExecutionId)
+	case *License:
+		return e.Name
+	case *Developer:
+		return orDefault(e.Id, orDefault(e.Email, e.Name))
+	case *Contributor:
+		return orDefault(e.Email, e.Name)
+	case *MailingList:
+		return e.Name
+	case *Notifier:
+		return e.Type + ":" + e.Address
+	case *Resource:
+		return e.Directory
+	case *Source:
+		return orDefault(e.Scope, "main") + ":" + orDefault(e.Lang, "java") + ":" + e.Directory
+	case *DOM:
+		return e.XMLName.Local
+	}
+	return ""
+}
+
+// summary describes an element added or removed as a whole.
+func summary(v reflect.Value) string {
+	switch e := v.Addr().Interface().(type) {
+	case *Dependency:
+		return strings.TrimSpace(e.Version + " " + e.Scope)
+	case *Plugin:
+		return e.Version
+	case *ReportPlugin:
+		return e.Version
+	case *Extension:
+		return e.Version
+	case *Execution:
+		s := e.Phase
+		if e.Goals != nil {
+			s = strings.TrimSpace(s + " " + strings.Join(e.Goals.Goal, ","))
+		}
+		return s
+	case *Parent:
+		return e.GroupId + ":" + e.ArtifactId + ":" + e.Version
+	case *Repository:
+		return e.Url
+	case *DOM:
+		return domString(e)
+	}
+	return ""
+}
+
+func (d *differ) stringSet(ctx diffContext, a, b []string) {
+	in := func(list []string, s string) bool {
+		for _, v := range list {
+			if v == s {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, s := range a {
+		if !in(b, s) {
+			d.add(ctx, Removed, s, "")
+		}
+	}
+	for _, s := range b {
+		if !in(a, s) {
+			d.add(ctx, Added, "", s)
+		}
+	}
+}
+
+func (d *differ) attrs(ctx diffContext, a, b []xml.Attr) {
+	values := func(attrs []xml.Attr) map[string]string {
+		m := make(map[string]string, len(attrs))
+		for _, attr := range attrs {
+			if !isNamespaceAttr(attr) {
+				m[attr.Name.Local] = attr.Value
+			}
+		}
+		return m
+	}
+
+	av, bv := values(a), values(b)
+	for _, attr := range a {
+		if _, ok := bv[attr.Name.Local]; !ok && !isNamespaceAttr(attr) {
+			d.add(ctx.child("@"+attr.Name.Local, false), Removed, attr.Value, "")
+		}
+	}
+	for _, attr := range b {
+		if !isNamespaceAttr(attr) {
+			d.scalar(ctx.child("@"+attr.Name.Local, false), av[attr.Name.Local], attr.Value)
+		}
+	}
+}
+
+func (d *differ) properties(ctx diffContext, a, b *Properties) {
+	if a == nil {
+		a = &Properties{}
+	}
+	if b == nil {
+		b = &Properties{}
+	}
+
+	for _, key := range a.Keys() {
+		if _, ok := b.Fields[key]; !ok {
+			d.add(propertyContext(ctx, key), Removed, a.Fields[key], "")
+		}
+	}
+	for _, key := range b.Keys() {
+		old, ok := a.Fields[key]
+		switch {
+		case !ok:
+			d.add(propertyContext(ctx, key), Added, "", b.Fields[key])
+		case old != b.Fields[key]:
+			d.add(propertyContext(ctx, key), Changed, old, b.Fields[key])
+		}
+	}
+}
+
+func propertyContext(ctx diffContext, name string) diffContext {
+	c := ctx
+	c.path += "/" + name
+	c.element = "property"
+	c.key = name
+	c.field = ""
+	return c
+}
+
+// domString renders d as XML for comparison.
+func domString(d *DOM) string {
+	if d == nil {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	enc := xml.NewEncoder(&buf)
+	if err := enc.Encode(d); err != nil {
+		return ""
+	}
+	return buf.String()
+}
@@ -0,0 +1,93 @@
+package pom_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+func readDiffPair(t *testing.T) (*pom.Model, *pom.Model) {
+	t.Helper()
+
+	var models []*pom.Model
+	for _, name := range []string{"before.xml", "after.xml"} {
+		m, err := pom.ReadFile(filepath.Join("testdata", "diff", name))
+		if err != nil {
+			t.Fatalf("Expected no errors reading %s, but found: %s", name, err.Error())
+		}
+		models = append(models, m)
+	}
+	return models[0], models[1]
+}
+
+func TestDiff(t *testing.T) {
+	t.Run("Should report semantic changes", func(t *testing.T) {
+		before, after := readDiffPair(t)
+
+		expected := `~ parent version: 7 -> 8
+- property legacy true
+~ property slf4j.version 2.0.7 -> 2.0.9
+- dependency commons-io:commons-io 2.15.1
+~ dependency org.junit.jupiter:junit-jupiter version: 5.10.1 -> 5.10.2
++ dependency com.google.guava:guava 33.0.0-jre
+~ execution org.apache.maven.plugins:maven-surefire-plugin/integration phase: integration-test -> verify
+~ profile release: dependency org.example:signing version: 1.0 -> 1.1
++ profile coverage
+`
+		if diff := pom.Diff(before, after).String(); diff != expected {
+			t.Errorf("Expected:\n%s\nbut found:\n%s", expected, diff)
+		}
+	})
+
+	t.Run("Should identify changes structurally", func(t *testing.T) {
+		before, after := readDiffPair(t)
+
+		var found *pom.Change
+		for _, c := range pom.Diff(before, after) {
+			if c.Element == "dependency" && c.Kind == pom.Changed && c.Profile == "" {
+				found = &c
+			}
+		}
+		if found == nil {
+			t.Fatalf("Expected a changed dependency")
+		}
+		if found.Key != "org.junit.jupiter:junit-jupiter:jar:" || found.Field != "version" ||
+			found.Path != "/project/dependencies/dependency[org.junit.jupiter:junit-jupiter:jar:]/version" {
+			t.Errorf("Expected the junit version change, but found: %+v", found)
+		}
+	})
+
+	t.Run("Should find no changes between equal poms", func(t *testing.T) {
+		before, _ := readDiffPair(t)
+
+		if changes := pom.Diff(before, before.Clone()); len(changes) != 0 {
+			t.Errorf("Expected no changes, but found:\n%s", changes)
+		}
+	})
+
+	t.Run("Should render JSON", func(t *testing.T) {
+		before, after := readDiffPair(t)
+
+		var buf bytes.Buffer
+		if err := pom.Diff(before, after).WriteJSON(&buf); err != nil {
+			t.Fatalf("Expected no errors writing JSON, but found: %s", err.Error())
+		}
+
+		var changes []map[string]string
+		if err := json.Unmarshal(buf.Bytes(), &changes); err != nil {
+			t.Fatalf("Expected valid JSON, but found: %s", err.Error())
+		}
+		if len(changes) != 9 || changes[0]["kind"] != "changed" || changes[0]["element"] != "parent" || changes[0]["new"] != "8" {
+			t.Errorf("Expected 9 changes starting with the parent, but found: %s", buf.String())
+		}
+
+		buf.Reset()
+		pom.Diff(before, before).WriteJSON(&buf)
+		if buf.String() != "[]\n" {
+			t.Errorf("Expected an empty array, but found: %s", buf.String())
+		}
+	})
+}
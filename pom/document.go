@@ -0,0 +1,610 @@
+package pom
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Document is a POM document edited in place. Edits only change the text of
+// the elements they concern, so the formatting, comments and element order of
+// the rest of the document are kept, unlike when a Model is written back.
+//
+// Elements are located by paths in the form of the Path of a Change, like
+// /project/dependencies/dependency[org.slf4j:slf4j-api:jar:]/version. Paths
+// not starting with a slash are relative to /project. Only documents in UTF-8
+// can be edited.
+type Document struct {
+	data []byte
+	root *node
+	// newline and indent are the line ending and the indentation of one
+	// level used by the document, for inserted elements.
+	newline string
+	indent  string
+}
+
+// node is an element of a Document, located by byte offsets.
+type node struct {
+	name     string
+	rawName  string
+	start    int
+	end      int
+	inner    int // start of the content
+	innerEnd int // end of the content
+	empty    bool
+	parent   *node
+	children []*node
+}
+
+// NotFoundError reports that a Document has no element at a path.
+type NotFoundError struct {
+	Path string
+}
+
+func (e *NotFoundError) Error() string {
+	return "pom: no element at " + e.Path
+}
+
+// ParseDocument parses data, a POM document in UTF-8, for editing.
+func ParseDocument(data []byte) (*Document, error) {
+	d := &Document{data: data}
+	if err := d.parse(); err != nil {
+		return nil, err
+	}
+
+	d.newline = "\n"
+	if bytes.Contains(data, []byte("\r\n")) {
+		d.newline = "\r\n"
+	}
+	d.indent = d.detectIndent(d.root)
+	if d.indent == "" {
+		d.indent = DefaultWriteOptions.Indent
+	}
+	return d, nil
+}
+
+// Bytes returns the document with the edits made so far.
+func (d *Document) Bytes() []byte {
+	return d.data
+}
+
+// Model decodes the document with the edits made so far.
+func (d *Document) Model() (*Model, error) {
+	return Read(bytes.NewReader(d.data))
+}
+
+func (d *Document) parse() error {
+	offset := 0
+	if bytes.HasPrefix(d.data, bomUTF8) {
+		offset = len(bomUTF8)
+	}
+
+	dec := xml.NewDecoder(bytes.NewReader(d.data[offset:]))
+	var root *node
+	var stack []*node
+	for {
+		before := int(dec.InputOffset()) + offset
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return &SyntaxError{Err: err}
+		}
+		after := int(dec.InputOffset()) + offset
+
+		switch t := tok.(type) {
+		case xml.ProcInst:
+			if t.Target == "xml" {
+				if encoding := procInstEncoding(t.Inst); encoding != "" && !isUTF8(encoding) {
+					return &EncodingError{Encoding: encoding}
+				}
+			}
+		case xml.StartElement:
+			n := &node{name: t.Name.Local, rawName: t.Name.Local, start: before, inner: after}
+			if t.Name.Space != "" {
+				n.rawName = t.Name.Space + ":" + t.Name.Local
+			}
+			if len(stack) > 0 {
+				n.parent = stack[len(stack)-1]
+				n.parent.children = append(n.parent.children, n)
+			} else if root == nil {
+				root = n
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			if len(stack) == 0 {
+				return &SyntaxError{Err: fmt.Errorf("unexpected end element </%s>", t.Name.Local)}
+			}
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if before == after {
+				// <name/>, the end element is implied
+				n.empty = true
+				n.inner, n.innerEnd, n.end = after, after, after
+			} else {
+				n.innerEnd, n.end = before, after
+			}
+		}
+	}
+
+	if root == nil || len(stack) > 0 {
+		return &SyntaxError{Err: io.ErrUnexpectedEOF}
+	}
+	if root.name != "project" {
+		return &RootElementError{Name: xml.Name{Local: root.name}}
+	}
+	d.root = root
+	return nil
+}
+
+// procInstEncoding returns the encoding named by the XML declaration inst.
+func procInstEncoding(inst []byte) string {
+	_, rest, ok := strings.Cut(string(inst), "encoding=")
+	if !ok || len(rest) < 2 {
+		return ""
+	}
+	quote := rest[0]
+	value, _, _ := strings.Cut(rest[1:], string(quote))
+	return value
+}
+
+// lineIndent returns the whitespace before offset on its line, and whether
+// there is nothing else before it.
+func (d *Document) lineIndent(offset int) (string, bool) {
+	i := offset
+	for i > 0 && (d.data[i-1] == ' ' || d.data[i-1] == '\t') {
+		i--
+	}
+	if i > 0 && d.data[i-1] != '\n' {
+		return "", false
+	}
+	return string(d.data[i:offset]), true
+}
+
+func (d *Document) detectIndent(n *node) string {
+	parent, ok := d.lineIndent(n.start)
+	for _, c := range n.children {
+		if child, childOk := d.lineIndent(c.start); ok && childOk && len(child) > len(parent) && strings.HasPrefix(child, parent) {
+			return child[len(parent):]
+		}
+		if indent := d.detectIndent(c); indent != "" {
+			return indent
+		}
+	}
+	return ""
+}
+
+// indentOf returns the indentation of the line of n.
+func (d *Document) indentOf(n *node) string {
+	if indent, ok := d.lineIndent(n.start); ok {
+		return indent
+	}
+	if n.parent != nil {
+		return d.indentOf(n.parent) + d.indent
+	}
+	return ""
+}
+
+// segment is an element of a path: a name and an optional key.
+type segment struct {
+	name   string
+	key    string
+	hasKey bool
+}
+
+func parsePath(path string) ([]segment, error) {
+	if !strings.HasPrefix(path, "/") {
+		path = "/project/" + path
+	}
+
+	var segments []segment
+	depth := 0
+	start := 1
+	for i := 1; i <= len(path); i++ {
+		if i < len(path) {
+			switch path[i] {
+			case '[':
+				depth++
+				continue
+			case ']':
+				depth--
+				continue
+			case '/':
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+
+		s := segment{name: path[start:i]}
+		if open := strings.IndexByte(s.name, '['); open >= 0 && strings.HasSuffix(s.name, "]") {
+			s.name, s.key, s.hasKey = s.name[:open], s.name[open+1:len(s.name)-1], true
+		}
+		if s.name == "" {
+			return nil, fmt.Errorf("pom: invalid path %s", path)
+		}
+		segments = append(segments, s)
+		start = i + 1
+	}
+
+	if len(segments) == 0 || segments[0].name != "project" || segments[0].hasKey {
+		return nil, fmt.Errorf("pom: invalid path %s", path)
+	}
+	return segments, nil
+}
+
+// childType returns the type decoding the child element name of an element
+// of type t, or nil when the child has no type of its own, like properties.
+func childType(t reflect.Type, name string) reflect.Type {
+	if t == nil || t.Kind() != reflect.Struct || reflect.PointerTo(t).Implements(unmarshalerType) {
+		return nil
+	}
+	field, ok := fieldByElementName(reflect.New(t).Elem(), name)
+	if !ok {
+		return nil
+	}
+	return indirectType(field.Type())
+}
+
+// childOrder returns the names of the child elements of t in schema order.
+func childOrder(t reflect.Type) []string {
+	if t == nil || t.Kind() != reflect.Struct || reflect.PointerTo(t).Implements(unmarshalerType) {
+		return nil
+	}
+
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if isEmbedded(f) {
+			names = append(names, childOrder(indirectType(f.Type))...)
+			continue
+		}
+		if name, ok := fieldName(f); ok && name != "*" && !strings.HasPrefix(name, "@") {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// find returns the element at the path, the deepest existing element on the
+// way to it and its type, and the segments left.
+func (d *Document) find(segments []segment) (*node, reflect.Type, []segment) {
+	n, t := d.root, reflect.TypeOf(Model{})
+	for i, s := range segments[1:] {
+		child := d.child(n, t, s)
+		if child == nil {
+			return n, t, segments[i+1:]
+		}
+		n, t = child, childType(t, s.name)
+	}
+	return n, t, nil
+}
+
+// child returns the child of n, of type t, matching s.
+func (d *Document) child(n *node, t reflect.Type, s segment) *node {
+	var candidates []*node
+	for _, c := range n.children {
+		if c.name == s.name {
+			candidates = append(candidates, c)
+		}
+	}
+	if !s.hasKey {
+		if len(candidates) == 0 {
+			return nil
+		}
+		return candidates[0]
+	}
+
+	// key the elements the way Diff does
+	ct := childType(t, s.name)
+	if ct == nil {
+		ct = reflect.TypeOf(DOM{})
+	}
+	list := reflect.MakeSlice(reflect.SliceOf(ct), len(candidates), len(candidates))
+	for i, c := range candidates {
+		if err := xml.Unmarshal(d.data[c.start:c.end], list.Index(i).Addr().Interface()); err != nil {
+			return nil
+		}
+	}
+	keys, _ := diffItems(list)
+	for i, key := range keys {
+		if key == s.key {
+			return candidates[i]
+		}
+	}
+	return nil
+}
+
+// lookup returns the element at path, or a *NotFoundError.
+func (d *Document) lookup(path string) (*node, reflect.Type, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	n, t, missing := d.find(segments)
+	if len(missing) > 0 {
+		return nil, nil, &NotFoundError{Path: path}
+	}
+	return n, t, nil
+}
+
+// Get returns the text of the element at path, with surrounding whitespace
+// removed, and whether there is one.
+func (d *Document) Get(path string) (string, bool) {
+	n, _, err := d.lookup(path)
+	if err != nil {
+		return "", false
+	}
+
+	var text struct {
+		Value string `xml:",chardata"`
+	}
+	if err := xml.Unmarshal(d.data[n.start:n.end], &text); err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(text.Value), true
+}
+
+// Has reports whether the document has an element at path.
+func (d *Document) Has(path string) bool {
+	_, _, err := d.lookup(path)
+	return err == nil
+}
+
+// Set sets the text of the element at path to value, adding the element and
+// those leading to it where they are missing. Elements identified by a key
+// cannot be added by Set.
+func (d *Document) Set(path, value string) error {
+	segments, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+
+	var escaped bytes.Buffer
+	if err := xml.EscapeText(&escaped, []byte(value)); err != nil {
+		return err
+	}
+
+	n, _, missing := d.find(segments)
+	if len(missing) > 0 {
+		last := segments[len(segments)-1]
+		if last.hasKey {
+			return fmt.Errorf("pom: cannot add %s, it is identified by a key", path)
+		}
+		parent, err := d.ensure(segments[:len(segments)-1], path)
+		if err != nil {
+			return err
+		}
+		return d.insert(parent, last.name, "<"+last.name+">"+escaped.String()+"</"+last.name+">")
+	}
+
+	if len(n.children) > 0 {
+		return fmt.Errorf("pom: cannot set %s, it has child elements", path)
+	}
+	if n.empty {
+		return d.replace(n.start, n.end, d.openTag(n)+">"+escaped.String()+"</"+n.rawName+">")
+	}
+	return d.replace(n.inner, n.innerEnd, escaped.String())
+}
+
+// Add adds v, such as a Dependency, encoded as the last element of the list
+// at path, like /project/dependencies/dependency. The element holding the
+// list is added if it is missing.
+func (d *Document) Add(path string, v any) error {
+	segments, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	last := segments[len(segments)-1]
+	if last.hasKey || len(segments) < 2 {
+		return fmt.Errorf("pom: invalid path %s to add to", path)
+	}
+
+	parent, err := d.ensure(segments[:len(segments)-1], path)
+	if err != nil {
+		return err
+	}
+
+	var markup bytes.Buffer
+	e := xml.NewEncoder(&markup)
+	e.Indent("", d.indent)
+	if err := e.EncodeElement(v, xml.StartElement{Name: xml.Name{Local: last.name}}); err != nil {
+		return err
+	}
+	return d.insert(parent, last.name, markup.String())
+}
+
+// Remove removes the element at path, with the line it is on when nothing
+// else is. Elements only holding the removed one, like dependencies and
+// dependencyManagement for the last dependency, are removed too.
+func (d *Document) Remove(path string) error {
+	n, _, err := d.lookup(path)
+	if err != nil {
+		return err
+	}
+	if n == d.root {
+		return fmt.Errorf("pom: cannot remove %s", path)
+	}
+
+	for n.parent != d.root && len(n.parent.children) == 1 && isWrapper(typeOf(n.parent)) {
+		n = n.parent
+	}
+
+	start, end := n.start, n.end
+	if _, ok := d.lineIndent(start); ok {
+		rest := end
+		for rest < len(d.data) && (d.data[rest] == ' ' || d.data[rest] == '\t' || d.data[rest] == '\r') {
+			rest++
+		}
+		if rest == len(d.data) || d.data[rest] == '\n' {
+			for start > 0 && d.data[start-1] != '\n' {
+				start--
+			}
+			end = min(rest+1, len(d.data))
+		}
+	}
+	return d.replace(start, end, "")
+}
+
+// Comment adds a comment with text on its own line before the element at
+// path, or before the deepest element on the way to it when there is none.
+// The -- that comments cannot hold are written as - -.
+func (d *Document) Comment(path, text string) error {
+	segments, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	n, _, _ := d.find(segments)
+
+	text = strings.ReplaceAll(text, "--", "- -")
+	if strings.HasSuffix(text, "-") {
+		text += " "
+	}
+	comment := "<!--" + text + "-->"
+	if indent, ok := d.lineIndent(n.start); ok {
+		at := n.start - len(indent)
+		return d.replace(at, at, indent+comment+d.newline)
+	}
+	return d.replace(n.start, n.start, comment)
+}
+
+// ensure adds the elements of segments that are missing and returns the
+// last one.
+func (d *Document) ensure(segments []segment, path string) (*node, error) {
+	for {
+		n, _, missing := d.find(segments)
+		if len(missing) == 0 {
+			return n, nil
+		}
+		if missing[0].hasKey {
+			return nil, &NotFoundError{Path: path}
+		}
+		name := missing[0].name
+		if err := d.insert(n, name, "<"+name+"></"+name+">"); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// typeOf returns the type decoding n.
+func typeOf(n *node) reflect.Type {
+	if n.parent == nil {
+		return reflect.TypeOf(Model{})
+	}
+	return childType(typeOf(n.parent), n.name)
+}
+
+// insert adds the element markup as a child name of parent, after the
+// children that come before it in schema order.
+func (d *Document) insert(parent *node, name, markup string) error {
+	order := childOrder(typeOf(parent))
+	rank := func(name string) int {
+		for i, n := range order {
+			if n == name {
+				return i
+			}
+		}
+		return len(order)
+	}
+
+	var after *node
+	if order == nil || rank(name) == len(order) {
+		if len(parent.children) > 0 {
+			after = parent.children[len(parent.children)-1]
+		}
+	} else {
+		for _, c := range parent.children {
+			if rank(c.name) <= rank(name) {
+				after = c
+			}
+		}
+	}
+
+	reindent := func(indent string) string {
+		return strings.ReplaceAll(markup, "\n", d.newline+indent)
+	}
+
+	switch {
+	case after != nil:
+		indent := d.indentOf(after)
+		at := d.lineEnd(after.end)
+		return d.replace(at, at, d.newline+indent+reindent(indent))
+	case len(parent.children) > 0:
+		first := parent.children[0]
+		indent := d.indentOf(first)
+		return d.replace(first.start, first.start, reindent(indent)+d.newline+indent)
+	}
+
+	parentIndent := d.indentOf(parent)
+	indent := parentIndent + d.indent
+	content := d.newline + indent + reindent(indent) + d.newline + parentIndent
+	if parent.empty {
+		return d.replace(parent.start, parent.end, d.openTag(parent)+">"+content+"</"+parent.rawName+">")
+	}
+	if len(bytes.TrimSpace(d.data[parent.inner:parent.innerEnd])) > 0 {
+		return errors.New("pom: cannot add an element to " + parent.name + ", it has a value")
+	}
+	return d.replace(parent.inner, parent.innerEnd, content)
+}
+
+// lineEnd returns the end of the line of offset when only comments follow
+// it, so that they stay with the element they are about, and offset
+// otherwise.
+func (d *Document) lineEnd(offset int) int {
+	i := offset
+	for i < len(d.data) {
+		switch {
+		case d.data[i] == ' ' || d.data[i] == '\t':
+			i++
+		case bytes.HasPrefix(d.data[i:], []byte("<!--")):
+			end := bytes.Index(d.data[i:], []byte("-->"))
+			if end < 0 {
+				return offset
+			}
+			i += end + len("-->")
+		case d.data[i] == '\r' || d.data[i] == '\n':
+			return i
+		default:
+			return offset
+		}
+	}
+	return i
+}
+
+// isWrapper reports whether t only holds one child element, like
+// Dependencies or DependencyManagement, so it is no use without it.
+func isWrapper(t reflect.Type) bool {
+	if t == nil || t.Kind() != reflect.Struct {
+		return false
+	}
+	return len(childOrder(t)) == 1
+}
+
+// openTag returns the start tag of an empty element without its closing />.
+func (d *Document) openTag(n *node) string {
+	tag := strings.TrimSuffix(string(d.data[n.start:n.end]), "/>")
+	return strings.TrimRight(tag, " \t\r\n")
+}
+
+func (d *Document) replace(start, end int, text string) error {
+	data := make([]byte, 0, len(d.data)-(end-start)+len(text))
+	data = append(data, d.data[:start]...)
+	data = append(data, text...)
+	data = append(data, d.data[end:]...)
+
+	previous := d.data
+	d.data = data
+	if err := d.parse(); err != nil {
+		d.data = previous
+		d.parse()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package pom_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+func readDocument(t *testing.T) (*pom.Document, string) {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "document", "pom.xml"))
+	if err != nil {
+		t.Fatalf("Expected no errors reading pom, but found: %s", err.Error())
+	}
+	d, err := pom.ParseDocument(data)
+	if err != nil {
+		t.Fatalf("Expected no errors parsing the document, but found: %s", err.Error())
+	}
+	return d, string(data)
+}
+
+func TestDocument(t *testing.T) {
+	t.Run("Should get values by path", func(t *testing.T) {
+		d, _ := readDocument(t)
+
+		for path, expected := range map[string]string{
+			"version":                           "1.0.0-SNAPSHOT",
+			"/project/properties/slf4j.version": "2.0.9",
+			"dependencies/dependency[org.junit.jupiter:junit-jupiter:jar:]/scope": "test",
+		} {
+			if value, ok := d.Get(path); !ok || value != expected {
+				t.Errorf("Expected %s at %s, but found: %s", expected, path, value)
+			}
+		}
+		if d.Has("build") {
+			t.Errorf("Expected no build element")
+		}
+	})
+
+	t.Run("Should only change the text of the element set", func(t *testing.T) {
+		d, original := readDocument(t)
+
+		if err := d.Set("version", "1.0.0"); err != nil {
+			t.Fatalf("Expected no errors setting the version, but found: %s", err.Error())
+		}
+		expected := strings.Replace(original, "1.0.0-SNAPSHOT", "1.0.0", 1)
+		if string(d.Bytes()) != expected {
+			t.Errorf("Expected:\n%s\nbut found:\n%s", expected, d.Bytes())
+		}
+	})
+
+	t.Run("Should add missing elements in schema order with the indentation of the document", func(t *testing.T) {
+		d, _ := readDocument(t)
+
+		if err := d.Set("build/finalName", "service"); err != nil {
+			t.Fatalf("Expected no errors setting the final name, but found: %s", err.Error())
+		}
+		if err := d.Set("name", "Service"); err != nil {
+			t.Fatalf("Expected no errors setting the name, but found: %s", err.Error())
+		}
+
+		out := string(d.Bytes())
+		if !strings.Contains(out, "\t<build>\n\t\t<finalName>service</finalName>\n\t</build>\n</project>") {
+			t.Errorf("Expected build at the end, but found:\n%s", out)
+		}
+		if !strings.Contains(out, "<version>1.0.0-SNAPSHOT</version>\n\t<name>Service</name>\n") {
+			t.Errorf("Expected name after version, but found:\n%s", out)
+		}
+		if !strings.HasPrefix(out, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<!-- keep this header -->\n") {
+			t.Errorf("Expected the header to be kept, but found:\n%s", out)
+		}
+	})
+
+	t.Run("Should add and remove dependencies", func(t *testing.T) {
+		d, original := readDocument(t)
+
+		guava := pom.Dependency{GroupId: "com.google.guava", ArtifactId: "guava", Version: "33.0.0-jre"}
+		if err := d.Add("dependencyManagement/dependencies/dependency", guava); err != nil {
+			t.Fatalf("Expected no errors adding guava, but found: %s", err.Error())
+		}
+		m, err := d.Model()
+		if err != nil {
+			t.Fatalf("Expected no errors decoding the document, but found: %s", err.Error())
+		}
+		if m.DependencyManagement == nil || len(m.DependencyManagement.Dependencies.Dependency) != 1 {
+			t.Fatalf("Expected guava to be managed, but found:\n%s", d.Bytes())
+		}
+
+		if err := d.Remove("dependencyManagement/dependencies/dependency[com.google.guava:guava:jar:]"); err != nil {
+			t.Fatalf("Expected no errors removing guava, but found: %s", err.Error())
+		}
+		if string(d.Bytes()) != original {
+			t.Errorf("Expected the original document, but found:\n%s", d.Bytes())
+		}
+	})
+
+	t.Run("Should add comments before elements", func(t *testing.T) {
+		d, original := readDocument(t)
+
+		if err := d.Comment("version", " released -- soon "); err != nil {
+			t.Fatalf("Expected no errors adding the comment, but found: %s", err.Error())
+		}
+		if err := d.Comment("build/finalName", " no build "); err != nil {
+			t.Fatalf("Expected no errors adding the comment, but found: %s", err.Error())
+		}
+		expected := strings.Replace(original, "\t<version>", "\t<!-- released - - soon -->\n\t<version>", 1)
+		expected = strings.Replace(expected, "<project", "<!-- no build -->\n<project", 1)
+		if string(d.Bytes()) != expected {
+			t.Errorf("Expected:\n%s\nbut found:\n%s", expected, d.Bytes())
+		}
+	})
+
+	t.Run("Should report missing elements", func(t *testing.T) {
+		d, _ := readDocument(t)
+
+		var notFound *pom.NotFoundError
+		if err := d.Remove("dependencies/dependency[com.google.guava:guava:jar:]"); !errors.As(err, &notFound) {
+			t.Errorf("Expected a NotFoundError, but found: %v", err)
+		}
+	})
+}
@@ -0,0 +1,368 @@
+package pom
+
+import (
+	"errors"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// EffectiveOptions configures how the effective model of a POM is built.
+type EffectiveOptions struct {
+	// Dir is the directory holding the POM. Parents are first looked up by
+	// their relativePath from it, and file activation and ${project.basedir}
+	// are relative to it. Empty disables all three.
+	Dir string
+	// Resolver finds the parents that are not in the source tree and the
+	// boms imported in dependency management. When nil they cannot be found.
+	Resolver ModelResolver
+	// Properties are user properties, like -D options on the command line,
+	// taking precedence over the properties of the POM. They also feed the
+	// activation of profiles, including java.version for jdk activation and
+	// os.name, os.arch and os.version for os activation.
+	Properties map[string]string
+	// ActiveProfiles and InactiveProfiles are the ids of profiles activated
+	// or deactivated explicitly, like the -P option.
+	ActiveProfiles   []string
+	InactiveProfiles []string
+	// SuperPOM is the model all POMs inherit from last, like the one
+	// returned by SuperPOM. When nil, the defaults of Maven are not applied.
+	SuperPOM *Model
+}
+
+// Effective returns the model Maven builds from m, leaving m unchanged:
+//
+//   - the active profiles of m and of each of its parents are injected,
+//   - m inherits from its parents, and then from the Super POM when given,
+//   - ${...} expressions are interpolated,
+//   - boms imported in dependency management are replaced by their managed
+//     dependencies,
+//   - plugin management is applied to the build plugins and dependency
+//     management to the dependencies.
+//
+// A nil opts is the same as empty EffectiveOptions. The error is an
+// *UnresolvableModelError when a parent or imported bom cannot be found.
+func (m *Model) Effective(opts *EffectiveOptions) (*Model, error) {
+	if opts == nil {
+		opts = &EffectiveOptions{}
+	}
+
+	b := &builder{
+		opts: opts,
+		activator: activator{
+			props:    opts.Properties,
+			active:   opts.ActiveProfiles,
+			inactive: opts.InactiveProfiles,
+		},
+		importing: make(map[string]bool),
+	}
+	return b.build(m, opts.Dir)
+}
+
+type builder struct {
+	opts *EffectiveOptions
+	activator
+	// importing holds the boms being imported, to stop at cycles.
+	importing map[string]bool
+}
+
+func (b *builder) build(m *Model, dir string) (*Model, error) {
+	lineage, err := b.lineage(m, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if b.opts.SuperPOM != nil {
+		super := b.opts.SuperPOM.Clone()
+		for _, p := range b.activeProfiles(super, "") {
+			injectProfile(super, p)
+		}
+		lineage = append(lineage, super)
+	}
+
+	eff := lineage[len(lineage)-1]
+	for i := len(lineage) - 2; i >= 0; i-- {
+		inherit(lineage[i], eff)
+		eff = lineage[i]
+	}
+
+	interpolateModel(eff, eff.Clone(), b.properties(dir))
+
+	if err := b.importBoms(eff); err != nil {
+		return nil, err
+	}
+	injectPluginManagement(eff)
+	injectDependencyManagement(eff)
+
+	return eff, nil
+}
+
+// properties returns the user properties along with the location of the
+// project.
+func (b *builder) properties(dir string) map[string]string {
+	if dir == "" {
+		return b.opts.Properties
+	}
+
+	props := maps.Clone(b.opts.Properties)
+	if props == nil {
+		props = make(map[string]string)
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	props["basedir"] = dir
+	props["project.basedir"] = dir
+	props["project.baseUri"] = "file://" + filepath.ToSlash(dir) + "/"
+	return props
+}
+
+// lineage returns copies of m and its parents, closest first, with their
+// active profiles injected.
+func (b *builder) lineage(m *Model, dir string) ([]*Model, error) {
+	var lineage []*Model
+	seen := make(map[string]bool)
+
+	for {
+		c := m.Clone()
+		for _, p := range b.activeProfiles(c, dir) {
+			injectProfile(c, p)
+		}
+		lineage = append(lineage, c)
+
+		if c.Parent == nil {
+			return lineage, nil
+		}
+
+		ref := b.parentReference(c)
+		id := ref.GroupId + ":" + ref.ArtifactId + ":" + ref.Version
+		if seen[id] {
+			return nil, &UnresolvableModelError{
+				GroupId:    ref.GroupId,
+				ArtifactId: ref.ArtifactId,
+				Version:    ref.Version,
+				Err:        errors.New("the parents form a cycle"),
+			}
+		}
+		seen[id] = true
+
+		var err error
+		if m, dir, err = b.resolveParent(ref, dir); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parentReference returns the parent of m with expressions in its
+// coordinates, such as ${revision}, resolved from the user properties and
+// those of m.
+func (b *builder) parentReference(m *Model) Parent {
+	in := newInterpolator(modelLookup(m, b.opts.Properties))
+
+	ref := *m.Parent
+	ref.GroupId = in.expand(ref.GroupId)
+	ref.ArtifactId = in.expand(ref.ArtifactId)
+	ref.Version = in.expand(ref.Version)
+	return ref
+}
+
+// resolveParent finds the parent POM, first at its relativePath from dir,
+// which defaults to ../pom.xml, then with the resolver. It returns the
+// directory of the parent, or "" when it does not come from the source tree.
+func (b *builder) resolveParent(ref Parent, dir string) (*Model, string, error) {
+	if dir != "" {
+		path := filepath.Join(dir, filepath.FromSlash(orDefault(ref.RelativePath, "../pom.xml")))
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			path = filepath.Join(path, "pom.xml")
+		}
+
+		parent, err := ReadFile(path)
+		switch {
+		case err == nil && b.isParent(parent, ref):
+			return parent, filepath.Dir(path), nil
+		case err != nil && !errors.Is(err, fs.ErrNotExist):
+			return nil, "", &UnresolvableModelError{GroupId: ref.GroupId, ArtifactId: ref.ArtifactId, Version: ref.Version, Err: err}
+		}
+	}
+
+	if b.opts.Resolver == nil {
+		return nil, "", &UnresolvableModelError{
+			GroupId:    ref.GroupId,
+			ArtifactId: ref.ArtifactId,
+			Version:    ref.Version,
+			Err:        errors.New("not found in the source tree and no resolver was given"),
+		}
+	}
+
+	parent, err := b.opts.Resolver.ResolveModel(ref.GroupId, ref.ArtifactId, ref.Version)
+	if err != nil {
+		return nil, "", &UnresolvableModelError{GroupId: ref.GroupId, ArtifactId: ref.ArtifactId, Version: ref.Version, Err: err}
+	}
+	return parent, "", nil
+}
+
+// isParent reports whether m has the coordinates of ref. A reference without
+// a version, allowed by Maven 4, matches any version, and so does one whose
+// version is an expression defined by the parent itself, like ${revision}.
+func (b *builder) isParent(m *Model, ref Parent) bool {
+	groupId, version := m.GroupId, m.Version
+	if m.Parent != nil {
+		groupId = orDefault(groupId, m.Parent.GroupId)
+		version = orDefault(version, m.Parent.Version)
+	}
+	version = newInterpolator(modelLookup(m, b.opts.Properties)).expand(version)
+
+	return m.ArtifactId == ref.ArtifactId && groupId == ref.GroupId &&
+		(ref.Version == "" || strings.Contains(ref.Version, "${") || version == ref.Version)
+}
+
+// inherit merges what child inherits from its effective parent into child.
+func inherit(child, parent *Model) {
+	p := parent.Clone()
+
+	// elements that are not inherited
+	p.ModelVersion, p.ArtifactId, p.Packaging, p.Name, p.Root = "", "", "", "", ""
+	p.Parent, p.Prerequisites, p.Profiles, p.Modules, p.Subprojects = nil, nil, nil, nil, nil
+	p.Any, p.AnyAttrs, p.Encoding = nil, nil, ""
+	if p.DistributionManagement != nil {
+		p.DistributionManagement.Relocation = nil
+	}
+
+	// urls are extended with the artifactId of the child unless disabled
+	p.Url = appendPath(p.Url, p.ChildProjectUrlInheritAppendPath, child.ArtifactId)
+	if p.Scm != nil {
+		p.Scm.Connection = appendPath(p.Scm.Connection, p.Scm.ChildScmConnectionInheritAppendPath, child.ArtifactId)
+		p.Scm.DeveloperConnection = appendPath(p.Scm.DeveloperConnection, p.Scm.ChildScmDeveloperConnectionInheritAppendPath, child.ArtifactId)
+		p.Scm.Url = appendPath(p.Scm.Url, p.Scm.ChildScmUrlInheritAppendPath, child.ArtifactId)
+	}
+	if p.DistributionManagement != nil && p.DistributionManagement.Site != nil {
+		site := p.DistributionManagement.Site
+		site.Url = appendPath(site.Url, site.ChildSiteUrlInheritAppendPath, child.ArtifactId)
+	}
+
+	merger{inheritance: true}.mergeInto(child, p)
+}
+
+func appendPath(url, appendPath, name string) string {
+	if url == "" || name == "" || strings.EqualFold(appendPath, "false") {
+		return url
+	}
+	return strings.TrimSuffix(url, "/") + "/" + name
+}
+
+// importBoms replaces the dependencies of scope import in the dependency
+// management of m with the dependencies managed by those boms. Dependencies
+// m manages itself take precedence, then boms in declaration order.
+func (b *builder) importBoms(m *Model) error {
+	if m.DependencyManagement == nil || m.DependencyManagement.Dependencies == nil {
+		return nil
+	}
+	deps := m.DependencyManagement.Dependencies
+
+	var managed, imported []Dependency
+	for _, d := range deps.Dependency {
+		if d.Scope != ScopeImport || d.Type != "pom" {
+			managed = append(managed, d)
+			continue
+		}
+
+		bom, err := b.importBom(d)
+		if err != nil {
+			return err
+		}
+		if bom.DependencyManagement != nil && bom.DependencyManagement.Dependencies != nil {
+			imported = append(imported, bom.DependencyManagement.Dependencies.Dependency...)
+		}
+	}
+
+	keys := make(map[string]bool, len(managed))
+	for i := range managed {
+		keys[managed[i].ManagementKey()] = true
+	}
+	for i := range imported {
+		if key := imported[i].ManagementKey(); !keys[key] {
+			keys[key] = true
+			managed = append(managed, imported[i])
+		}
+	}
+	deps.Dependency = managed
+
+	return nil
+}
+
+func (b *builder) importBom(d Dependency) (*Model, error) {
+	id := d.GroupId + ":" + d.ArtifactId + ":" + d.Version
+	fail := func(err error) (*Model, error) {
+		return nil, &UnresolvableModelError{GroupId: d.GroupId, ArtifactId: d.ArtifactId, Version: d.Version, Err: err}
+	}
+
+	if b.importing[id] {
+		return fail(errors.New("the imported boms form a cycle"))
+	}
+	if b.opts.Resolver == nil {
+		return fail(errors.New("imported boms cannot be found without a resolver"))
+	}
+
+	bom, err := b.opts.Resolver.ResolveModel(d.GroupId, d.ArtifactId, d.Version)
+	if err != nil {
+		return fail(err)
+	}
+
+	b.importing[id] = true
+	defer delete(b.importing, id)
+	return b.build(bom, "")
+}
+
+// injectPluginManagement merges the managed version, configuration,
+// executions and dependencies into the build plugins of m.
+func injectPluginManagement(m *Model) {
+	if m.Build == nil || m.Build.Plugins == nil || m.Build.PluginManagement == nil || m.Build.PluginManagement.Plugins == nil {
+		return
+	}
+
+	managed := make(map[string]*Plugin)
+	for i := range m.Build.PluginManagement.Plugins.Plugin {
+		p := &m.Build.PluginManagement.Plugins.Plugin[i]
+		managed[p.Key()] = p
+	}
+
+	for i := range m.Build.Plugins.Plugin {
+		p := &m.Build.Plugins.Plugin[i]
+		if mp, ok := managed[p.Key()]; ok {
+			merger{}.mergeInto(p, mp)
+		}
+	}
+}
+
+// injectDependencyManagement fills in the version, scope, exclusions, optional
+// flag and system path of the dependencies of m from its dependency
+// management, where they are not given.
+func injectDependencyManagement(m *Model) {
+	if m.Dependencies == nil || m.DependencyManagement == nil || m.DependencyManagement.Dependencies == nil {
+		return
+	}
+
+	managed := make(map[string]*Dependency)
+	for i := range m.DependencyManagement.Dependencies.Dependency {
+		d := &m.DependencyManagement.Dependencies.Dependency[i]
+		managed[d.ManagementKey()] = d
+	}
+
+	for i := range m.Dependencies.Dependency {
+		d := &m.Dependencies.Dependency[i]
+		md, ok := managed[d.ManagementKey()]
+		if !ok {
+			continue
+		}
+
+		d.Version = orDefault(d.Version, md.Version)
+		d.Scope = orDefault(d.Scope, md.Scope)
+		d.Optional = orDefault(d.Optional, md.Optional)
+		d.SystemPath = orDefault(d.SystemPath, md.SystemPath)
+		if (d.Exclusions == nil || len(d.Exclusions.Exclusion) == 0) && md.Exclusions != nil {
+			d.Exclusions = &Exclusions{Exclusion: append([]Exclusion(nil), md.Exclusions.Exclusion...)}
+		}
+	}
+}
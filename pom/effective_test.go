@@ -0,0 +1,196 @@
+package pom_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+var effectiveRepository = pom.LocalRepository{Dir: filepath.Join("testdata", "effective", "repository")}
+
+func readCore(t *testing.T) *pom.Model {
+	t.Helper()
+
+	m, err := pom.ReadFile(filepath.Join("testdata", "effective", "core", "pom.xml"))
+	if err != nil {
+		t.Fatalf("Expected no errors reading pom, but found: %s", err.Error())
+	}
+	return m
+}
+
+func effectiveCore(t *testing.T, opts pom.EffectiveOptions) *pom.Model {
+	t.Helper()
+
+	opts.Dir = filepath.Join("testdata", "effective", "core")
+	opts.Resolver = effectiveRepository
+	eff, err := readCore(t).Effective(&opts)
+	if err != nil {
+		t.Fatalf("Expected no errors building the effective model, but found: %s", err.Error())
+	}
+	return eff
+}
+
+func findDependency(m *pom.Model, artifactId string) *pom.Dependency {
+	if m.Dependencies == nil {
+		return nil
+	}
+	for i := range m.Dependencies.Dependency {
+		if m.Dependencies.Dependency[i].ArtifactId == artifactId {
+			return &m.Dependencies.Dependency[i]
+		}
+	}
+	return nil
+}
+
+func findPlugin(m *pom.Model, artifactId string) *pom.Plugin {
+	if m.Build == nil || m.Build.Plugins == nil {
+		return nil
+	}
+	for i := range m.Build.Plugins.Plugin {
+		if m.Build.Plugins.Plugin[i].ArtifactId == artifactId {
+			return &m.Build.Plugins.Plugin[i]
+		}
+	}
+	return nil
+}
+
+func TestEffective(t *testing.T) {
+	t.Run("Should inherit from the parents and interpolate", func(t *testing.T) {
+		eff := effectiveCore(t, pom.EffectiveOptions{})
+
+		if eff.GroupId != "org.example" || eff.Version != "1.2.0-SNAPSHOT" {
+			t.Errorf("Expected org.example 1.2.0-SNAPSHOT, but found: %s %s", eff.GroupId, eff.Version)
+		}
+		if eff.Parent.Version != "1.2.0-SNAPSHOT" {
+			t.Errorf("Expected the parent version to be interpolated, but found: %s", eff.Parent.Version)
+		}
+		if eff.Name != "Example core" || eff.Packaging != "" {
+			t.Errorf("Expected the name and packaging not to be inherited, but found: %q %q", eff.Name, eff.Packaging)
+		}
+		if eff.Url != "https://example.org/project/core" || eff.Scm.Connection != "scm:git:https://example.org/project.git/core" {
+			t.Errorf("Expected urls extended with the artifactId, but found: %s %s", eff.Url, eff.Scm.Connection)
+		}
+		if eff.Licenses == nil || eff.Licenses.License[0].Name != "Apache-2.0" {
+			t.Errorf("Expected the license of the corporate parent, but found: %+v", eff.Licenses)
+		}
+		if eff.Modules != nil {
+			t.Errorf("Expected modules not to be inherited, but found: %+v", eff.Modules)
+		}
+	})
+
+	t.Run("Should apply dependency management and imported boms", func(t *testing.T) {
+		eff := effectiveCore(t, pom.EffectiveOptions{})
+
+		if d := findDependency(eff, "slf4j-api"); d.Version != "2.0.9" {
+			t.Errorf("Expected slf4j-api 2.0.9 managed by the parent, but found: %s", d.Version)
+		}
+		if d := findDependency(eff, "guava"); d.Version != "33.0.0-jre" {
+			t.Errorf("Expected guava 33.0.0-jre from the bom, but found: %s", d.Version)
+		}
+		if d := findDependency(eff, "junit-jupiter"); d.Version != "5.10.2" || d.Scope != "test" {
+			t.Errorf("Expected junit-jupiter 5.10.2 test from the bom, but found: %s %s", d.Version, d.Scope)
+		}
+		for _, d := range eff.DependencyManagement.Dependencies.Dependency {
+			if d.Scope == pom.ScopeImport {
+				t.Errorf("Expected imports to be replaced, but found: %s", d.ArtifactId)
+			}
+		}
+	})
+
+	t.Run("Should merge plugins with their management and parents", func(t *testing.T) {
+		eff := effectiveCore(t, pom.EffectiveOptions{})
+
+		if findPlugin(eff, "maven-enforcer-plugin") != nil {
+			t.Errorf("Expected the enforcer plugin not to be inherited")
+		}
+		if p := findPlugin(eff, "maven-surefire-plugin"); p == nil || p.Executions.Execution[0].Id != "integration" {
+			t.Errorf("Expected the surefire plugin with its execution to be inherited, but found: %+v", p)
+		}
+
+		compiler := findPlugin(eff, "maven-compiler-plugin")
+		if compiler == nil || compiler.Version != "3.11.0" {
+			t.Fatalf("Expected the managed compiler plugin version, but found: %+v", compiler)
+		}
+		var release, showWarnings string
+		var args []string
+		for _, c := range compiler.Configuration.Children {
+			switch c.XMLName.Local {
+			case "release":
+				release = c.Value
+			case "showWarnings":
+				showWarnings = c.Value
+			case "compilerArgs":
+				for _, arg := range c.Children {
+					args = append(args, arg.Value)
+				}
+			}
+		}
+		if release != "17" || showWarnings != "true" {
+			t.Errorf("Expected the merged configuration, but found: release %q showWarnings %q", release, showWarnings)
+		}
+		if len(args) != 2 || args[0] != "-parameters" || args[1] != "-Xlint:all" {
+			t.Errorf("Expected appended compiler args, but found: %v", args)
+		}
+	})
+
+	t.Run("Should activate profiles", func(t *testing.T) {
+		eff := effectiveCore(t, pom.EffectiveOptions{})
+		if eff.Properties.Fields["profile.defaults"] != "active" || findDependency(eff, "commons-lang3") != nil {
+			t.Errorf("Expected only the default profile to be active")
+		}
+
+		eff = effectiveCore(t, pom.EffectiveOptions{Properties: map[string]string{"lang": "true"}})
+		if findDependency(eff, "commons-lang3") == nil {
+			t.Errorf("Expected the lang profile to be activated by its property")
+		}
+		if _, ok := eff.Properties.Fields["profile.defaults"]; ok {
+			t.Errorf("Expected the default profile to be inactive when another profile is active")
+		}
+
+		eff = effectiveCore(t, pom.EffectiveOptions{ActiveProfiles: []string{"lang"}, InactiveProfiles: []string{"defaults"}})
+		if findDependency(eff, "commons-lang3") == nil || eff.Properties.Fields["profile.defaults"] != "" {
+			t.Errorf("Expected the explicitly listed profiles to be (de)activated")
+		}
+	})
+
+	t.Run("Should prefer user properties", func(t *testing.T) {
+		eff := effectiveCore(t, pom.EffectiveOptions{Properties: map[string]string{"revision": "2.0.0"}})
+
+		if eff.Version != "2.0.0" {
+			t.Errorf("Expected version 2.0.0, but found: %s", eff.Version)
+		}
+	})
+
+	t.Run("Should report a parent that cannot be resolved", func(t *testing.T) {
+		_, err := readCore(t).Effective(nil)
+
+		var resolveErr *pom.UnresolvableModelError
+		if !errors.As(err, &resolveErr) {
+			t.Fatalf("Expected an UnresolvableModelError, but found: %v", err)
+		}
+		if resolveErr.ArtifactId != "parent" {
+			t.Errorf("Expected the parent to be unresolvable, but found: %s", resolveErr.ArtifactId)
+		}
+	})
+}
+
+func TestInterpolate(t *testing.T) {
+	t.Run("Should replace expressions and leave unknown ones", func(t *testing.T) {
+		m := pom.New()
+		m.ArtifactId = "demo"
+		m.Version = "${major}.${minor}"
+		m.Description = "${project.artifactId} ${undefined} ${cycle}"
+		m.Properties = &pom.Properties{Fields: map[string]string{"major": "1", "minor": "${patch}", "patch": "5", "cycle": "${cycle}"}}
+
+		m.Interpolate(map[string]string{"major": "3"})
+
+		if m.Version != "3.5" {
+			t.Errorf("Expected version 3.5, but found: %s", m.Version)
+		}
+		if m.Description != "demo ${undefined} ${cycle}" {
+			t.Errorf("Expected unresolvable expressions to be kept, but found: %s", m.Description)
+		}
+	})
+}
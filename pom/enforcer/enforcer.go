@@ -0,0 +1,133 @@
+// Package enforcer implements the most used rules of the Maven Enforcer
+// plugin over a POM and its resolved dependency graph, so that they can be
+// checked without a Maven build.
+//
+// Rules are named after their Maven counterparts and report structured
+// violations, with the paths through the dependency graph that lead to the
+// offending dependencies:
+//
+//	violations, err := enforcer.Enforce(&enforcer.Project{Model: m, Graph: root},
+//		enforcer.DependencyConvergence{}, enforcer.RequireReleaseDeps{})
+//
+// The graph must be resolved in full mode for the rules that compare the
+// versions requested along different paths, like DependencyConvergence, so
+// that the versions requested below the omitted dependencies are compared
+// too.
+package enforcer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/obscurelyme/encoding/pom"
+	"github.com/obscurelyme/encoding/pom/resolve"
+)
+
+// ErrNoGraph is returned by rules that check the dependency graph of a
+// Project without one.
+var ErrNoGraph = errors.New("enforcer: the rule needs a resolved dependency graph")
+
+// Project is what rules check.
+type Project struct {
+	// Model is the POM as read, for the rules about what it declares, like
+	// BanDuplicatePomDependencyVersions.
+	Model *pom.Model
+	// Effective is the effective model of the POM, for the rules about
+	// inherited elements like plugin versions. It defaults to Model.
+	Effective *pom.Model
+	// Graph is the dependency graph of the project returned by
+	// resolve.Resolve in full mode.
+	Graph *resolve.Node
+	// MavenVersion and JavaVersion are the versions of the build, checked
+	// by RequireMavenVersion and RequireJavaVersion instead of those
+	// declared by the POM when set.
+	MavenVersion string
+	JavaVersion  string
+}
+
+func (p *Project) effective() *pom.Model {
+	if p.Effective != nil {
+		return p.Effective
+	}
+	return p.Model
+}
+
+// Rule is a check of a Project.
+type Rule interface {
+	// ID is the name of the rule in the configuration of the Maven Enforcer
+	// plugin, like dependencyConvergence.
+	ID() string
+	// Check returns the violations of the rule by p.
+	Check(p *Project) ([]Violation, error)
+}
+
+// Violation is a breach of a rule.
+type Violation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+	// Path locates the offending element of the POM like the Path of a
+	// pom.Change, if any.
+	Path string `json:"path,omitempty"`
+	// Dependencies are the paths through the dependency graph to the
+	// offending dependencies.
+	Dependencies []DependencyPath `json:"dependencies,omitempty"`
+}
+
+func (v Violation) String() string {
+	var b strings.Builder
+	b.WriteString(v.Rule)
+	b.WriteString(": ")
+	if v.Path != "" {
+		b.WriteString(v.Path)
+		b.WriteString(": ")
+	}
+	b.WriteString(v.Message)
+	for _, path := range v.Dependencies {
+		b.WriteString("\n  ")
+		b.WriteString(path.String())
+	}
+	return b.String()
+}
+
+// DependencyPath is the groupId:artifactId:version coordinates of the
+// dependencies from the project to a dependency of its graph, the project
+// first.
+type DependencyPath []string
+
+func (p DependencyPath) String() string {
+	return strings.Join(p, " -> ")
+}
+
+// pathTo returns the path from the root of the graph of n to n.
+func pathTo(n *resolve.Node) DependencyPath {
+	var path DependencyPath
+	for ; n != nil; n = n.Parent() {
+		path = append(path, coordinates(&n.Dependency))
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+func coordinates(d *pom.Dependency) string {
+	return d.GroupId + ":" + d.ArtifactId + ":" + d.Version
+}
+
+// Enforce checks p with rules and returns their violations, in the order of
+// the rules.
+func Enforce(p *Project, rules ...Rule) ([]Violation, error) {
+	var violations []Violation
+	for _, r := range rules {
+		found, err := r.Check(p)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", r.ID(), err)
+		}
+		for _, v := range found {
+			v.Rule = r.ID()
+			violations = append(violations, v)
+		}
+	}
+	return violations, nil
+}
@@ -0,0 +1,200 @@
+package enforcer_test
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+	"github.com/obscurelyme/encoding/pom/enforcer"
+	"github.com/obscurelyme/encoding/pom/resolve"
+)
+
+// graphProject returns the project of the pom.xml in testdata, with its full
+// graph.
+func graphProject(t *testing.T, testdata string) *enforcer.Project {
+	t.Helper()
+
+	repository := pom.LocalRepository{Dir: filepath.Join(testdata, "repository")}
+	m, err := pom.ReadFile(filepath.Join(testdata, "pom.xml"))
+	if err != nil {
+		t.Fatalf("Expected no errors reading pom, but found: %s", err.Error())
+	}
+	eff, err := m.Effective(&pom.EffectiveOptions{Resolver: repository})
+	if err != nil {
+		t.Fatalf("Expected no errors building the effective model, but found: %s", err.Error())
+	}
+	root, err := resolve.Resolve(eff, &resolve.Options{Resolver: repository, Full: true})
+	if err != nil {
+		t.Fatalf("Expected no errors resolving dependencies, but found: %s", err.Error())
+	}
+	return &enforcer.Project{Model: m, Effective: eff, Graph: root}
+}
+
+func modelProject(t *testing.T) *enforcer.Project {
+	t.Helper()
+
+	m, err := pom.ReadFile(filepath.Join("testdata", "pom.xml"))
+	if err != nil {
+		t.Fatalf("Expected no errors reading pom, but found: %s", err.Error())
+	}
+	return &enforcer.Project{Model: m}
+}
+
+func enforce(t *testing.T, p *enforcer.Project, rule enforcer.Rule) []enforcer.Violation {
+	t.Helper()
+
+	violations, err := enforcer.Enforce(p, rule)
+	if err != nil {
+		t.Fatalf("Expected no errors enforcing %s, but found: %s", rule.ID(), err.Error())
+	}
+	for _, v := range violations {
+		if v.Rule != rule.ID() {
+			t.Errorf("Expected violations of %s, but found: %s", rule.ID(), v)
+		}
+	}
+	return violations
+}
+
+func TestGraphRules(t *testing.T) {
+	p := graphProject(t, filepath.Join("..", "resolve", "testdata"))
+
+	t.Run("Should report dependencies that do not converge", func(t *testing.T) {
+		violations := enforce(t, p, enforcer.DependencyConvergence{})
+
+		if len(violations) != 1 || !strings.Contains(violations[0].Message, "org.example:core:jar: does not converge: versions 1.0, 1.5") {
+			t.Fatalf("Expected core not to converge, but found: %v", violations)
+		}
+		paths := violations[0].Dependencies
+		if len(paths) != 2 || paths[1].String() != "org.example:app:1.0.0 -> org.example:util:1.0 -> org.example:core:1.5" {
+			t.Errorf("Expected the paths to both versions, but found: %v", paths)
+		}
+
+		if excluded := enforce(t, p, enforcer.DependencyConvergence{Excludes: []string{"org.example:core"}}); len(excluded) != 0 {
+			t.Errorf("Expected no violations for excluded dependencies, but found: %v", excluded)
+		}
+	})
+
+	t.Run("Should report dependencies that do not converge below omitted ones", func(t *testing.T) {
+		violations := enforce(t, graphProject(t, filepath.Join("testdata", "convergence")), enforcer.DependencyConvergence{})
+
+		if len(violations) != 2 || !strings.Contains(violations[1].Message, "org.example:json:jar: does not converge: versions 2.0, 1.0") {
+			t.Fatalf("Expected api and json not to converge, but found: %v", violations)
+		}
+		paths := violations[1].Dependencies
+		if len(paths) != 2 || paths[1].String() != "org.example:app:1.0 -> org.example:service:1.0 -> org.example:api:2.0 -> org.example:json:1.0" {
+			t.Errorf("Expected the path below the omitted api, but found: %v", paths)
+		}
+	})
+
+	t.Run("Should report dependencies older than requested", func(t *testing.T) {
+		violations := enforce(t, p, enforcer.RequireUpperBoundDeps{})
+
+		if len(violations) != 1 || violations[0].Message != "dependency org.example:core:jar: is resolved to 1.0, older than the requested 1.5" {
+			t.Errorf("Expected core to be reported, but found: %v", violations)
+		}
+	})
+
+	t.Run("Should ban dependencies by pattern", func(t *testing.T) {
+		violations := enforce(t, p, enforcer.BannedDependencies{Excludes: []string{"org.example:*:[1.0,1.2)"}, Includes: []string{"org.example:web"}})
+
+		var banned []string
+		for _, v := range violations {
+			banned = append(banned, v.Dependencies[0][len(v.Dependencies[0])-1])
+		}
+		expected := "org.example:core:1.0 org.example:logging:1.1 org.example:util:1.0 org.example:lib:1.0 org.example:common:1.0"
+		if strings.Join(banned, " ") != expected {
+			t.Errorf("Expected %s to be banned, but found: %v", expected, banned)
+		}
+
+		direct := enforce(t, p, enforcer.BannedDependencies{Excludes: []string{"*:*:*:*:test"}, DirectOnly: true})
+		if len(direct) != 1 || direct[0].Message != "dependency org.example:util:1.0 is banned" {
+			t.Errorf("Expected util to be banned, but found: %v", direct)
+		}
+	})
+
+	t.Run("Should reject invalid patterns", func(t *testing.T) {
+		if _, err := enforcer.Enforce(p, enforcer.BannedDependencies{Excludes: []string{"a:b:[1.0:jar"}}); err == nil {
+			t.Errorf("Expected an error")
+		}
+	})
+
+	t.Run("Should need a graph", func(t *testing.T) {
+		_, err := enforcer.Enforce(&enforcer.Project{Model: p.Model}, enforcer.RequireReleaseDeps{})
+		if !errors.Is(err, enforcer.ErrNoGraph) {
+			t.Errorf("Expected ErrNoGraph, but found: %v", err)
+		}
+	})
+}
+
+func TestModelRules(t *testing.T) {
+	p := modelProject(t)
+
+	t.Run("Should report duplicate dependencies", func(t *testing.T) {
+		violations := enforce(t, p, enforcer.BanDuplicatePomDependencyVersions{})
+
+		if len(violations) != 1 || violations[0].Path != "/project/dependencies/dependency[org.example:core:jar:#2]" {
+			t.Errorf("Expected the second core dependency, but found: %v", violations)
+		}
+	})
+
+	t.Run("Should report snapshot parents", func(t *testing.T) {
+		p := graphProject(t, filepath.Join("..", "resolve", "testdata"))
+		p.Model = modelProject(t).Model
+
+		violations := enforce(t, p, enforcer.RequireReleaseDeps{})
+		if len(violations) != 1 || violations[0].Message != "parent org.example:parent:2.0-SNAPSHOT is a SNAPSHOT" {
+			t.Errorf("Expected the parent, but found: %v", violations)
+		}
+		if allowed := enforce(t, p, enforcer.RequireReleaseDeps{AllowSnapshotParent: true}); len(allowed) != 0 {
+			t.Errorf("Expected no violations, but found: %v", allowed)
+		}
+	})
+
+	t.Run("Should report unpinned plugins", func(t *testing.T) {
+		var messages []string
+		for _, v := range enforce(t, p, enforcer.RequirePluginVersions{}) {
+			messages = append(messages, v.Message)
+		}
+		expected := []string{
+			"plugin org.apache.maven.plugins:maven-surefire-plugin has no version",
+			"plugin org.codehaus.mojo:exec-maven-plugin has the version LATEST",
+			"plugin org.example:tool-maven-plugin has the SNAPSHOT version 0.1-SNAPSHOT",
+		}
+		if strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("Expected:\n%s\nbut found:\n%s", strings.Join(expected, "\n"), strings.Join(messages, "\n"))
+		}
+
+		if allowed := enforce(t, p, enforcer.RequirePluginVersions{AllowSnapshots: true, Unchecked: []string{"org.apache.maven.plugins:maven-surefire-plugin"}}); len(allowed) != 1 {
+			t.Errorf("Expected only the LATEST version, but found: %v", allowed)
+		}
+	})
+
+	t.Run("Should check the Maven version", func(t *testing.T) {
+		if v := enforce(t, p, enforcer.RequireMavenVersion{Version: "3.6"}); len(v) != 0 {
+			t.Errorf("Expected no violations, but found: %v", v)
+		}
+		v := enforce(t, p, enforcer.RequireMavenVersion{Version: "[3.9,4)"})
+		if len(v) != 1 || v[0].Path != "/project/prerequisites/maven" || v[0].Message != "Maven version 3.6.3 is not within [3.9,4)" {
+			t.Errorf("Expected the prerequisites to be reported, but found: %v", v)
+		}
+
+		build := &enforcer.Project{Model: p.Model, MavenVersion: "3.9.9"}
+		if v := enforce(t, build, enforcer.RequireMavenVersion{Version: "[3.9,4)"}); len(v) != 0 {
+			t.Errorf("Expected the version of the build to be checked, but found: %v", v)
+		}
+	})
+
+	t.Run("Should check the Java version", func(t *testing.T) {
+		v := enforce(t, p, enforcer.RequireJavaVersion{Version: "21"})
+		if len(v) != 1 || v[0].Path != "/project/properties/maven.compiler.release" {
+			t.Errorf("Expected the compiler release to be reported, but found: %v", v)
+		}
+
+		build := &enforcer.Project{Model: p.Model, JavaVersion: "1.8.0_402"}
+		if v := enforce(t, build, enforcer.RequireJavaVersion{Version: "[1.8,9)"}); len(v) != 0 {
+			t.Errorf("Expected Java 8 to be within range, but found: %v", v)
+		}
+	})
+}
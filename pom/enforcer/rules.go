@@ -0,0 +1,459 @@
+package enforcer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/obscurelyme/encoding/pom"
+	"github.com/obscurelyme/encoding/pom/resolve"
+)
+
+// DependencyConvergence reports dependencies requested with different
+// versions along different paths of the graph.
+type DependencyConvergence struct {
+	// Excludes are patterns, like those of BannedDependencies, of the
+	// dependencies not to check.
+	Excludes []string
+}
+
+func (DependencyConvergence) ID() string { return "dependencyConvergence" }
+
+func (r DependencyConvergence) Check(p *Project) ([]Violation, error) {
+	if p.Graph == nil {
+		return nil, ErrNoGraph
+	}
+	excludes, err := parsePatterns(r.Excludes)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []Violation
+	for _, nodes := range byKey(p.Graph) {
+		if matchesAny(excludes, &nodes[0].Dependency) {
+			continue
+		}
+		var versions []string
+		for _, n := range nodes {
+			if !contains(versions, n.Dependency.Version) {
+				versions = append(versions, n.Dependency.Version)
+			}
+		}
+		if len(versions) < 2 {
+			continue
+		}
+
+		v := Violation{Message: fmt.Sprintf("dependency %s does not converge: versions %s are requested", nodes[0].Dependency.ManagementKey(), strings.Join(versions, ", "))}
+		for _, n := range nodes {
+			v.Dependencies = append(v.Dependencies, pathTo(n))
+		}
+		violations = append(violations, v)
+	}
+	return violations, nil
+}
+
+// RequireUpperBoundDeps reports dependencies resolved to a version older
+// than one requested along another path of the graph.
+type RequireUpperBoundDeps struct {
+	// Excludes are patterns, like those of BannedDependencies, of the
+	// dependencies not to check.
+	Excludes []string
+}
+
+func (RequireUpperBoundDeps) ID() string { return "requireUpperBoundDeps" }
+
+func (r RequireUpperBoundDeps) Check(p *Project) ([]Violation, error) {
+	if p.Graph == nil {
+		return nil, ErrNoGraph
+	}
+	excludes, err := parsePatterns(r.Excludes)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []Violation
+	for _, nodes := range byKey(p.Graph) {
+		var resolved *resolve.Node
+		for _, n := range nodes {
+			if n.Omitted == "" {
+				resolved = n
+			}
+		}
+		if resolved == nil || matchesAny(excludes, &resolved.Dependency) {
+			continue
+		}
+
+		v := Violation{Dependencies: []DependencyPath{pathTo(resolved)}}
+		var newer []string
+		for _, n := range nodes {
+			if version := n.Dependency.Version; pom.CompareVersions(version, resolved.Dependency.Version) > 0 {
+				v.Dependencies = append(v.Dependencies, pathTo(n))
+				if !contains(newer, version) {
+					newer = append(newer, version)
+				}
+			}
+		}
+		if len(newer) == 0 {
+			continue
+		}
+		v.Message = fmt.Sprintf("dependency %s is resolved to %s, older than the requested %s", resolved.Dependency.ManagementKey(), resolved.Dependency.Version, strings.Join(newer, ", "))
+		violations = append(violations, v)
+	}
+	return violations, nil
+}
+
+// byKey returns the nodes of the graph below root by management key, in the
+// order of the first node of each key, leaving out the cycles.
+func byKey(root *resolve.Node) [][]*resolve.Node {
+	var keys []string
+	nodes := make(map[string][]*resolve.Node)
+	root.Walk(func(n *resolve.Node) {
+		if n == root || n.Omitted == resolve.OmittedForCycle {
+			return
+		}
+		key := n.Dependency.ManagementKey()
+		if nodes[key] == nil {
+			keys = append(keys, key)
+		}
+		nodes[key] = append(nodes[key], n)
+	})
+
+	grouped := make([][]*resolve.Node, len(keys))
+	for i, key := range keys {
+		grouped[i] = nodes[key]
+	}
+	return grouped
+}
+
+// duplicatePath matches the paths Walk gives to the elements that have the
+// key of a previous one of the same list, like dependency[g:a:jar:#2].
+var duplicatePath = regexp.MustCompile(`#\d+]$`)
+
+// BanDuplicatePomDependencyVersions reports dependencies declared more than
+// once in the same list of the POM as read, which Maven only warns about.
+type BanDuplicatePomDependencyVersions struct{}
+
+func (BanDuplicatePomDependencyVersions) ID() string { return "banDuplicatePomDependencyVersions" }
+
+func (BanDuplicatePomDependencyVersions) Check(p *Project) ([]Violation, error) {
+	var violations []Violation
+	p.Model.Walk(func(path string, element any) {
+		d, ok := element.(*pom.Dependency)
+		if !ok || !duplicatePath.MatchString(path) {
+			return
+		}
+		violations = append(violations, Violation{
+			Path:    path,
+			Message: fmt.Sprintf("dependency %s is declared more than once", d.ManagementKey()),
+		})
+	})
+	return violations, nil
+}
+
+// BannedDependencies reports the dependencies of the graph that match one
+// of Excludes but none of Includes.
+//
+// Patterns are of the form groupId[:artifactId[:version[:type[:scope[:classifier]]]]],
+// where each part may use * wildcards and the version may be a range, like
+// org.apache.logging.log4j:log4j-core:[2.0,2.17.1).
+type BannedDependencies struct {
+	Excludes []string
+	Includes []string
+	// DirectOnly restricts the rule to the dependencies the POM declares.
+	DirectOnly bool
+}
+
+func (BannedDependencies) ID() string { return "bannedDependencies" }
+
+func (r BannedDependencies) Check(p *Project) ([]Violation, error) {
+	if p.Graph == nil {
+		return nil, ErrNoGraph
+	}
+	excludes, err := parsePatterns(r.Excludes)
+	if err != nil {
+		return nil, err
+	}
+	includes, err := parsePatterns(r.Includes)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []Violation
+	p.Graph.Walk(func(n *resolve.Node) {
+		d := &n.Dependency
+		if n == p.Graph || n.Omitted != "" || r.DirectOnly && n.Parent() != p.Graph {
+			return
+		}
+		if matchesAny(excludes, d) && !matchesAny(includes, d) {
+			violations = append(violations, Violation{
+				Message:      fmt.Sprintf("dependency %s is banned", coordinates(d)),
+				Dependencies: []DependencyPath{pathTo(n)},
+			})
+		}
+	})
+	return violations, nil
+}
+
+// RequireReleaseDeps reports SNAPSHOT dependencies and parents.
+type RequireReleaseDeps struct {
+	// OnlyWhenRelease skips the check of SNAPSHOT projects.
+	OnlyWhenRelease bool
+	// AllowSnapshotParent allows a SNAPSHOT parent.
+	AllowSnapshotParent bool
+	// Excludes are patterns, like those of BannedDependencies, of the
+	// dependencies allowed to be SNAPSHOTs.
+	Excludes []string
+}
+
+func (RequireReleaseDeps) ID() string { return "requireReleaseDeps" }
+
+func (r RequireReleaseDeps) Check(p *Project) ([]Violation, error) {
+	if p.Graph == nil {
+		return nil, ErrNoGraph
+	}
+	if r.OnlyWhenRelease && pom.IsSnapshot(p.Graph.Dependency.Version) {
+		return nil, nil
+	}
+	excludes, err := parsePatterns(r.Excludes)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []Violation
+	if parent := p.Model.Parent; parent != nil && !r.AllowSnapshotParent && pom.IsSnapshot(parent.Version) {
+		violations = append(violations, Violation{
+			Path:    "/project/parent",
+			Message: fmt.Sprintf("parent %s:%s:%s is a SNAPSHOT", parent.GroupId, parent.ArtifactId, parent.Version),
+		})
+	}
+	p.Graph.Walk(func(n *resolve.Node) {
+		d := &n.Dependency
+		if n != p.Graph && n.Omitted == "" && pom.IsSnapshot(d.Version) && !matchesAny(excludes, d) {
+			violations = append(violations, Violation{
+				Message:      fmt.Sprintf("dependency %s is a SNAPSHOT", coordinates(d)),
+				Dependencies: []DependencyPath{pathTo(n)},
+			})
+		}
+	})
+	return violations, nil
+}
+
+// RequirePluginVersions reports the build and report plugins of the
+// effective model without a version, directly or from the plugin
+// management, or with a LATEST, RELEASE, unresolved or SNAPSHOT version.
+type RequirePluginVersions struct {
+	AllowSnapshots bool
+	// Unchecked are the groupId:artifactId keys of the plugins not to check.
+	Unchecked []string
+}
+
+func (RequirePluginVersions) ID() string { return "requirePluginVersions" }
+
+func (r RequirePluginVersions) Check(p *Project) ([]Violation, error) {
+	m := p.effective()
+	managed := make(map[string]string)
+	m.Walk(func(path string, element any) {
+		if plugin, ok := element.(*pom.Plugin); ok && strings.Contains(path, "/pluginManagement/") && plugin.Version != "" {
+			managed[plugin.Key()] = plugin.Version
+		}
+	})
+
+	var violations []Violation
+	check := func(path, key, version string) {
+		if strings.Contains(path, "/pluginManagement/") || contains(r.Unchecked, key) {
+			return
+		}
+		if version == "" {
+			version = managed[key]
+		}
+		var problem string
+		switch {
+		case version == "":
+			problem = "has no version"
+		case version == "LATEST" || version == "RELEASE":
+			problem = "has the version " + version
+		case strings.Contains(version, "${"):
+			problem = fmt.Sprintf("has the unresolved version %s", version)
+		case !r.AllowSnapshots && pom.IsSnapshot(version):
+			problem = fmt.Sprintf("has the SNAPSHOT version %s", version)
+		default:
+			return
+		}
+		violations = append(violations, Violation{Path: path, Message: fmt.Sprintf("plugin %s %s", key, problem)})
+	}
+	m.Walk(func(path string, element any) {
+		switch plugin := element.(type) {
+		case *pom.Plugin:
+			check(path, plugin.Key(), plugin.Version)
+		case *pom.ReportPlugin:
+			check(path, plugin.Key(), plugin.Version)
+		}
+	})
+	return violations, nil
+}
+
+// RequireMavenVersion reports a Maven version outside of Version, a range or
+// a minimum version. The version is the MavenVersion of the Project, or the
+// prerequisites of its effective model.
+type RequireMavenVersion struct {
+	Version string
+}
+
+func (RequireMavenVersion) ID() string { return "requireMavenVersion" }
+
+func (r RequireMavenVersion) Check(p *Project) ([]Violation, error) {
+	version, path := p.MavenVersion, ""
+	if m := p.effective(); version == "" && m.Prerequisites != nil {
+		version, path = m.Prerequisites.Maven, "/project/prerequisites/maven"
+	}
+	if version == "" {
+		return []Violation{{Path: "/project/prerequisites", Message: "the Maven version is not known, as the project has no prerequisites"}}, nil
+	}
+	return checkVersion("Maven", version, path, r.Version)
+}
+
+// RequireJavaVersion reports a Java version outside of Version, a range or a
+// minimum version. The version is the JavaVersion of the Project, or the
+// release, or else target, of the compiler in the effective model: the
+// maven.compiler properties or the configuration of the compiler plugin.
+type RequireJavaVersion struct {
+	Version string
+}
+
+func (RequireJavaVersion) ID() string { return "requireJavaVersion" }
+
+func (r RequireJavaVersion) Check(p *Project) ([]Violation, error) {
+	version, path := p.JavaVersion, ""
+	if version == "" {
+		version, path = compilerVersion(p.effective())
+	}
+	if version == "" {
+		return []Violation{{Path: "/project/properties", Message: "the Java version is not known, as the project does not configure the compiler release"}}, nil
+	}
+	// Java versions like 1.8.0_402 compare as 1.8.0-402.
+	return checkVersion("Java", strings.ReplaceAll(version, "_", "-"), path, r.Version)
+}
+
+// compilerVersion returns the Java version the compiler targets in m, and
+// its path.
+func compilerVersion(m *pom.Model) (string, string) {
+	for _, name := range []string{"release", "target"} {
+		if m.Properties != nil {
+			if v := m.Properties.Fields["maven.compiler."+name]; v != "" {
+				return v, "/project/properties/maven.compiler." + name
+			}
+		}
+
+		var version, at string
+		m.Walk(func(path string, element any) {
+			plugin, ok := element.(*pom.Plugin)
+			if !ok || version != "" || plugin.Key() != "org.apache.maven.plugins:maven-compiler-plugin" || plugin.Configuration == nil || strings.Contains(path, "/pluginManagement/") {
+				return
+			}
+			for _, c := range plugin.Configuration.Children {
+				if c.XMLName.Local == name {
+					version, at = strings.TrimSpace(c.Value), path+"/configuration/"+name
+				}
+			}
+		})
+		if version != "" {
+			return version, at
+		}
+	}
+	return "", ""
+}
+
+// checkVersion reports version when it is outside of spec.
+func checkVersion(name, version, path, spec string) ([]Violation, error) {
+	r, err := pom.ParseVersionRange(spec)
+	if err != nil {
+		return nil, err
+	}
+	if r.Recommended != "" {
+		// A version without brackets is a minimum, like in the enforcer.
+		r = &pom.VersionRange{Restrictions: []pom.Restriction{{Lower: r.Recommended, LowerInclusive: true}}}
+	}
+	if r.Contains(version) {
+		return nil, nil
+	}
+	return []Violation{{Path: path, Message: fmt.Sprintf("%s version %s is not within %s", name, version, spec)}}, nil
+}
+
+// pattern matches dependencies by
+// groupId:artifactId:version:type:scope:classifier, with missing trailing
+// parts matching everything.
+type pattern struct {
+	parts   []*regexp.Regexp
+	version *pom.VersionRange
+}
+
+func parsePatterns(specs []string) ([]pattern, error) {
+	patterns := make([]pattern, len(specs))
+	for i, spec := range specs {
+		parts := strings.Split(spec, ":")
+		if len(parts) > 6 || spec == "" {
+			return nil, fmt.Errorf("enforcer: invalid dependency pattern %q", spec)
+		}
+		for j, part := range parts {
+			if j == 2 && strings.ContainsAny(part, "[(") {
+				r, err := pom.ParseVersionRange(part)
+				if err != nil {
+					return nil, fmt.Errorf("enforcer: invalid dependency pattern %q: %w", spec, err)
+				}
+				patterns[i].version = r
+				patterns[i].parts = append(patterns[i].parts, nil)
+				continue
+			}
+			patterns[i].parts = append(patterns[i].parts, glob(part))
+		}
+	}
+	return patterns, nil
+}
+
+// glob returns a regular expression matching s, where * matches any text.
+func glob(s string) *regexp.Regexp {
+	quoted := strings.Split(s, "*")
+	for i, q := range quoted {
+		quoted[i] = regexp.QuoteMeta(q)
+	}
+	return regexp.MustCompile("^" + strings.Join(quoted, ".*") + "$")
+}
+
+func (p pattern) matches(d *pom.Dependency) bool {
+	values := []string{d.GroupId, d.ArtifactId, d.Version, orDefault(d.Type, "jar"), orDefault(d.Scope, pom.ScopeCompile), d.Classifier}
+	for i, part := range p.parts {
+		if part == nil {
+			if !p.version.Contains(d.Version) {
+				return false
+			}
+		} else if !part.MatchString(values[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesAny(patterns []pattern, d *pom.Dependency) bool {
+	for _, p := range patterns {
+		if p.matches(d) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
@@ -0,0 +1,111 @@
+package pom
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// SyntaxError is returned when a POM document is not well-formed XML, or
+// when an element value cannot be decoded into its field.
+type SyntaxError struct {
+	Line int
+	// Column is 0 when unknown, for errors of the XML decoder that only
+	// give their line.
+	Column int
+	Err    error
+}
+
+func (e *SyntaxError) Error() string {
+	msg := e.Err.Error()
+	if synErr, ok := e.Err.(*xml.SyntaxError); ok {
+		msg = synErr.Msg
+	}
+
+	if e.Column == 0 {
+		return fmt.Sprintf("pom: malformed XML at line %d: %s", e.Line, msg)
+	}
+	return fmt.Sprintf("pom: malformed XML at line %d, column %d: %s", e.Line, e.Column, msg)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// RootElementError is returned when the document element is not <project>.
+type RootElementError struct {
+	Name   xml.Name
+	Line   int
+	Column int
+}
+
+func (e *RootElementError) Error() string {
+	return fmt.Sprintf("pom: unexpected root element <%s> at line %d, column %d, expected <project>", e.Name.Local, e.Line, e.Column)
+}
+
+// ModelVersionError is returned when the <modelVersion> of a POM is not one
+// of the versions supported by this package.
+type ModelVersionError struct {
+	Version string
+	Line    int
+	Column  int
+}
+
+func (e *ModelVersionError) Error() string {
+	return fmt.Sprintf("pom: unsupported modelVersion %q at line %d, column %d", e.Version, e.Line, e.Column)
+}
+
+// EncodingError is returned when the XML declaration names a character
+// encoding that the decoder cannot read, or by Write for an encoding it
+// cannot write or a character of a comment the encoding cannot represent.
+type EncodingError struct {
+	Encoding string
+	// Rune is the character that cannot be encoded, 0 when the encoding is
+	// not supported.
+	Rune rune
+	// Line and Column are 0 when unknown, for the encodings given to Write.
+	Line   int
+	Column int
+}
+
+func (e *EncodingError) Error() string {
+	switch {
+	case e.Rune != 0:
+		return fmt.Sprintf("pom: cannot encode %q in %s at line %d, column %d", e.Rune, e.Encoding, e.Line, e.Column)
+	case e.Line == 0:
+		return fmt.Sprintf("pom: unsupported encoding %q", e.Encoding)
+	}
+	return fmt.Sprintf("pom: unsupported encoding %q at line %d, column %d", e.Encoding, e.Line, e.Column)
+}
+
+// UnrecognizedError is returned by a Strict Decoder when the document contains
+// elements or attributes that are not part of the POM schema.
+type UnrecognizedError struct {
+	Nodes []Unrecognized
+}
+
+func (e *UnrecognizedError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "pom: %d unrecognized element(s) or attribute(s)", len(e.Nodes))
+	for _, n := range e.Nodes {
+		fmt.Fprintf(&b, "\n\t%s at line %d, column %d", n.Path, n.Line, n.Column)
+	}
+	return b.String()
+}
+
+// UnresolvableModelError is returned when the POM of a parent or of an
+// imported bom cannot be found or read.
+type UnresolvableModelError struct {
+	GroupId    string
+	ArtifactId string
+	Version    string
+	Err        error
+}
+
+func (e *UnresolvableModelError) Error() string {
+	return fmt.Sprintf("pom: cannot resolve %s:%s:%s: %s", e.GroupId, e.ArtifactId, e.Version, e.Err)
+}
+
+func (e *UnresolvableModelError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,418 @@
+package pom
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DependencyOrder selects how Format sorts dependencies.
+type DependencyOrder int
+
+const (
+	// DependencyOrderNone keeps dependencies in document order.
+	DependencyOrderNone DependencyOrder = iota
+	// DependencyOrderCoordinates sorts dependencies by groupId, then
+	// artifactId.
+	DependencyOrderCoordinates
+	// DependencyOrderScope sorts dependencies by scope, from compile to
+	// import, then by groupId and artifactId.
+	DependencyOrderScope
+)
+
+// scopeOrder is the order of scopes for DependencyOrderScope. Unknown scopes
+// come last.
+var scopeOrder = []string{
+	ScopeCompile,
+	ScopeCompileOnly,
+	ScopeProvided,
+	ScopeRuntime,
+	ScopeSystem,
+	ScopeTest,
+	ScopeTestOnly,
+	ScopeTestRuntime,
+	ScopeImport,
+}
+
+// FormatOptions controls how Format rewrites a POM.
+type FormatOptions struct {
+	// WriteOptions control the indentation, declaration and schema header.
+	WriteOptions
+	// LineEnding separates lines, "\n" when empty.
+	LineEnding string
+	// SortDependencies sorts the dependencies of the project, its dependency
+	// management and its profiles.
+	SortDependencies DependencyOrder
+	// SortPlugins sorts build and report plugins by groupId, then artifactId.
+	SortPlugins bool
+	// SortProperties sorts properties by name.
+	SortProperties bool
+	// SortModules sorts modules and subprojects by path.
+	SortModules bool
+}
+
+// DefaultFormatOptions are used by Format when no options are given. They
+// only reorder the top level elements, like Write does.
+var DefaultFormatOptions = FormatOptions{
+	WriteOptions: DefaultWriteOptions,
+	LineEnding:   "\n",
+}
+
+// Format rewrites the POM document data in canonical form: the elements in
+// the order recommended by Maven, the lists sorted as configured in opts, and
+// consistent indentation and line endings. Comments are kept before the
+// element that follows them, which they move with, or at the end of the
+// element they close; those of documents that are not in UTF-8 cannot be
+// kept and are an error. A document is formatted when Format returns it
+// unchanged. A nil opts uses DefaultFormatOptions.
+func Format(data []byte, opts *FormatOptions) ([]byte, error) {
+	if opts == nil {
+		opts = &DefaultFormatOptions
+	}
+
+	var comments []anchoredComment
+	if doc, err := ParseDocument(data); err == nil {
+		comments, data = doc.stripComments()
+	} else if _, err := Read(bytes.NewReader(data)); err != nil {
+		return nil, err
+	} else if hasComments(data) {
+		return nil, errors.New("pom: cannot keep the comments of a document not in UTF-8")
+	}
+
+	m, err := Read(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	m.Sort(opts)
+
+	var buf bytes.Buffer
+	if err := m.Write(&buf, &opts.WriteOptions); err != nil {
+		return nil, err
+	}
+
+	out := buf.Bytes()
+	if len(comments) > 0 {
+		if encoding := orDefault(opts.Encoding, m.Encoding); !isUTF8(encoding) {
+			return nil, fmt.Errorf("pom: cannot keep the comments of a document in %s", encoding)
+		}
+		doc, err := ParseDocument(out)
+		if err != nil {
+			return nil, err
+		}
+		out = doc.insertComments(comments, opts.Indent)
+	}
+	if opts.LineEnding != "" && opts.LineEnding != "\n" {
+		out = bytes.ReplaceAll(out, []byte("\n"), []byte(opts.LineEnding))
+	}
+	return out, nil
+}
+
+// hasComments reports whether the document data, in any encoding Read
+// supports, has comments.
+func hasComments(data []byte) bool {
+	s := new(sniffer)
+	r, err := s.reader(bytes.NewReader(data))
+	if err != nil {
+		return false
+	}
+	d := xml.NewDecoder(r)
+	d.CharsetReader = s.charsetReader
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return false
+		}
+		if _, ok := tok.(xml.Comment); ok {
+			return true
+		}
+	}
+}
+
+// anchoredComment is a comment of a document with the element it belongs
+// to, located by the keys of the elements leading to it, which do not
+// change when Format sorts lists.
+type anchoredComment struct {
+	text string
+	path string
+	// end is set for comments at the end of the content of the element,
+	// after its last child, rather than before it.
+	end bool
+	// after is set for comments after the root element.
+	after bool
+}
+
+// stripComments returns the comments of d with the elements they belong
+// to, and the document without them.
+func (d *Document) stripComments() ([]anchoredComment, []byte) {
+	var comments []anchoredComment
+	var cuts [][2]int
+	add := func(from, to int, c anchoredComment) {
+		for _, r := range commentRanges(d.data[from:to]) {
+			c.text = string(d.data[from+r[0]+len("<!--") : from+r[1]-len("-->")])
+			comments = append(comments, c)
+			cuts = append(cuts, [2]int{from + r[0], from + r[1]})
+		}
+	}
+
+	add(0, d.root.start, anchoredComment{path: "project"})
+	var walk func(n *node, path string)
+	walk = func(n *node, path string) {
+		if n.empty {
+			return
+		}
+		keys := d.childKeys(n)
+		at := n.inner
+		for i, c := range n.children {
+			add(at, c.start, anchoredComment{path: path + "/" + keys[i]})
+			walk(c, path+"/"+keys[i])
+			at = c.end
+		}
+		add(at, n.innerEnd, anchoredComment{path: path, end: true})
+	}
+	walk(d.root, "project")
+	add(d.root.end, len(d.data), anchoredComment{path: "project", after: true})
+
+	if len(cuts) == 0 {
+		return nil, d.data
+	}
+	var stripped []byte
+	at := 0
+	for _, c := range cuts {
+		stripped = append(stripped, d.data[at:c[0]]...)
+		at = c[1]
+	}
+	return comments, append(stripped, d.data[at:]...)
+}
+
+// commentRanges returns the offsets of the comments in data, the content
+// between two tags, skipping CDATA sections and processing instructions.
+func commentRanges(data []byte) [][2]int {
+	var ranges [][2]int
+	for i := 0; i < len(data); {
+		open := bytes.IndexByte(data[i:], '<')
+		if open < 0 {
+			break
+		}
+		i += open
+		end := ""
+		switch rest := data[i:]; {
+		case bytes.HasPrefix(rest, []byte("<!--")):
+			end = "-->"
+		case bytes.HasPrefix(rest, []byte("<![CDATA[")):
+			end = "]]>"
+		case bytes.HasPrefix(rest, []byte("<?")):
+			end = "?>"
+		default:
+			i++
+			continue
+		}
+		close := bytes.Index(data[i:], []byte(end))
+		if close < 0 {
+			break
+		}
+		if end == "-->" {
+			ranges = append(ranges, [2]int{i, i + close + len(end)})
+		}
+		i += close + len(end)
+	}
+	return ranges
+}
+
+// childKeys returns the names of the children of n with a key identifying
+// them among the children of the same name: the key Diff gives to the
+// elements of lists, the text of the values of lists like modules, or the
+// position for the other elements.
+func (d *Document) childKeys(n *node) []string {
+	t := typeOf(n)
+	var names []string
+	indexes := make(map[string][]int)
+	for i, c := range n.children {
+		if indexes[c.name] == nil {
+			names = append(names, c.name)
+		}
+		indexes[c.name] = append(indexes[c.name], i)
+	}
+
+	keys := make([]string, len(n.children))
+	for _, name := range names {
+		group := indexes[name]
+		ct := childType(t, name)
+		switch {
+		case ct != nil && ct.Kind() == reflect.Struct:
+			list := reflect.MakeSlice(reflect.SliceOf(ct), len(group), len(group))
+			for j, i := range group {
+				c := n.children[i]
+				// an element that does not decode is keyed by its position
+				xml.Unmarshal(d.data[c.start:c.end], list.Index(j).Addr().Interface())
+			}
+			itemKeys, _ := diffItems(list)
+			for j, i := range group {
+				keys[i] = name + "[" + itemKeys[j] + "]"
+			}
+		case ct != nil && ct.Kind() == reflect.String:
+			seen := make(map[string]int)
+			for _, i := range group {
+				c := n.children[i]
+				text := strings.TrimSpace(string(d.data[c.inner:c.innerEnd]))
+				seen[text]++
+				keys[i] = fmt.Sprintf("%s[%s#%d]", name, text, seen[text])
+			}
+		default:
+			for j, i := range group {
+				keys[i] = fmt.Sprintf("%s[%d]", name, j)
+			}
+		}
+	}
+	return keys
+}
+
+// insertComments returns the document with comments added back where they
+// belong. The comments of elements d does not have are added at the end of
+// their closest ancestor. indent is the indentation of one level.
+func (d *Document) insertComments(comments []anchoredComment, indent string) []byte {
+	nodes := make(map[string]*node)
+	var walk func(n *node, path string)
+	walk = func(n *node, path string) {
+		nodes[path] = n
+		for i, key := range d.childKeys(n) {
+			walk(n.children[i], path+"/"+key)
+		}
+	}
+	walk(d.root, "project")
+
+	type insertion struct {
+		at   int
+		text string
+	}
+	var insertions []insertion
+	for _, c := range comments {
+		comment := "<!--" + c.text + "-->"
+		n := nodes[c.path]
+		for path := c.path; n == nil; n = nodes[path] {
+			path = path[:strings.LastIndexByte(path, '/')]
+			c.end = true
+		}
+
+		switch {
+		case c.after:
+			insertions = append(insertions, insertion{n.end, "\n" + comment})
+		case c.end && !n.empty && len(n.children) > 0:
+			if lineIndent, ok := d.lineIndent(n.innerEnd); ok {
+				at := n.innerEnd - len(lineIndent)
+				insertions = append(insertions, insertion{at, lineIndent + indent + comment + "\n"})
+			} else {
+				insertions = append(insertions, insertion{n.innerEnd, comment})
+			}
+		case c.end && !n.empty:
+			insertions = append(insertions, insertion{n.innerEnd, comment})
+		default:
+			if lineIndent, ok := d.lineIndent(n.start); ok {
+				at := n.start - len(lineIndent)
+				insertions = append(insertions, insertion{at, lineIndent + comment + "\n"})
+			} else {
+				insertions = append(insertions, insertion{n.start, comment})
+			}
+		}
+	}
+
+	sort.SliceStable(insertions, func(i, j int) bool { return insertions[i].at < insertions[j].at })
+	var out []byte
+	at := 0
+	for _, i := range insertions {
+		out = append(out, d.data[at:i.at]...)
+		out = append(out, i.text...)
+		at = i.at
+	}
+	return append(out, d.data[at:]...)
+}
+
+// Sort sorts the lists of m as configured in opts, including those in
+// profiles. A nil opts uses DefaultFormatOptions.
+func (m *Model) Sort(opts *FormatOptions) {
+	if opts == nil {
+		opts = &DefaultFormatOptions
+	}
+	sortLists(reflect.ValueOf(m), opts)
+}
+
+func sortLists(v reflect.Value, opts *FormatOptions) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return
+		}
+		sortList(v.Interface(), opts)
+		sortLists(v.Elem(), opts)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				sortLists(v.Field(i), opts)
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			sortLists(v.Index(i).Addr(), opts)
+		}
+	}
+}
+
+func sortList(list any, opts *FormatOptions) {
+	switch l := list.(type) {
+	case *Dependencies:
+		sortDependencies(l.Dependency, opts.SortDependencies)
+	case *Plugins:
+		if opts.SortPlugins {
+			sort.SliceStable(l.Plugin, func(i, j int) bool {
+				return l.Plugin[i].Key() < l.Plugin[j].Key()
+			})
+		}
+	case *ReportPlugins:
+		if opts.SortPlugins {
+			sort.SliceStable(l.Plugin, func(i, j int) bool {
+				return l.Plugin[i].Key() < l.Plugin[j].Key()
+			})
+		}
+	case *Properties:
+		if opts.SortProperties {
+			l.order = nil
+		}
+	case *Modules:
+		if opts.SortModules {
+			sort.Strings(l.Module)
+		}
+	case *Subprojects:
+		if opts.SortModules {
+			sort.Strings(l.Subproject)
+		}
+	}
+}
+
+func sortDependencies(deps []Dependency, order DependencyOrder) {
+	if order == DependencyOrderNone {
+		return
+	}
+
+	scope := func(d *Dependency) int {
+		for i, s := range scopeOrder {
+			if orDefault(d.Scope, ScopeCompile) == s {
+				return i
+			}
+		}
+		return len(scopeOrder)
+	}
+
+	sort.SliceStable(deps, func(i, j int) bool {
+		a, b := &deps[i], &deps[j]
+		if order == DependencyOrderScope && scope(a) != scope(b) {
+			return scope(a) < scope(b)
+		}
+		if a.GroupId != b.GroupId {
+			return a.GroupId < b.GroupId
+		}
+		return a.ArtifactId < b.ArtifactId
+	})
+}
@@ -0,0 +1,105 @@
+package pom_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+func readFormatFixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "format", name))
+	if err != nil {
+		t.Fatalf("Expected no errors reading %s, but found: %s", name, err.Error())
+	}
+	return data
+}
+
+func TestFormat(t *testing.T) {
+	sorted := pom.DefaultFormatOptions
+	sorted.SortDependencies = pom.DependencyOrderScope
+	sorted.SortPlugins = true
+	sorted.SortProperties = true
+	sorted.SortModules = true
+
+	t.Run("Should order elements and sort lists", func(t *testing.T) {
+		out, err := pom.Format(readFormatFixture(t, "pom.xml"), &sorted)
+		if err != nil {
+			t.Fatalf("Expected no errors formatting, but found: %s", err.Error())
+		}
+
+		if expected := readFormatFixture(t, "sorted.xml"); !bytes.Equal(out, expected) {
+			t.Errorf("Expected:\n%s\nbut found:\n%s", expected, out)
+		}
+	})
+
+	t.Run("Should leave a formatted document unchanged", func(t *testing.T) {
+		expected := readFormatFixture(t, "sorted.xml")
+
+		out, err := pom.Format(expected, &sorted)
+		if err != nil {
+			t.Fatalf("Expected no errors formatting, but found: %s", err.Error())
+		}
+		if !bytes.Equal(out, expected) {
+			t.Errorf("Expected the document to be unchanged, but found:\n%s", out)
+		}
+	})
+
+	t.Run("Should keep the comments with the elements they come before", func(t *testing.T) {
+		out, err := pom.Format(readFormatFixture(t, "commented.xml"), &sorted)
+		if err != nil {
+			t.Fatalf("Expected no errors formatting, but found: %s", err.Error())
+		}
+
+		expected := readFormatFixture(t, "commented-sorted.xml")
+		if !bytes.Equal(out, expected) {
+			t.Errorf("Expected:\n%s\nbut found:\n%s", expected, out)
+		}
+		if again, err := pom.Format(expected, &sorted); err != nil || !bytes.Equal(again, expected) {
+			t.Errorf("Expected the document to be unchanged, but found:\n%s %v", again, err)
+		}
+	})
+
+	t.Run("Should refuse to drop the comments of documents not in UTF-8", func(t *testing.T) {
+		data := []byte("<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>\n<project>\n\t<!-- kept -->\n\t<modelVersion>4.0.0</modelVersion>\n</project>\n")
+		if _, err := pom.Format(data, nil); err == nil {
+			t.Errorf("Expected an error")
+		}
+		if _, err := pom.Format(bytes.Replace(data, []byte("\t<!-- kept -->\n"), nil, 1), nil); err != nil {
+			t.Errorf("Expected no errors without comments, but found: %s", err.Error())
+		}
+	})
+
+	t.Run("Should keep the order of lists by default", func(t *testing.T) {
+		out, err := pom.Format(readFormatFixture(t, "pom.xml"), nil)
+		if err != nil {
+			t.Fatalf("Expected no errors formatting, but found: %s", err.Error())
+		}
+
+		s := string(out)
+		if strings.Index(s, "<modelVersion>") > strings.Index(s, "<dependencies>") {
+			t.Errorf("Expected modelVersion before dependencies")
+		}
+		if strings.Index(s, "junit-jupiter") > strings.Index(s, "<artifactId>guava") || strings.Index(s, "<module>web") > strings.Index(s, "<module>core") {
+			t.Errorf("Expected the lists in document order, but found:\n%s", s)
+		}
+	})
+
+	t.Run("Should use the line ending", func(t *testing.T) {
+		opts := pom.DefaultFormatOptions
+		opts.LineEnding = "\r\n"
+
+		out, err := pom.Format(readFormatFixture(t, "sorted.xml"), &opts)
+		if err != nil {
+			t.Fatalf("Expected no errors formatting, but found: %s", err.Error())
+		}
+		if bytes.Count(out, []byte("\r\n")) != bytes.Count(out, []byte("\n")) {
+			t.Errorf("Expected only CRLF line endings, but found: %q", out)
+		}
+	})
+}
@@ -0,0 +1,431 @@
+// Package index maps the classes and packages of the jars of a local Maven
+// repository to the artifacts that provide them.
+//
+// An Index is built by Update, which only reads the jars added or changed
+// since the previous update, and persists as JSON with Save and Load:
+//
+//	ix, err := index.Load(path)
+//	if errors.Is(err, fs.ErrNotExist) {
+//		ix = index.New(repository)
+//	}
+//	stats, err := ix.Update()
+//	...
+//	err = ix.Save(path)
+package index
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/obscurelyme/encoding/jar"
+	"github.com/obscurelyme/encoding/pom"
+	"github.com/obscurelyme/encoding/pom/resolve"
+	"github.com/obscurelyme/encoding/properties"
+)
+
+// Artifact identifies a file of a repository.
+type Artifact struct {
+	GroupId    string `json:"groupId"`
+	ArtifactId string `json:"artifactId"`
+	Version    string `json:"version"`
+	Classifier string `json:"classifier,omitempty"`
+	Extension  string `json:"extension,omitempty"`
+}
+
+// String returns the groupId:artifactId:extension[:classifier]:version
+// coordinates of a.
+func (a Artifact) String() string {
+	s := a.GroupId + ":" + a.ArtifactId + ":" + orDefault(a.Extension, "jar")
+	if a.Classifier != "" {
+		s += ":" + a.Classifier
+	}
+	return s + ":" + a.Version
+}
+
+// File is an indexed jar.
+type File struct {
+	// Path is the path of the jar in the repository, with forward slashes.
+	Path string `json:"path"`
+	// Size and ModTime are those of the jar when it was indexed.
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+	// Artifact is the artifact of the jar given by its location in the
+	// repository, or else by its only pom.properties.
+	Artifact Artifact `json:"artifact"`
+	// Embedded are the artifacts of the other pom.properties of the jar,
+	// whose classes a shaded jar holds.
+	Embedded []Artifact `json:"embedded,omitempty"`
+	// Classes are the binary names of the classes of the jar, sorted.
+	Classes []string `json:"classes"`
+}
+
+// Index maps classes and packages to the jars of a repository.
+type Index struct {
+	// Dir is the directory of the repository.
+	Dir string
+	// Errors are those of the jars and directories the last Update could
+	// not read, like empty or partly downloaded jars, which it skipped.
+	Errors []error
+	files  map[string]*File
+
+	// classes and packages are the paths of the files with each class and
+	// package, built on first use.
+	classes  map[string][]string
+	packages map[string][]string
+}
+
+// New returns an empty index of repository.
+func New(repository pom.LocalRepository) *Index {
+	return &Index{Dir: repository.Dir, files: make(map[string]*File)}
+}
+
+// indexFile is the persisted form of an Index.
+type indexFile struct {
+	Dir   string  `json:"dir"`
+	Files []*File `json:"files"`
+}
+
+// Load reads an index saved by Save.
+func Load(path string) (*Index, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var f indexFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("index: %s: %w", path, err)
+	}
+	ix := &Index{Dir: f.Dir, files: make(map[string]*File, len(f.Files))}
+	for _, file := range f.Files {
+		ix.files[file.Path] = file
+	}
+	return ix, nil
+}
+
+// Save writes ix to path.
+func (ix *Index) Save(path string) error {
+	data, err := json.Marshal(&indexFile{Dir: ix.Dir, Files: ix.Files()})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Files returns the indexed jars, sorted by path.
+func (ix *Index) Files() []*File {
+	files := make([]*File, 0, len(ix.files))
+	for _, f := range ix.files {
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files
+}
+
+// Stats counts the jars of an Update.
+type Stats struct {
+	// Indexed are the jars read, new or changed since the last update.
+	Indexed int
+	// Unchanged are the jars already indexed with the same size and
+	// modification time.
+	Unchanged int
+	// Removed are the jars indexed before that are gone.
+	Removed int
+	// Failed are the jars and directories that could not be read, with
+	// their errors in the Errors of the index.
+	Failed int
+}
+
+// Update indexes the jars of the repository that are new or changed since
+// the last update, and forgets those that are gone. Jars that cannot be read
+// are skipped, and forgotten if they were indexed before; directories that
+// cannot be read are skipped, and the jars indexed below them kept. Both are
+// counted as Failed and their errors kept in Errors. Only a repository that
+// cannot be read is an error.
+func (ix *Index) Update() (Stats, error) {
+	var stats Stats
+	ix.Errors = nil
+	seen := make(map[string]bool)
+	err := filepath.WalkDir(ix.Dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == ix.Dir {
+				return err
+			}
+			// keep what was indexed below a directory that cannot be read
+			rel, _ := filepath.Rel(ix.Dir, p)
+			rel = filepath.ToSlash(rel)
+			for f := range ix.files {
+				if f == rel || strings.HasPrefix(f, rel+"/") {
+					seen[f] = true
+				}
+			}
+			ix.Errors = append(ix.Errors, err)
+			stats.Failed++
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !strings.HasSuffix(p, ".jar") {
+			return nil
+		}
+		rel, err := filepath.Rel(ix.Dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		seen[rel] = true
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if f := ix.files[rel]; f != nil && f.Size == info.Size() && f.ModTime.Equal(info.ModTime()) {
+			stats.Unchanged++
+			return nil
+		}
+
+		f, err := readFile(p, rel, info)
+		if err != nil {
+			delete(ix.files, rel)
+			ix.Errors = append(ix.Errors, err)
+			stats.Failed++
+			return nil
+		}
+		ix.files[rel] = f
+		stats.Indexed++
+		return nil
+	})
+	if err != nil {
+		return stats, fmt.Errorf("index: %w", err)
+	}
+
+	for rel := range ix.files {
+		if !seen[rel] {
+			delete(ix.files, rel)
+			stats.Removed++
+		}
+	}
+	ix.classes, ix.packages = nil, nil
+	return stats, nil
+}
+
+// readFile indexes the jar at p, rel in the repository.
+func readFile(p, rel string, info fs.FileInfo) (*File, error) {
+	r, err := zip.OpenReader(p)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", p, err)
+	}
+	defer r.Close()
+
+	f := &File{Path: rel, Size: info.Size(), ModTime: info.ModTime()}
+	if f.Classes, err = jar.Classes(r); err != nil {
+		return nil, fmt.Errorf("%s: %w", p, err)
+	}
+
+	var embedded []Artifact
+	for _, entry := range r.File {
+		if dir, name := path.Split(entry.Name); name != "pom.properties" || !strings.HasPrefix(dir, "META-INF/maven/") {
+			continue
+		}
+		a, err := readPomProperties(entry)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", p, entry.Name, err)
+		}
+		embedded = append(embedded, a)
+	}
+
+	a, ok := layoutArtifact(rel)
+	if !ok && len(embedded) == 1 {
+		a, ok = embedded[0], true
+	}
+	if ok {
+		f.Artifact = a
+	}
+	for _, e := range embedded {
+		if e.GroupId != a.GroupId || e.ArtifactId != a.ArtifactId {
+			f.Embedded = append(f.Embedded, e)
+		}
+	}
+	return f, nil
+}
+
+// readPomProperties reads the artifact of a pom.properties entry written by
+// the Maven archiver.
+func readPomProperties(entry *zip.File) (Artifact, error) {
+	rc, err := entry.Open()
+	if err != nil {
+		return Artifact{}, err
+	}
+	defer rc.Close()
+
+	p, err := properties.Read(rc)
+	if err != nil {
+		return Artifact{}, err
+	}
+	props := p.Map()
+	return Artifact{GroupId: props["groupId"], ArtifactId: props["artifactId"], Version: props["version"], Extension: "jar"}, nil
+}
+
+// layoutArtifact returns the artifact of the file at rel in a repository,
+// like org/slf4j/slf4j-api/2.0.9/slf4j-api-2.0.9.jar, if rel follows the
+// layout of repositories.
+func layoutArtifact(rel string) (Artifact, bool) {
+	parts := strings.Split(rel, "/")
+	if len(parts) < 4 {
+		return Artifact{}, false
+	}
+	n := len(parts)
+	a := Artifact{
+		GroupId:    strings.Join(parts[:n-3], "."),
+		ArtifactId: parts[n-3],
+		Version:    parts[n-2],
+	}
+
+	rest, ok := strings.CutPrefix(parts[n-1], a.ArtifactId+"-"+a.Version)
+	if !ok {
+		return Artifact{}, false
+	}
+	rest, a.Extension, _ = cutLast(rest, ".")
+	if rest != "" {
+		if a.Classifier, ok = strings.CutPrefix(rest, "-"); !ok {
+			return Artifact{}, false
+		}
+	}
+	return a, true
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+func (ix *Index) lookups() {
+	if ix.classes != nil {
+		return
+	}
+	ix.classes = make(map[string][]string)
+	ix.packages = make(map[string][]string)
+	for _, f := range ix.Files() {
+		// the classes of a package are not contiguous in sorted order, as
+		// those of its subpackages and package-info may come between them
+		seen := make(map[string]bool)
+		for _, class := range f.Classes {
+			ix.classes[class] = append(ix.classes[class], f.Path)
+			if pkg := packageOf(class); !seen[pkg] {
+				ix.packages[pkg] = append(ix.packages[pkg], f.Path)
+				seen[pkg] = true
+			}
+		}
+	}
+}
+
+// packageOf returns the package of the class with binary name class.
+func packageOf(class string) string {
+	if i := strings.LastIndexByte(class, '.'); i >= 0 {
+		return class[:i]
+	}
+	return ""
+}
+
+// Class returns the jars with the class named class, a binary name like
+// org.slf4j.Logger or java.util.Map$Entry, sorted by path.
+func (ix *Index) Class(class string) []*File {
+	ix.lookups()
+	return ix.filesAt(ix.classes[class])
+}
+
+// Package returns the jars with classes in the package named pkg, sorted by
+// path. Split packages are provided by more than one artifact.
+func (ix *Index) Package(pkg string) []*File {
+	ix.lookups()
+	return ix.filesAt(ix.packages[pkg])
+}
+
+func (ix *Index) filesAt(paths []string) []*File {
+	files := make([]*File, len(paths))
+	for i, p := range paths {
+		files[i] = ix.files[p]
+	}
+	return files
+}
+
+// Duplicate is a class found in more than one entry of a classpath.
+type Duplicate struct {
+	Class string
+	// Entries are the entries with the class, in classpath order: the class
+	// is loaded from the first one.
+	Entries []resolve.Entry
+}
+
+// Duplicates returns the classes in more than one entry of cp, sorted by
+// name. The classes of the jars of the repository are taken from ix, those
+// of other entries, like output directories, are read.
+func (ix *Index) Duplicates(cp resolve.Classpath) ([]Duplicate, error) {
+	entries := make(map[string][]resolve.Entry)
+	for _, e := range cp {
+		classes, err := ix.entryClasses(e.Path)
+		if err != nil {
+			return nil, err
+		}
+		for _, class := range classes {
+			entries[class] = append(entries[class], e)
+		}
+	}
+
+	var duplicates []Duplicate
+	for class, found := range entries {
+		if len(found) > 1 {
+			duplicates = append(duplicates, Duplicate{Class: class, Entries: found})
+		}
+	}
+	sort.Slice(duplicates, func(i, j int) bool { return duplicates[i].Class < duplicates[j].Class })
+	return duplicates, nil
+}
+
+func (ix *Index) entryClasses(p string) ([]string, error) {
+	if rel, err := filepath.Rel(ix.Dir, p); err == nil {
+		if f := ix.files[filepath.ToSlash(rel)]; f != nil {
+			return f.Classes, nil
+		}
+	}
+
+	info, err := os.Stat(p)
+	switch {
+	case os.IsNotExist(err):
+		// Like the JVM, skip missing entries, such as output directories
+		// not compiled yet.
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("index: %w", err)
+	case info.IsDir():
+		return jar.Classes(os.DirFS(p))
+	}
+
+	r, err := zip.OpenReader(p)
+	if err != nil {
+		return nil, fmt.Errorf("index: %s: %w", p, err)
+	}
+	defer r.Close()
+	return jar.Classes(r)
+}
+
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
@@ -0,0 +1,257 @@
+package index_test
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/obscurelyme/encoding/pom"
+	"github.com/obscurelyme/encoding/pom/index"
+	"github.com/obscurelyme/encoding/pom/resolve"
+)
+
+// writeJar writes a jar with the named entries to dir/rel. Classes are empty,
+// as the index does not read them.
+func writeJar(t *testing.T, dir, rel string, entries map[string]string) string {
+	t.Helper()
+
+	p := filepath.Join(dir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for name, content := range entries {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func pomProperties(groupId, artifactId, version string) string {
+	return "#Created by Apache Maven\ngroupId=" + groupId + "\nartifactId=" + artifactId + "\nversion=" + version + "\n"
+}
+
+func writeRepository(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	writeJar(t, dir, "org/slf4j/slf4j-api/2.0.9/slf4j-api-2.0.9.jar", map[string]string{
+		"org/slf4j/Logger.class":                                  "",
+		"org/slf4j/LoggerFactory.class":                           "",
+		"META-INF/maven/org.slf4j/slf4j-api/pom.properties":       pomProperties("org.slf4j", "slf4j-api", "2.0.9"),
+		"META-INF/versions/9/module-info.class":                   "",
+		"org/slf4j/helpers/NOPLogger.class":                       "",
+		"META-INF/maven/org.slf4j/slf4j-api/pom.xml":              "<project/>",
+		"org/slf4j/messages.properties":                           "",
+		"META-INF/versions/9/org/slf4j/LoggerFactory.class":       "",
+		"META-INF/maven/org.slf4j/slf4j-api/unrelated.properties": "",
+	})
+	writeJar(t, dir, "org/example/shaded/1.0/shaded-1.0-all.jar", map[string]string{
+		"org/slf4j/Logger.class":                            "",
+		"org/example/shaded/Main.class":                     "",
+		"META-INF/maven/org.example/shaded/pom.properties":  pomProperties("org.example", "shaded", "1.0"),
+		"META-INF/maven/org.slf4j/slf4j-api/pom.properties": pomProperties("org.slf4j", "slf4j-api", "2.0.9"),
+	})
+	writeJar(t, dir, "odd/place/lib.jar", map[string]string{
+		"org/other/Lib.class":                         "",
+		"org/other/model/Item.class":                  "",
+		"org/other/package-info.class":                "",
+		"META-INF/maven/org.other/lib/pom.properties": pomProperties("org.other", "lib", "3"),
+	})
+	return dir
+}
+
+func paths(files []*index.File) string {
+	var p []string
+	for _, f := range files {
+		p = append(p, f.Path)
+	}
+	return strings.Join(p, " ")
+}
+
+func TestIndex(t *testing.T) {
+	dir := writeRepository(t)
+	ix := index.New(pom.LocalRepository{Dir: dir})
+	stats, err := ix.Update()
+	if err != nil {
+		t.Fatalf("Expected no errors indexing the repository, but found: %s", err.Error())
+	}
+	if stats != (index.Stats{Indexed: 3}) {
+		t.Errorf("Expected 3 jars indexed, but found: %+v", stats)
+	}
+
+	t.Run("Should find the artifacts of classes and packages", func(t *testing.T) {
+		expected := "org/example/shaded/1.0/shaded-1.0-all.jar org/slf4j/slf4j-api/2.0.9/slf4j-api-2.0.9.jar"
+		if found := paths(ix.Class("org.slf4j.Logger")); found != expected {
+			t.Errorf("Expected %s, but found: %s", expected, found)
+		}
+		if found := paths(ix.Package("org.slf4j")); found != expected {
+			t.Errorf("Expected %s, but found: %s", expected, found)
+		}
+		if found := paths(ix.Package("org.slf4j.helpers")); found != "org/slf4j/slf4j-api/2.0.9/slf4j-api-2.0.9.jar" {
+			t.Errorf("Expected slf4j-api, but found: %s", found)
+		}
+		if found := paths(ix.Package("org.other")); found != "odd/place/lib.jar" {
+			t.Errorf("Expected lib once, but found: %s", found)
+		}
+		if found := ix.Class("org.slf4j.Missing"); len(found) != 0 {
+			t.Errorf("Expected no artifacts, but found: %s", paths(found))
+		}
+	})
+
+	t.Run("Should identify artifacts", func(t *testing.T) {
+		for _, f := range ix.Files() {
+			var expected string
+			switch f.Path {
+			case "org/slf4j/slf4j-api/2.0.9/slf4j-api-2.0.9.jar":
+				expected = "org.slf4j:slf4j-api:jar:2.0.9"
+				if strings.Join(f.Classes, " ") != "org.slf4j.Logger org.slf4j.LoggerFactory org.slf4j.helpers.NOPLogger" {
+					t.Errorf("Expected the classes of slf4j-api, but found: %v", f.Classes)
+				}
+			case "org/example/shaded/1.0/shaded-1.0-all.jar":
+				expected = "org.example:shaded:jar:all:1.0"
+				if len(f.Embedded) != 1 || f.Embedded[0].String() != "org.slf4j:slf4j-api:jar:2.0.9" {
+					t.Errorf("Expected slf4j-api to be embedded, but found: %v", f.Embedded)
+				}
+			case "odd/place/lib.jar":
+				expected = "org.other:lib:jar:3"
+			}
+			if f.Artifact.String() != expected {
+				t.Errorf("Expected %s for %s, but found: %s", expected, f.Path, f.Artifact)
+			}
+		}
+	})
+
+	t.Run("Should persist and update incrementally", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "index.json")
+		if err := ix.Save(path); err != nil {
+			t.Fatalf("Expected no errors saving the index, but found: %s", err.Error())
+		}
+		loaded, err := index.Load(path)
+		if err != nil {
+			t.Fatalf("Expected no errors loading the index, but found: %s", err.Error())
+		}
+		if found := paths(loaded.Class("org.other.Lib")); found != "odd/place/lib.jar" {
+			t.Errorf("Expected the loaded index to find Lib, but found: %s", found)
+		}
+
+		p := writeJar(t, dir, "odd/place/lib.jar", map[string]string{"org/other/Lib2.class": ""})
+		later := time.Now().Add(time.Hour)
+		os.Chtimes(p, later, later)
+		os.Remove(filepath.Join(dir, "org", "example", "shaded", "1.0", "shaded-1.0-all.jar"))
+
+		stats, err := loaded.Update()
+		if err != nil || stats != (index.Stats{Indexed: 1, Unchanged: 1, Removed: 1}) {
+			t.Errorf("Expected 1 jar indexed, 1 unchanged and 1 removed, but found: %+v %v", stats, err)
+		}
+		if found := paths(loaded.Class("org.other.Lib2")); found != "odd/place/lib.jar" {
+			t.Errorf("Expected the changed jar to be indexed again, but found: %s", found)
+		}
+		if found := paths(loaded.Class("org.slf4j.Logger")); found != "org/slf4j/slf4j-api/2.0.9/slf4j-api-2.0.9.jar" {
+			t.Errorf("Expected the removed jar to be forgotten, but found: %s", found)
+		}
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	t.Run("Should skip the jars that cannot be read", func(t *testing.T) {
+		dir := writeRepository(t)
+		ix := index.New(pom.LocalRepository{Dir: dir})
+		if _, err := ix.Update(); err != nil {
+			t.Fatalf("Expected no errors indexing the repository, but found: %s", err.Error())
+		}
+
+		os.MkdirAll(filepath.Join(dir, "org", "example", "broken", "1.0"), 0o755)
+		os.WriteFile(filepath.Join(dir, "org", "example", "broken", "1.0", "broken-1.0.jar"), nil, 0o644)
+		p := filepath.Join(dir, "odd", "place", "lib.jar")
+		os.WriteFile(p, []byte("PK\x03\x04 partial download"), 0o644)
+		later := time.Now().Add(time.Hour)
+		os.Chtimes(p, later, later)
+
+		stats, err := ix.Update()
+		if err != nil || stats != (index.Stats{Unchanged: 2, Failed: 2}) || len(ix.Errors) != 2 {
+			t.Errorf("Expected 2 jars unchanged and 2 failed, but found: %+v %v %v", stats, ix.Errors, err)
+		}
+		if found := paths(ix.Class("org.other.Lib")); found != "" {
+			t.Errorf("Expected the corrupt jar to be forgotten, but found: %s", found)
+		}
+		if found := paths(ix.Class("org.slf4j.LoggerFactory")); found != "org/slf4j/slf4j-api/2.0.9/slf4j-api-2.0.9.jar" {
+			t.Errorf("Expected the other jars to stay indexed, but found: %s", found)
+		}
+	})
+}
+
+func TestUpdateUnreadable(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("directories are always readable by root")
+	}
+
+	t.Run("Should skip the directories that cannot be read", func(t *testing.T) {
+		dir := writeRepository(t)
+		ix := index.New(pom.LocalRepository{Dir: dir})
+		if _, err := ix.Update(); err != nil {
+			t.Fatalf("Expected no errors indexing the repository, but found: %s", err.Error())
+		}
+
+		locked := filepath.Join(dir, "odd")
+		if err := os.Chmod(locked, 0o000); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Chmod(locked, 0o755)
+
+		stats, err := ix.Update()
+		if err != nil || stats != (index.Stats{Unchanged: 2, Failed: 1}) || len(ix.Errors) != 1 {
+			t.Errorf("Expected 2 jars unchanged and 1 failed, but found: %+v %v %v", stats, ix.Errors, err)
+		}
+		if found := paths(ix.Class("org.other.Lib")); found != "odd/place/lib.jar" {
+			t.Errorf("Expected the jar below the directory to stay indexed, but found: %s", found)
+		}
+	})
+}
+
+func TestDuplicates(t *testing.T) {
+	dir := writeRepository(t)
+	ix := index.New(pom.LocalRepository{Dir: dir})
+	if _, err := ix.Update(); err != nil {
+		t.Fatalf("Expected no errors indexing the repository, but found: %s", err.Error())
+	}
+
+	classes := filepath.Join(t.TempDir(), "classes")
+	os.MkdirAll(filepath.Join(classes, "org", "example", "shaded"), 0o755)
+	os.WriteFile(filepath.Join(classes, "org", "example", "shaded", "Main.class"), nil, 0o644)
+
+	cp := resolve.Classpath{
+		{Path: classes},
+		{Path: filepath.Join(dir, "org", "slf4j", "slf4j-api", "2.0.9", "slf4j-api-2.0.9.jar")},
+		{Path: filepath.Join(dir, "org", "example", "shaded", "1.0", "shaded-1.0-all.jar")},
+		{Path: filepath.Join(dir, "missing.jar")},
+	}
+	duplicates, err := ix.Duplicates(cp)
+	if err != nil {
+		t.Fatalf("Expected no errors, but found: %s", err.Error())
+	}
+
+	var found []string
+	for _, d := range duplicates {
+		found = append(found, d.Class+":"+filepath.Base(d.Entries[0].Path)+","+filepath.Base(d.Entries[1].Path))
+	}
+	expected := "org.example.shaded.Main:classes,shaded-1.0-all.jar org.slf4j.Logger:slf4j-api-2.0.9.jar,shaded-1.0-all.jar"
+	if strings.Join(found, " ") != expected {
+		t.Errorf("Expected %s, but found: %s", expected, strings.Join(found, " "))
+	}
+}
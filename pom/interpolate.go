@@ -0,0 +1,196 @@
+package pom
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+)
+
+// Interpolate replaces the ${...} expressions in every value of m, including
+// attributes, properties and plugin configuration. An expression names one of
+// props, like a -D option on the command line, an element of m prefixed with
+// project., such as ${project.version} or ${project.parent.groupId}, or one of
+// m's own properties, in that order of precedence. Expressions that cannot be
+// resolved are left as they are.
+func (m *Model) Interpolate(props map[string]string) {
+	interpolateModel(m, m.Clone(), props)
+}
+
+// Evaluate returns the value of expression, the name within ${...}, as
+// Interpolate would replace it, and whether it can be resolved.
+func (m *Model) Evaluate(expression string, props map[string]string) (string, bool) {
+	return newInterpolator(modelLookup(m, props)).value(expression)
+}
+
+// interpolateModel interpolates target, resolving project expressions and
+// properties against source so that values being replaced do not affect each
+// other.
+func interpolateModel(target, source *Model, props map[string]string) {
+	in := newInterpolator(modelLookup(source, props))
+	eachString(reflect.ValueOf(target), in.expand)
+}
+
+// modelLookup returns the value of an expression as described by Interpolate.
+func modelLookup(m *Model, props map[string]string) func(name string) (string, bool) {
+	return func(name string) (string, bool) {
+		if value, ok := props[name]; ok {
+			return value, true
+		}
+		if path, ok := strings.CutPrefix(name, "project."); ok {
+			return modelValue(reflect.ValueOf(m), strings.Split(path, "."))
+		}
+		if m.Properties != nil {
+			if value, ok := m.Properties.Fields[name]; ok {
+				return value, true
+			}
+		}
+		return "", false
+	}
+}
+
+// modelValue returns the string found by following the xml element names of
+// path from v.
+func modelValue(v reflect.Value, path []string) (string, bool) {
+	for _, name := range path {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return "", false
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return "", false
+		}
+
+		field, ok := fieldByElementName(v, name)
+		if !ok {
+			return "", false
+		}
+		v = field
+	}
+
+	if v.Kind() != reflect.String || v.String() == "" {
+		return "", false
+	}
+	return v.String(), true
+}
+
+func fieldByElementName(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, hasTag := f.Tag.Lookup("xml")
+		if f.Anonymous && !hasTag {
+			if field, ok := fieldByElementName(reflect.Indirect(v.Field(i)), name); ok {
+				return field, true
+			}
+			continue
+		}
+		if tagName, opts, _ := strings.Cut(tag, ","); f.IsExported() && tagName == name && !hasOption(opts, "attr") {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+type interpolator struct {
+	lookup func(name string) (string, bool)
+	// resolving holds the expressions being expanded, to stop at cycles.
+	resolving map[string]bool
+}
+
+func newInterpolator(lookup func(name string) (string, bool)) *interpolator {
+	return &interpolator{lookup: lookup, resolving: make(map[string]bool)}
+}
+
+func (in *interpolator) expand(s string) string {
+	if !strings.Contains(s, "${") {
+		return s
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+
+		b.WriteString(s[:start])
+		if value, ok := in.value(s[start+2 : end]); ok {
+			b.WriteString(value)
+		} else {
+			b.WriteString(s[start : end+1])
+		}
+		s = s[end+1:]
+	}
+	b.WriteString(s)
+
+	return b.String()
+}
+
+func (in *interpolator) value(name string) (string, bool) {
+	if in.resolving[name] {
+		return "", false
+	}
+	value, ok := in.lookup(name)
+	if !ok {
+		return "", false
+	}
+
+	in.resolving[name] = true
+	value = in.expand(value)
+	delete(in.resolving, name)
+
+	return value, true
+}
+
+var (
+	xmlNameType = reflect.TypeOf(xml.Name{})
+	domType     = reflect.TypeOf(DOM{})
+)
+
+// eachString replaces every value held by v, a pointer to a Model or one of
+// its elements, with the result of fn. Comments, element and attribute names
+// and fields that are not part of the document are left alone.
+func eachString(v reflect.Value, fn func(string) string) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return
+		}
+		if p, ok := v.Interface().(*Properties); ok {
+			for key, value := range p.Fields {
+				p.Fields[key] = fn(value)
+			}
+			return
+		}
+		eachString(v.Elem(), fn)
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			eachString(v.Index(i), fn)
+		}
+	case reflect.String:
+		v.SetString(fn(v.String()))
+	case reflect.Struct:
+		if v.Type() == xmlNameType {
+			return
+		}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			tag := f.Tag.Get("xml")
+			if tag == ",comment" || tag == "-" && t != domType {
+				continue
+			}
+			eachString(v.Field(i), fn)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package pom
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Lifecycle is a lifecycle of Maven: its phases, in the order they run.
+type Lifecycle struct {
+	Id     string
+	Phases []string
+}
+
+// Lifecycles are the lifecycles of Maven.
+var Lifecycles = []Lifecycle{
+	{Id: "clean", Phases: []string{"pre-clean", "clean", "post-clean"}},
+	{Id: "default", Phases: []string{
+		"validate",
+		"initialize",
+		"generate-sources",
+		"process-sources",
+		"generate-resources",
+		"process-resources",
+		"compile",
+		"process-classes",
+		"generate-test-sources",
+		"process-test-sources",
+		"generate-test-resources",
+		"process-test-resources",
+		"test-compile",
+		"process-test-classes",
+		"test",
+		"prepare-package",
+		"package",
+		"pre-integration-test",
+		"integration-test",
+		"post-integration-test",
+		"verify",
+		"install",
+		"deploy",
+	}},
+	{Id: "site", Phases: []string{"pre-site", "site", "post-site", "site-deploy"}},
+}
+
+// LifecycleOf returns the lifecycle with the phase named phase, or nil.
+func LifecycleOf(phase string) *Lifecycle {
+	for i := range Lifecycles {
+		if slices.Contains(Lifecycles[i].Phases, phase) {
+			return &Lifecycles[i]
+		}
+	}
+	return nil
+}
+
+// Binding is a plugin goal that runs in a phase by default.
+type Binding struct {
+	Phase      string
+	GroupId    string
+	ArtifactId string
+	Version    string
+	Goal       string
+}
+
+// String returns the groupId:artifactId:version:goal of b.
+func (b Binding) String() string {
+	return b.GroupId + ":" + b.ArtifactId + ":" + b.Version + ":" + b.Goal
+}
+
+// goal is a goal of a plugin of DefaultPluginGroupId, by its prefix, like
+// compiler:compile for the compile goal of the maven-compiler-plugin.
+type goal struct {
+	phase string
+	goal  string
+}
+
+// jarGoals are the goals of the default lifecycle of the jar packaging, but
+// for the goal of its package phase.
+var jarGoals = []goal{
+	{"process-resources", "resources:resources"},
+	{"compile", "compiler:compile"},
+	{"process-test-resources", "resources:testResources"},
+	{"test-compile", "compiler:testCompile"},
+	{"test", "surefire:test"},
+}
+
+var deployGoals = []goal{
+	{"install", "install:install"},
+	{"deploy", "deploy:deploy"},
+}
+
+// packagingGoals are the goals of the default lifecycle of each packaging.
+var packagingGoals = map[string][]goal{
+	"pom":          deployGoals,
+	"jar":          concat(jarGoals, []goal{{"package", "jar:jar"}}, deployGoals),
+	"ejb":          concat(jarGoals, []goal{{"package", "ejb:ejb"}}, deployGoals),
+	"war":          concat(jarGoals, []goal{{"package", "war:war"}}, deployGoals),
+	"rar":          concat(jarGoals, []goal{{"package", "rar:rar"}}, deployGoals),
+	"maven-plugin": concat(jarGoals[:2], []goal{{"process-classes", "plugin:descriptor"}}, jarGoals[2:], []goal{{"package", "jar:jar"}, {"package", "plugin:addPluginArtifactMetadata"}}, deployGoals),
+	"ear": concat([]goal{
+		{"generate-resources", "ear:generate-application-xml"},
+		{"process-resources", "resources:resources"},
+		{"package", "ear:ear"},
+	}, deployGoals),
+}
+
+// otherGoals are the goals of the clean and site lifecycles, the same for
+// all packagings.
+var otherGoals = []goal{
+	{"clean", "clean:clean"},
+	{"site", "site:site"},
+	{"site-deploy", "site:deploy"},
+}
+
+func concat(lists ...[]goal) []goal {
+	var goals []goal
+	for _, l := range lists {
+		goals = append(goals, l...)
+	}
+	return goals
+}
+
+// maven3Plugins and maven4Plugins are the versions of the plugins bound to
+// the lifecycles by Maven 3.9 and 4.0, by prefix.
+var maven3Plugins = map[string]string{
+	"clean":     "3.2.0",
+	"resources": "3.3.1",
+	"compiler":  "3.11.0",
+	"surefire":  "3.2.2",
+	"jar":       "3.3.0",
+	"ejb":       "3.2.1",
+	"war":       "3.4.0",
+	"rar":       "2.4",
+	"ear":       "3.3.0",
+	"plugin":    "3.9.0",
+	"install":   "3.1.1",
+	"deploy":    "3.1.1",
+	"site":      "3.12.1",
+}
+
+var maven4Plugins = map[string]string{
+	"clean":     "3.4.1",
+	"resources": "3.3.1",
+	"compiler":  "3.14.0",
+	"surefire":  "3.5.3",
+	"jar":       "3.4.2",
+	"ejb":       "3.2.1",
+	"war":       "3.4.0",
+	"rar":       "3.0.0",
+	"ear":       "3.3.0",
+	"plugin":    "3.15.1",
+	"install":   "3.1.4",
+	"deploy":    "3.1.4",
+	"site":      "3.21.0",
+}
+
+// LifecycleBindings returns the goals that run by default in the phases of
+// the lifecycles for projects with packaging, an empty packaging being jar,
+// in the order of the phases, with the versions of the plugins of the Maven
+// version mavenVersion like SuperPOM. Maven 4 adds the bom packaging, bound
+// like pom. An unknown packaging, which would be defined by a build
+// extension, is an error.
+func LifecycleBindings(packaging, mavenVersion string) ([]Binding, error) {
+	packaging = orDefault(packaging, "jar")
+	versions := maven3Plugins
+	if isMaven4(mavenVersion) {
+		versions = maven4Plugins
+		if packaging == "bom" {
+			packaging = "pom"
+		}
+	}
+	goals, ok := packagingGoals[packaging]
+	if !ok {
+		return nil, fmt.Errorf("pom: unknown packaging %s", packaging)
+	}
+
+	var bindings []Binding
+	for _, lifecycle := range Lifecycles {
+		for _, phase := range lifecycle.Phases {
+			for _, g := range concat(otherGoals, goals) {
+				if g.phase != phase {
+					continue
+				}
+				prefix, name, _ := strings.Cut(g.goal, ":")
+				bindings = append(bindings, Binding{
+					Phase:      phase,
+					GroupId:    DefaultPluginGroupId,
+					ArtifactId: "maven-" + prefix + "-plugin",
+					Version:    versions[prefix],
+					Goal:       name,
+				})
+			}
+		}
+	}
+	return bindings, nil
+}
@@ -0,0 +1,66 @@
+package pom_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+func TestLifecycleBindings(t *testing.T) {
+	goals := func(bindings []pom.Binding) string {
+		var s []string
+		for _, b := range bindings {
+			s = append(s, b.Phase+"="+strings.TrimSuffix(strings.TrimPrefix(b.ArtifactId, "maven-"), "-plugin")+":"+b.Goal)
+		}
+		return strings.Join(s, " ")
+	}
+
+	t.Run("Should bind the goals of the packaging in phase order", func(t *testing.T) {
+		bindings, err := pom.LifecycleBindings("", "3.9.6")
+		if err != nil {
+			t.Fatalf("Expected no errors, but found: %s", err.Error())
+		}
+		expected := "clean=clean:clean process-resources=resources:resources compile=compiler:compile " +
+			"process-test-resources=resources:testResources test-compile=compiler:testCompile test=surefire:test " +
+			"package=jar:jar install=install:install deploy=deploy:deploy site=site:site site-deploy=site:deploy"
+		if found := goals(bindings); found != expected {
+			t.Errorf("Expected %s, but found: %s", expected, found)
+		}
+		if b := bindings[2]; b.String() != "org.apache.maven.plugins:maven-compiler-plugin:3.11.0:compile" {
+			t.Errorf("Expected the compiler plugin of Maven 3.9, but found: %s", b)
+		}
+	})
+
+	t.Run("Should bind the goals of other packagings", func(t *testing.T) {
+		for packaging, expected := range map[string]string{
+			"pom":          "clean=clean:clean install=install:install deploy=deploy:deploy site=site:site site-deploy=site:deploy",
+			"maven-plugin": "process-classes=plugin:descriptor",
+			"war":          "package=war:war",
+			"ear":          "generate-resources=ear:generate-application-xml",
+		} {
+			bindings, err := pom.LifecycleBindings(packaging, "4.0.0")
+			if err != nil || !strings.Contains(goals(bindings), expected) {
+				t.Errorf("Expected %s for %s, but found: %s %v", expected, packaging, goals(bindings), err)
+			}
+		}
+	})
+
+	t.Run("Should know the bom packaging from Maven 4", func(t *testing.T) {
+		if _, err := pom.LifecycleBindings("bom", "4.0.0"); err != nil {
+			t.Errorf("Expected no errors, but found: %s", err.Error())
+		}
+		if _, err := pom.LifecycleBindings("bom", "3.9.6"); err == nil {
+			t.Errorf("Expected an error for the bom packaging of Maven 3")
+		}
+	})
+
+	t.Run("Should find the lifecycle of a phase", func(t *testing.T) {
+		if l := pom.LifecycleOf("verify"); l == nil || l.Id != "default" {
+			t.Errorf("Expected the default lifecycle, but found: %v", l)
+		}
+		if l := pom.LifecycleOf("unknown"); l != nil {
+			t.Errorf("Expected no lifecycle, but found: %v", l)
+		}
+	})
+}
@@ -0,0 +1,216 @@
+// Package lint checks POMs against opinionated rules that go beyond the
+// schema, like hardcoded dependency versions or plain http repositories.
+//
+// A Linter runs a set of rules, DefaultRules unless configured otherwise,
+// with per rule severities. Findings are suppressed by a comment within the
+// element they concern, or one of its ancestors:
+//
+//	<dependency>
+//	  <!-- lint:ignore hardcoded-version,snapshot-dependency -->
+//	  ...
+//	</dependency>
+//
+// A lint:ignore comment without rule IDs suppresses all rules.
+package lint
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+// Severity is the importance of a finding.
+type Severity int
+
+const (
+	// SeverityOff disables a rule.
+	SeverityOff Severity = iota
+	SeverityNote
+	SeverityWarning
+	SeverityError
+)
+
+var severityNames = []string{"off", "note", "warning", "error"}
+
+func (s Severity) String() string {
+	if s < 0 || int(s) >= len(severityNames) {
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+	return severityNames[s]
+}
+
+// ParseSeverity returns the Severity named s: off, note, warning or error.
+func ParseSeverity(s string) (Severity, error) {
+	for i, name := range severityNames {
+		if strings.EqualFold(s, name) {
+			return Severity(i), nil
+		}
+	}
+	return SeverityOff, fmt.Errorf("lint: unknown severity %q", s)
+}
+
+// Finding is a problem reported by a rule.
+type Finding struct {
+	Rule     string
+	Severity Severity
+	// Path locates the element like the Path of a pom.Change.
+	Path    string
+	Message string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s [%s]", f.Severity, f.Path, f.Message, f.Rule)
+}
+
+// Rule checks a POM for one kind of problem.
+type Rule interface {
+	// ID identifies the rule in configurations and suppressions, like
+	// hardcoded-version.
+	ID() string
+	// Description is a sentence about what the rule reports.
+	Description() string
+	// Severity is the severity of the findings of the rule unless configured
+	// otherwise.
+	Severity() Severity
+	// Check reports the problems of ctx.Model to ctx.
+	Check(ctx *Context)
+}
+
+// Context is the POM a rule checks, and collects its findings.
+type Context struct {
+	Model *pom.Model
+
+	paths  map[any]string
+	report func(path, message string)
+}
+
+// Path returns the path of element, a pointer into Model like a
+// *pom.Dependency.
+func (c *Context) Path(element any) string {
+	return c.paths[element]
+}
+
+// Report reports a problem with element, a pointer into Model.
+func (c *Context) Report(element any, format string, args ...any) {
+	c.ReportAt(c.Path(element), format, args...)
+}
+
+// ReportAt reports a problem with the element at path, for those that are
+// not pointers into Model like properties.
+func (c *Context) ReportAt(path, format string, args ...any) {
+	c.report(path, fmt.Sprintf(format, args...))
+}
+
+// Linter runs rules on POMs.
+type Linter struct {
+	// Rules are the rules to run, DefaultRules when nil.
+	Rules []Rule
+	// Severity overrides the severity of rules by ID. SeverityOff disables a
+	// rule.
+	Severity map[string]Severity
+}
+
+// Lint checks m with the default rules and severities.
+func Lint(m *pom.Model) []Finding {
+	return (&Linter{}).Lint(m)
+}
+
+func (l *Linter) rules() []Rule {
+	if l.Rules == nil {
+		return DefaultRules()
+	}
+	return l.Rules
+}
+
+func (l *Linter) severity(r Rule) Severity {
+	if s, ok := l.Severity[r.ID()]; ok {
+		return s
+	}
+	return r.Severity()
+}
+
+// Lint checks m and returns the findings that are not suppressed, in the
+// order of the rules.
+func (l *Linter) Lint(m *pom.Model) []Finding {
+	paths := make(map[any]string)
+	suppressed := make(map[string][]string)
+	m.Walk(func(path string, element any) {
+		paths[element] = path
+		if ids, ok := suppressions(element); ok {
+			suppressed[path] = ids
+		}
+	})
+
+	var findings []Finding
+	for _, r := range l.rules() {
+		severity := l.severity(r)
+		if severity == SeverityOff {
+			continue
+		}
+
+		ctx := &Context{Model: m, paths: paths}
+		ctx.report = func(path, message string) {
+			if isSuppressed(suppressed, path, r.ID()) {
+				return
+			}
+			findings = append(findings, Finding{Rule: r.ID(), Severity: severity, Path: path, Message: message})
+		}
+		r.Check(ctx)
+	}
+	return findings
+}
+
+var ignoreDirective = regexp.MustCompile(`lint:ignore\b[ \t]*([\w.,-]*)`)
+
+// suppressions returns the rules suppressed by the comments of element, and
+// whether it has any lint:ignore comments. No rules means all of them.
+func suppressions(element any) ([]string, bool) {
+	comment, ok := commentOf(element)
+	if !ok {
+		return nil, false
+	}
+
+	var ids []string
+	found := false
+	for _, match := range ignoreDirective.FindAllStringSubmatch(comment, -1) {
+		if match[1] == "" {
+			return nil, true
+		}
+		found = true
+		ids = append(ids, strings.Split(match[1], ",")...)
+	}
+	return ids, found
+}
+
+func isSuppressed(suppressed map[string][]string, path, rule string) bool {
+	for prefix, ids := range suppressed {
+		if path != prefix && !strings.HasPrefix(path, prefix+"/") {
+			continue
+		}
+		if len(ids) == 0 {
+			return true
+		}
+		for _, id := range ids {
+			if id == rule {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// commentOf returns the comments within element, if it can have any.
+func commentOf(element any) (string, bool) {
+	v := reflect.ValueOf(element)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return "", false
+	}
+	f := v.Elem().FieldByName("Comment")
+	if !f.IsValid() || f.Kind() != reflect.String {
+		return "", false
+	}
+	return f.String(), true
+}
@@ -0,0 +1,155 @@
+package lint_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+	"github.com/obscurelyme/encoding/pom/lint"
+)
+
+func readLintFixture(t *testing.T) *pom.Model {
+	t.Helper()
+
+	m, err := pom.ReadFile(filepath.Join("testdata", "pom.xml"))
+	if err != nil {
+		t.Fatalf("Expected no errors reading pom, but found: %s", err.Error())
+	}
+	return m
+}
+
+func findingsOf(findings []lint.Finding, rule string) []lint.Finding {
+	var found []lint.Finding
+	for _, f := range findings {
+		if f.Rule == rule {
+			found = append(found, f)
+		}
+	}
+	return found
+}
+
+func TestLint(t *testing.T) {
+	t.Run("Should report the problems of each default rule", func(t *testing.T) {
+		findings := lint.Lint(readLintFixture(t))
+
+		expected := map[string]int{
+			"hardcoded-version":           2,
+			"conflicting-managed-version": 1,
+			"snapshot-dependency":         1,
+			"unpinned-plugin":             1,
+			"duplicate-repository":        1,
+			"insecure-repository":         1,
+			"unused-property":             1,
+			"deprecated-element":          2,
+		}
+		for rule, n := range expected {
+			if found := findingsOf(findings, rule); len(found) != n {
+				t.Errorf("Expected %d %s findings, but found: %v", n, rule, found)
+			}
+		}
+
+		snapshot := findingsOf(findings, "snapshot-dependency")[0]
+		if snapshot.Severity != lint.SeverityError || snapshot.Path != "/project/dependencies/dependency[org.example:library:jar:]" {
+			t.Errorf("Expected an error on the library dependency, but found: %s", snapshot)
+		}
+		if unused := findingsOf(findings, "unused-property")[0]; unused.Path != "/project/properties/unused.version" {
+			t.Errorf("Expected unused.version to be reported, but found: %s", unused)
+		}
+	})
+
+	t.Run("Should honor suppression comments", func(t *testing.T) {
+		for _, f := range lint.Lint(readLintFixture(t)) {
+			if f.Path == "/project/dependencies/dependency[org.junit.jupiter:junit-jupiter:jar:]" {
+				t.Errorf("Expected the junit-jupiter version to be suppressed, but found: %s", f)
+			}
+			if f.Path == "/project/build/plugins/plugin[org.apache.maven.plugins:maven-jar-plugin]" {
+				t.Errorf("Expected all rules to be suppressed for the jar plugin, but found: %s", f)
+			}
+		}
+	})
+
+	t.Run("Should apply configured severities", func(t *testing.T) {
+		l := &lint.Linter{Severity: map[string]lint.Severity{
+			"hardcoded-version": lint.SeverityOff,
+			"unused-property":   lint.SeverityError,
+		}}
+		findings := l.Lint(readLintFixture(t))
+
+		if found := findingsOf(findings, "hardcoded-version"); len(found) != 0 {
+			t.Errorf("Expected the disabled rule not to run, but found: %v", found)
+		}
+		if found := findingsOf(findings, "unused-property"); found[0].Severity != lint.SeverityError {
+			t.Errorf("Expected the configured severity, but found: %s", found[0].Severity)
+		}
+	})
+
+	t.Run("Should run the configured rules", func(t *testing.T) {
+		l := &lint.Linter{Rules: []lint.Rule{lint.InsecureRepository}}
+
+		if findings := l.Lint(readLintFixture(t)); len(findings) != 1 || findings[0].Rule != "insecure-repository" {
+			t.Errorf("Expected only the insecure repository, but found: %v", findings)
+		}
+	})
+}
+
+func TestParseSeverity(t *testing.T) {
+	t.Run("Should parse severity names", func(t *testing.T) {
+		if s, err := lint.ParseSeverity("Warning"); err != nil || s != lint.SeverityWarning {
+			t.Errorf("Expected warning, but found: %s %v", s, err)
+		}
+		if _, err := lint.ParseSeverity("fatal"); err == nil {
+			t.Errorf("Expected an error for an unknown severity")
+		}
+	})
+}
+
+func TestWriteSARIF(t *testing.T) {
+	t.Run("Should write a SARIF log", func(t *testing.T) {
+		l := &lint.Linter{}
+		findings := l.Lint(readLintFixture(t))
+
+		var buf bytes.Buffer
+		if err := l.WriteSARIF(&buf, lint.Report{URI: "pom.xml", Findings: findings}); err != nil {
+			t.Fatalf("Expected no errors writing SARIF, but found: %s", err.Error())
+		}
+
+		var log struct {
+			Version string
+			Runs    []struct {
+				Tool struct {
+					Driver struct {
+						Rules []struct{ Id string }
+					}
+				}
+				Results []struct {
+					RuleId    string
+					RuleIndex int
+					Level     string
+					Locations []struct {
+						PhysicalLocation struct {
+							ArtifactLocation struct{ URI string }
+						}
+					}
+				}
+			}
+		}
+		if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+			t.Fatalf("Expected valid JSON, but found: %s", err.Error())
+		}
+
+		run := log.Runs[0]
+		if log.Version != "2.1.0" || len(run.Tool.Driver.Rules) != len(lint.DefaultRules()) || len(run.Results) != len(findings) {
+			t.Fatalf("Expected a run with the rules and results, but found: %s", buf.String())
+		}
+		for _, r := range run.Results {
+			if run.Tool.Driver.Rules[r.RuleIndex].Id != r.RuleId || r.Locations[0].PhysicalLocation.ArtifactLocation.URI != "pom.xml" {
+				t.Errorf("Expected results to refer to their rule and file, but found: %+v", r)
+			}
+			if r.RuleId == "insecure-repository" && r.Level != "error" {
+				t.Errorf("Expected the level error, but found: %s", r.Level)
+			}
+		}
+	})
+}
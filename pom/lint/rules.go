@@ -0,0 +1,297 @@
+package lint
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+// rule is a Rule made of a check function.
+type rule struct {
+	id          string
+	description string
+	severity    Severity
+	check       func(ctx *Context)
+}
+
+func (r *rule) ID() string          { return r.id }
+func (r *rule) Description() string { return r.description }
+func (r *rule) Severity() Severity  { return r.severity }
+func (r *rule) Check(ctx *Context)  { r.check(ctx) }
+
+var (
+	// HardcodedVersion reports dependency versions that are not taken from a
+	// property.
+	HardcodedVersion Rule = &rule{
+		id:          "hardcoded-version",
+		description: "Dependency versions should be defined by properties.",
+		severity:    SeverityNote,
+		check:       checkHardcodedVersion,
+	}
+	// ConflictingManagedVersion reports dependencies that declare a version
+	// other than the one of the same dependency in the dependency management
+	// of the POM.
+	ConflictingManagedVersion Rule = &rule{
+		id:          "conflicting-managed-version",
+		description: "Dependencies should not override the version managed by the same POM.",
+		severity:    SeverityWarning,
+		check:       checkConflictingManagedVersion,
+	}
+	// SnapshotDependency reports SNAPSHOT parents, dependencies and plugins
+	// of a release.
+	SnapshotDependency Rule = &rule{
+		id:          "snapshot-dependency",
+		description: "Releases should not depend on SNAPSHOT versions.",
+		severity:    SeverityError,
+		check:       checkSnapshotDependency,
+	}
+	// UnpinnedPlugin reports plugins without a version in the POM or its
+	// plugin management. Plugins managed by a parent are reported too, so
+	// the rule is best suppressed in the children of such a parent.
+	UnpinnedPlugin Rule = &rule{
+		id:          "unpinned-plugin",
+		description: "Plugins should have a version, for reproducible builds.",
+		severity:    SeverityWarning,
+		check:       checkUnpinnedPlugin,
+	}
+	// DuplicateRepository reports repositories with the same id or url as
+	// another one in the same list.
+	DuplicateRepository Rule = &rule{
+		id:          "duplicate-repository",
+		description: "Repositories should be declared once.",
+		severity:    SeverityWarning,
+		check:       checkDuplicateRepository,
+	}
+	// InsecureRepository reports repositories with a plain http url.
+	InsecureRepository Rule = &rule{
+		id:          "insecure-repository",
+		description: "Repositories should use https.",
+		severity:    SeverityError,
+		check:       checkInsecureRepository,
+	}
+	// UnusedProperty reports properties no expression of the POM refers to.
+	// Properties read by Maven and plugins without an expression, like
+	// project.build.sourceEncoding and maven.compiler.release, are ignored,
+	// but properties for the children of a parent are reported.
+	UnusedProperty Rule = &rule{
+		id:          "unused-property",
+		description: "Properties should be used.",
+		severity:    SeverityNote,
+		check:       checkUnusedProperty,
+	}
+	// DeprecatedElement reports the project reports and plugin goals that
+	// Maven does not use.
+	DeprecatedElement Rule = &rule{
+		id:          "deprecated-element",
+		description: "Elements Maven ignores should be removed.",
+		severity:    SeverityWarning,
+		check:       checkDeprecatedElement,
+	}
+)
+
+// DefaultRules returns the rules a Linter runs by default.
+func DefaultRules() []Rule {
+	return []Rule{
+		HardcodedVersion,
+		ConflictingManagedVersion,
+		SnapshotDependency,
+		UnpinnedPlugin,
+		DuplicateRepository,
+		InsecureRepository,
+		UnusedProperty,
+		DeprecatedElement,
+	}
+}
+
+// implicitProperties are prefixes of properties that Maven and plugins read
+// without an expression.
+var implicitProperties = []string{
+	"project.",
+	"maven.",
+	"surefire.",
+	"failsafe.",
+	"jacoco.",
+	"sonar.",
+	"gpg.",
+	"argLine",
+}
+
+func isExpression(s string) bool {
+	return strings.Contains(s, "${")
+}
+
+func checkHardcodedVersion(ctx *Context) {
+	ctx.Model.Walk(func(path string, element any) {
+		if d, ok := element.(*pom.Dependency); ok && d.Version != "" && !isExpression(d.Version) {
+			ctx.Report(d, "version %s of %s:%s is hardcoded, use a property", d.Version, d.GroupId, d.ArtifactId)
+		}
+	})
+}
+
+func checkConflictingManagedVersion(ctx *Context) {
+	check := func(deps *pom.Dependencies, management *pom.DependencyManagement) {
+		if deps == nil || management == nil || management.Dependencies == nil {
+			return
+		}
+
+		managed := make(map[string]string)
+		for _, d := range management.Dependencies.Dependency {
+			managed[d.ManagementKey()] = d.Version
+		}
+		for i := range deps.Dependency {
+			d := &deps.Dependency[i]
+			if version := managed[d.ManagementKey()]; d.Version != "" && version != "" && d.Version != version {
+				ctx.Report(d, "version %s of %s:%s differs from the managed version %s", d.Version, d.GroupId, d.ArtifactId, version)
+			}
+		}
+	}
+
+	m := ctx.Model
+	check(m.Dependencies, m.DependencyManagement)
+	if m.Profiles != nil {
+		for i := range m.Profiles.Profile {
+			p := &m.Profiles.Profile[i]
+			check(p.Dependencies, m.DependencyManagement)
+			check(p.Dependencies, p.DependencyManagement)
+		}
+	}
+}
+
+func checkSnapshotDependency(ctx *Context) {
+	m := ctx.Model
+	version := m.Version
+	if version == "" && m.Parent != nil {
+		version = m.Parent.Version
+	}
+	if version == "" || isExpression(version) || pom.IsSnapshot(version) {
+		return
+	}
+
+	ctx.Model.Walk(func(path string, element any) {
+		switch e := element.(type) {
+		case *pom.Parent:
+			if pom.IsSnapshot(e.Version) {
+				ctx.Report(e, "release %s has the SNAPSHOT parent %s:%s:%s", version, e.GroupId, e.ArtifactId, e.Version)
+			}
+		case *pom.Dependency:
+			if pom.IsSnapshot(e.Version) {
+				ctx.Report(e, "release %s depends on %s:%s:%s", version, e.GroupId, e.ArtifactId, e.Version)
+			}
+		case *pom.Plugin:
+			if pom.IsSnapshot(e.Version) {
+				ctx.Report(e, "release %s uses the plugin %s:%s", version, e.Key(), e.Version)
+			}
+		}
+	})
+}
+
+func checkUnpinnedPlugin(ctx *Context) {
+	managed := make(map[string]bool)
+	ctx.Model.Walk(func(path string, element any) {
+		if p, ok := element.(*pom.Plugin); ok && strings.Contains(path, "/pluginManagement/") && p.Version != "" {
+			managed[p.Key()] = true
+		}
+	})
+
+	ctx.Model.Walk(func(path string, element any) {
+		switch p := element.(type) {
+		case *pom.Plugin:
+			if p.Version == "" && !managed[p.Key()] {
+				ctx.Report(p, "plugin %s has no version", p.Key())
+			}
+		case *pom.ReportPlugin:
+			if p.Version == "" && !managed[p.Key()] {
+				ctx.Report(p, "report plugin %s has no version", p.Key())
+			}
+		}
+	})
+}
+
+func checkDuplicateRepository(ctx *Context) {
+	check := func(repos []pom.Repository) {
+		ids := make(map[string]bool)
+		urls := make(map[string]bool)
+		for i := range repos {
+			r := &repos[i]
+			url := strings.TrimSuffix(r.Url, "/")
+			switch {
+			case r.Id != "" && ids[r.Id]:
+				ctx.Report(r, "repository %s is declared more than once", r.Id)
+			case url != "" && urls[url]:
+				ctx.Report(r, "repository %s has the same url as another one: %s", r.Id, r.Url)
+			}
+			ids[r.Id] = true
+			urls[url] = true
+		}
+	}
+
+	ctx.Model.Walk(func(path string, element any) {
+		switch e := element.(type) {
+		case *pom.Repositories:
+			check(e.Repository)
+		case *pom.PluginRepositories:
+			check(e.PluginRepository)
+		}
+	})
+}
+
+func checkInsecureRepository(ctx *Context) {
+	insecure := func(url string) bool {
+		return len(url) >= 7 && strings.EqualFold(url[:7], "http://")
+	}
+
+	ctx.Model.Walk(func(path string, element any) {
+		switch r := element.(type) {
+		case *pom.Repository:
+			if insecure(r.Url) {
+				ctx.Report(r, "repository %s uses http: %s", r.Id, r.Url)
+			}
+		case *pom.DeploymentRepository:
+			if insecure(r.Url) {
+				ctx.Report(r, "repository %s uses http: %s", r.Id, r.Url)
+			}
+		}
+	})
+}
+
+func checkUnusedProperty(ctx *Context) {
+	var buf bytes.Buffer
+	if err := ctx.Model.Write(&buf, nil); err != nil {
+		return
+	}
+	document := buf.String()
+
+	ctx.Model.Walk(func(path string, element any) {
+		props, ok := element.(*pom.Properties)
+		if !ok {
+			return
+		}
+		for _, name := range props.Keys() {
+			if isImplicitProperty(name) || strings.Contains(document, "${"+name+"}") || strings.Contains(document, "@{"+name+"}") {
+				continue
+			}
+			ctx.ReportAt(path+"/"+name, "property %s is not used", name)
+		}
+	})
+}
+
+func isImplicitProperty(name string) bool {
+	for _, prefix := range implicitProperties {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkDeprecatedElement(ctx *Context) {
+	if ctx.Model.Reports != nil {
+		ctx.Report(ctx.Model.Reports, "reports are not used since Maven 2, configure report plugins in reporting instead")
+	}
+	ctx.Model.Walk(func(path string, element any) {
+		if p, ok := element.(*pom.Plugin); ok && p.Goals != nil {
+			ctx.Report(p.Goals, "plugin goals are not used, declare them in an execution instead")
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package lint
+
+import (
+	"encoding/json"
+	"io"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolName     = "pom-lint"
+	toolURI      = "https://github.com/obscurelyme/encoding"
+)
+
+// Report is the findings of one file, for WriteSARIF.
+type Report struct {
+	// URI locates the file, usually relative to the repository root.
+	URI      string
+	Findings []Finding
+}
+
+// sarifLevels maps severities to SARIF result levels.
+var sarifLevels = map[Severity]string{
+	SeverityOff:     "none",
+	SeverityNote:    "note",
+	SeverityWarning: "warning",
+	SeverityError:   "error",
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	Id                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleId    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// WriteSARIF writes the findings of reports to w as a SARIF 2.1.0 log, for
+// code scanning services. The rules of l are described with their configured
+// severities. Findings are located by file and element path, as POM models
+// do not record line numbers.
+func (l *Linter) WriteSARIF(w io.Writer, reports ...Report) error {
+	driver := sarifDriver{Name: toolName, InformationURI: toolURI, Rules: []sarifRule{}}
+	index := make(map[string]int)
+	for _, r := range l.rules() {
+		index[r.ID()] = len(driver.Rules)
+		driver.Rules = append(driver.Rules, sarifRule{
+			Id:                   r.ID(),
+			ShortDescription:     sarifMessage{Text: r.Description()},
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevels[l.severity(r)]},
+		})
+	}
+
+	results := []sarifResult{}
+	for _, report := range reports {
+		for _, f := range report.Findings {
+			location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: report.URI}}}
+			if f.Path != "" {
+				location.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: f.Path, Kind: "element"}}
+			}
+			results = append(results, sarifResult{
+				RuleId:    f.Rule,
+				RuleIndex: index[f.Rule],
+				Level:     sarifLevels[f.Severity],
+				Message:   sarifMessage{Text: f.Message},
+				Locations: []sarifLocation{location},
+			})
+		}
+	}
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	})
+}
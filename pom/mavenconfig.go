@@ -0,0 +1,284 @@
+package pom
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// MavenConfig is a .mvn/maven.config file, holding command line options that
+// Maven adds to those of the command line of the projects below it.
+type MavenConfig struct {
+	// Path is the location of the file.
+	Path string
+	// Properties are the user properties set by -D options.
+	Properties map[string]string
+	// ActiveProfiles and InactiveProfiles are the ids of the profiles
+	// activated and deactivated by -P options.
+	ActiveProfiles   []string
+	InactiveProfiles []string
+	// Projects are the selectors of the projects of the reactor to build,
+	// given by -pl options, like app or :core. See Reactor.Select.
+	Projects []string
+
+	data     []byte
+	original []byte
+}
+
+// configToken is a command line argument of a MavenConfig, located by byte
+// offsets.
+type configToken struct {
+	value      string
+	start, end int
+	// prefix is the option preceding the name=value argument of a -D option
+	// within the same argument, like -D in -Dname=value.
+	prefix string
+}
+
+// ReadMavenConfig reads the maven.config file at path.
+func ReadMavenConfig(path string) (*MavenConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &MavenConfig{Path: path, data: data, original: data}
+	c.parse()
+	return c, nil
+}
+
+// mavenConfigPath returns the location of the maven.config file of the
+// project in dir.
+func mavenConfigPath(dir string) string {
+	return filepath.Join(dir, ".mvn", "maven.config")
+}
+
+func (c *MavenConfig) parse() {
+	c.Properties = make(map[string]string)
+	for _, d := range c.defines() {
+		name, value, _ := strings.Cut(d.value, "=")
+		c.Properties[name] = value
+	}
+
+	c.ActiveProfiles, c.InactiveProfiles = nil, nil
+	c.Projects = nil
+	tokens := configTokens(c.data)
+	for _, ids := range optionValues(tokens, "-P", "--activate-profiles") {
+		active, inactive := ParseProfileIds(ids)
+		c.ActiveProfiles = append(c.ActiveProfiles, active...)
+		c.InactiveProfiles = append(c.InactiveProfiles, inactive...)
+	}
+	for _, projects := range optionValues(tokens, "-pl", "--projects") {
+		c.Projects = append(c.Projects, splitList(projects)...)
+	}
+}
+
+// optionValues returns the values of the option with the short and long
+// names in tokens, given as the next argument or within the same one, like
+// -P a, -Pa, --activate-profiles a or --activate-profiles=a.
+func optionValues(tokens []configToken, short, long string) []string {
+	var values []string
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i].value
+		switch {
+		case t == short || t == long:
+			if i+1 < len(tokens) {
+				i++
+				values = append(values, tokens[i].value)
+			}
+		case strings.HasPrefix(t, long+"="):
+			values = append(values, t[len(long)+1:])
+		case strings.HasPrefix(t, short) && !strings.HasPrefix(t, "--"):
+			values = append(values, t[len(short):])
+		}
+	}
+	return values
+}
+
+// splitList splits a comma separated list, dropping empty elements.
+func splitList(list string) []string {
+	return strings.FieldsFunc(list, func(r rune) bool { return r == ',' })
+}
+
+// ParseProfileIds splits the comma separated profile ids of a -P option into
+// those it activates and those it deactivates, prefixed by ! or -. The +
+// and ? prefixes of profiles activated explicitly or optionally are dropped.
+func ParseProfileIds(ids string) (active, inactive []string) {
+	for _, id := range splitList(strings.TrimSpace(ids)) {
+		id = strings.TrimSpace(id)
+		if id, ok := strings.CutPrefix(id, "!"); ok {
+			inactive = append(inactive, strings.TrimPrefix(id, "?"))
+		} else if id, ok := strings.CutPrefix(id, "-"); ok {
+			inactive = append(inactive, strings.TrimPrefix(id, "?"))
+		} else {
+			id = strings.TrimPrefix(id, "+")
+			active = append(active, strings.TrimPrefix(id, "?"))
+		}
+	}
+	return active, inactive
+}
+
+// Apply returns a copy of opts with the options of c added: the properties
+// of c under those of opts, which take precedence like -D options on the
+// command line, and the profiles of c before those of opts. A nil opts is
+// the same as empty EffectiveOptions.
+func (c *MavenConfig) Apply(opts *EffectiveOptions) *EffectiveOptions {
+	applied := &EffectiveOptions{}
+	if opts != nil {
+		*applied = *opts
+	}
+
+	props := make(map[string]string, len(c.Properties)+len(applied.Properties))
+	for name, value := range c.Properties {
+		props[name] = value
+	}
+	for name, value := range applied.Properties {
+		props[name] = value
+	}
+	applied.Properties = props
+	applied.ActiveProfiles = append(slices.Clip(c.ActiveProfiles), applied.ActiveProfiles...)
+	applied.InactiveProfiles = append(slices.Clip(c.InactiveProfiles), applied.InactiveProfiles...)
+	return applied
+}
+
+// defines returns the name=value arguments of the -D options of c.
+func (c *MavenConfig) defines() []configToken {
+	var defines []configToken
+	tokens := configTokens(c.data)
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		switch {
+		case t.value == "-D" || t.value == "--define":
+			if i+1 < len(tokens) {
+				i++
+				defines = append(defines, tokens[i])
+			}
+		case strings.HasPrefix(t.value, "--define="):
+			defines = append(defines, configToken{value: t.value[len("--define="):], start: t.start, end: t.end, prefix: "--define="})
+		case strings.HasPrefix(t.value, "-D"):
+			defines = append(defines, configToken{value: t.value[2:], start: t.start, end: t.end, prefix: "-D"})
+		}
+	}
+	return defines
+}
+
+// configTokens splits data into arguments at whitespace outside of quotes.
+func configTokens(data []byte) []configToken {
+	var tokens []configToken
+	for i := 0; i < len(data); {
+		if isSpace(data[i]) {
+			i++
+			continue
+		}
+
+		start := i
+		var value strings.Builder
+		var quote byte
+		for ; i < len(data) && (quote != 0 || !isSpace(data[i])); i++ {
+			switch ch := data[i]; {
+			case quote == 0 && (ch == '"' || ch == '\''):
+				quote = ch
+			case ch == quote:
+				quote = 0
+			default:
+				value.WriteByte(ch)
+			}
+		}
+		tokens = append(tokens, configToken{value: value.String(), start: start, end: i})
+	}
+	return tokens
+}
+
+func isSpace(ch byte) bool {
+	return ch == ' ' || ch == '\t' || ch == '\r' || ch == '\n'
+}
+
+// Set sets the user property name to value, replacing the last -D option
+// for it or adding one on a line of its own.
+func (c *MavenConfig) Set(name, value string) {
+	var last *configToken
+	defines := c.defines()
+	for i := range defines {
+		if n, _, _ := strings.Cut(defines[i].value, "="); n == name {
+			last = &defines[i]
+		}
+	}
+
+	var data []byte
+	if last != nil {
+		data = append(data, c.data[:last.start]...)
+		data = append(data, quoteArgument(last.prefix+name+"="+value)...)
+		data = append(data, c.data[last.end:]...)
+	} else {
+		data = append(data, c.data...)
+		if len(data) > 0 && data[len(data)-1] != '\n' {
+			data = append(data, '\n')
+		}
+		data = append(data, quoteArgument("-D"+name+"="+value)+"\n"...)
+	}
+	c.data = data
+	c.parse()
+}
+
+// quoteArgument quotes arg if it holds whitespace or quotes.
+func quoteArgument(arg string) string {
+	switch {
+	case !strings.ContainsAny(arg, " \t\r\n\"'"):
+		return arg
+	case !strings.Contains(arg, `"`):
+		return `"` + arg + `"`
+	}
+	return "'" + arg + "'"
+}
+
+// Bytes returns the file with the properties set so far.
+func (c *MavenConfig) Bytes() []byte {
+	return c.data
+}
+
+// Modified reports whether properties were set since the file was read or
+// last written.
+func (c *MavenConfig) Modified() bool {
+	return !bytes.Equal(c.data, c.original)
+}
+
+// FindProjectRoot returns the root of the multi-module project of dir: the
+// closest of dir and its ancestors that has a .mvn directory, like Maven's
+// maven.multiModuleProjectDirectory. It returns an empty string if there
+// is no .mvn directory.
+func FindProjectRoot(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		if info, err := os.Stat(filepath.Join(dir, ".mvn")); err == nil && info.IsDir() {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// FindMavenConfig reads the .mvn/maven.config file for the project in dir,
+// from the root found by FindProjectRoot, like Maven does. It returns nil if
+// that directory has no maven.config file, or if there is no .mvn directory.
+func FindMavenConfig(dir string) (*MavenConfig, error) {
+	root, err := FindProjectRoot(dir)
+	if err != nil || root == "" {
+		return nil, err
+	}
+
+	c, err := ReadMavenConfig(mavenConfigPath(root))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return c, err
+}
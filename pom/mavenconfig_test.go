@@ -0,0 +1,84 @@
+package pom_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+func TestMavenConfig(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, ".mvn"), 0o755)
+	path := filepath.Join(dir, ".mvn", "maven.config")
+	os.WriteFile(path, []byte("-T 1C -D revision=1.0 \"-Dname=a b\"\n--define=sha1=abc -Dchangelist=-SNAPSHOT\n"), 0o644)
+
+	t.Run("Should read the -D options", func(t *testing.T) {
+		c, err := pom.ReadMavenConfig(path)
+		if err != nil {
+			t.Fatalf("Expected no errors reading maven.config, but found: %s", err.Error())
+		}
+		expected := map[string]string{"revision": "1.0", "name": "a b", "sha1": "abc", "changelist": "-SNAPSHOT"}
+		for name, value := range expected {
+			if c.Properties[name] != value {
+				t.Errorf("Expected %s=%s, but found: %s", name, value, c.Properties[name])
+			}
+		}
+		if c.Modified() {
+			t.Errorf("Expected a config that was just read not to be modified")
+		}
+	})
+
+	t.Run("Should set properties in place", func(t *testing.T) {
+		c, _ := pom.ReadMavenConfig(path)
+
+		c.Set("revision", "2.0")
+		c.Set("name", "c")
+		c.Set("sha1", "")
+		c.Set("added", "x y")
+
+		expected := "-T 1C -D revision=2.0 -Dname=c\n--define=sha1= -Dchangelist=-SNAPSHOT\n\"-Dadded=x y\"\n"
+		if string(c.Bytes()) != expected {
+			t.Errorf("Expected %q, but found: %q", expected, c.Bytes())
+		}
+	})
+
+	t.Run("Should read the profiles and projects", func(t *testing.T) {
+		data := "-P ci,!slow -Prelease --activate-profiles=-docs,?optional\n-pl app,:core --projects tools\n"
+		profiles := filepath.Join(dir, "profiles.config")
+		if err := os.WriteFile(profiles, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		c, err := pom.ReadMavenConfig(profiles)
+		if err != nil {
+			t.Fatalf("Expected no errors reading maven.config, but found: %s", err.Error())
+		}
+		if strings.Join(c.ActiveProfiles, ",") != "ci,release,optional" || strings.Join(c.InactiveProfiles, ",") != "slow,docs" {
+			t.Errorf("Expected the profiles of the -P options, but found: %v %v", c.ActiveProfiles, c.InactiveProfiles)
+		}
+		if strings.Join(c.Projects, ",") != "app,:core,tools" {
+			t.Errorf("Expected the projects of the -pl options, but found: %v", c.Projects)
+		}
+
+		opts := c.Apply(&pom.EffectiveOptions{ActiveProfiles: []string{"local"}, Properties: map[string]string{"a": "1"}})
+		if strings.Join(opts.ActiveProfiles, ",") != "ci,release,optional,local" || opts.Properties["a"] != "1" {
+			t.Errorf("Expected the options of the config before those given, but found: %v", opts)
+		}
+	})
+
+	t.Run("Should find the config of the closest .mvn directory", func(t *testing.T) {
+		module := filepath.Join(dir, "module", "sub")
+		os.MkdirAll(module, 0o755)
+
+		c, err := pom.FindMavenConfig(module)
+		if err != nil || c == nil || c.Properties["revision"] != "1.0" {
+			t.Errorf("Expected the config of the root, but found: %v %v", c, err)
+		}
+		if root, err := pom.FindProjectRoot(module); err != nil || root != dir {
+			t.Errorf("Expected the root %s, but found: %s %v", dir, root, err)
+		}
+	})
+}
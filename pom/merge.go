@@ -0,0 +1,354 @@
+package pom
+
+import (
+	"encoding/xml"
+	"reflect"
+	"slices"
+)
+
+// merger merges a source element into a target element following Maven's
+// model merging rules. Values present in both are kept from the target unless
+// sourceDominant, lists of identifiable elements like dependencies and plugins
+// are merged by their key, and plugin configuration is merged element by
+// element.
+type merger struct {
+	sourceDominant bool
+	// inheritance skips plugins, executions and report sets that are not
+	// inherited by children.
+	inheritance bool
+}
+
+// mergeInto merges source into target, both pointers to the same type.
+func (mg merger) mergeInto(target, source any) {
+	mg.merge(reflect.ValueOf(target).Elem(), reflect.ValueOf(source).Elem())
+}
+
+func (mg merger) merge(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.String:
+		if src.String() != "" && (dst.String() == "" || mg.sourceDominant) {
+			dst.SetString(src.String())
+		}
+	case reflect.Bool:
+		if src.Bool() {
+			dst.SetBool(true)
+		}
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.New(src.Type().Elem()))
+			deepCopy(dst.Elem(), src.Elem())
+			mg.filter(dst)
+			return
+		}
+		switch d := dst.Interface().(type) {
+		case *Properties:
+			mergeProperties(d, src.Interface().(*Properties), mg.sourceDominant)
+		case *DOM:
+			if mg.sourceDominant {
+				c := src.Interface().(*DOM).Clone()
+				mergeDOM(c, d)
+				*d = *c
+			} else {
+				mergeDOM(d, src.Interface().(*DOM))
+			}
+		default:
+			mg.merge(dst.Elem(), src.Elem())
+		}
+	case reflect.Struct:
+		if src.Type() == xmlNameType {
+			return
+		}
+		for i := 0; i < src.NumField(); i++ {
+			if src.Type().Field(i).IsExported() {
+				mg.merge(dst.Field(i), src.Field(i))
+			}
+		}
+	case reflect.Slice:
+		mg.mergeSlice(dst, src)
+	}
+}
+
+// filter removes what is not inherited from a copy of a source element.
+func (mg merger) filter(v reflect.Value) {
+	if !mg.inheritance {
+		return
+	}
+
+	switch e := v.Interface().(type) {
+	case *Plugins:
+		e.Plugin = slices.DeleteFunc(e.Plugin, func(p Plugin) bool { return p.Inherited == "false" })
+		for i := range e.Plugin {
+			mg.filter(reflect.ValueOf(&e.Plugin[i]))
+		}
+	case *Plugin:
+		mg.filter(reflect.ValueOf(e.Executions))
+	case *Executions:
+		if e != nil {
+			e.Execution = slices.DeleteFunc(e.Execution, func(x Execution) bool { return x.Inherited == "false" })
+		}
+	case *ReportPlugins:
+		e.Plugin = slices.DeleteFunc(e.Plugin, func(p ReportPlugin) bool { return p.Inherited == "false" })
+		for i := range e.Plugin {
+			mg.filter(reflect.ValueOf(e.Plugin[i].ReportSets))
+		}
+	case *ReportSets:
+		if e != nil {
+			e.ReportSet = slices.DeleteFunc(e.ReportSet, func(r ReportSet) bool { return r.Inherited == "false" })
+		}
+	case *PluginManagement:
+		if e.Plugins != nil {
+			mg.filter(reflect.ValueOf(e.Plugins))
+		}
+	case *BuildBase:
+		if e.Plugins != nil {
+			mg.filter(reflect.ValueOf(e.Plugins))
+		}
+		if e.PluginManagement != nil {
+			mg.filter(reflect.ValueOf(e.PluginManagement))
+		}
+	case *Build:
+		mg.filter(reflect.ValueOf(&e.BuildBase))
+	case *Reporting:
+		if e.Plugins != nil {
+			mg.filter(reflect.ValueOf(e.Plugins))
+		}
+	}
+}
+
+// inherited reports whether the source element v is merged, which is always
+// the case outside of inheritance.
+func (mg merger) inherited(v reflect.Value) bool {
+	if !mg.inheritance {
+		return true
+	}
+
+	switch e := v.Addr().Interface().(type) {
+	case *Plugin:
+		return e.Inherited != "false"
+	case *ReportPlugin:
+		return e.Inherited != "false"
+	case *Execution:
+		return e.Inherited != "false"
+	case *ReportSet:
+		return e.Inherited != "false"
+	}
+	return true
+}
+
+// mergeKey returns the identity of an element of a merged list, and whether
+// elements of that type are merged deeply rather than replaced as a whole.
+func mergeKey(v reflect.Value) (key string, deep bool, ok bool) {
+	switch e := v.Addr().Interface().(type) {
+	case *Dependency:
+		return e.ManagementKey(), false, true
+	case *Exclusion:
+		return e.GroupId + ":" + e.ArtifactId, false, true
+	case *Repository:
+		return e.Id, false, true
+	case *Extension:
+		return e.GroupId + ":" + e.ArtifactId, false, true
+	case *Plugin:
+		return e.Key(), true, true
+	case *ReportPlugin:
+		return pluginKey(e.GroupId, e.ArtifactId), true, true
+	case *Execution:
+		return orDefault(e.Id, DefaultExecutionId), true, true
+	case *ReportSet:
+		return orDefault(e.Id, DefaultExecutionId), true, true
+	}
+	return "", false, false
+}
+
+func (mg merger) mergeSlice(dst, src reflect.Value) {
+	if src.Len() == 0 {
+		return
+	}
+
+	switch {
+	case src.Type().Elem().Kind() == reflect.String:
+		// lists of values, like goals and modules, are combined
+		for i := 0; i < src.Len(); i++ {
+			if !containsString(dst, src.Index(i).String()) {
+				dst.Set(reflect.Append(dst, src.Index(i)))
+			}
+		}
+		return
+	case src.Type().Elem() == reflect.TypeOf(xml.Attr{}):
+		attrs := dst.Interface().([]xml.Attr)
+		for _, attr := range src.Interface().([]xml.Attr) {
+			i := slices.IndexFunc(attrs, func(a xml.Attr) bool { return a.Name == attr.Name })
+			switch {
+			case i < 0:
+				attrs = append(attrs, attr)
+			case mg.sourceDominant:
+				attrs[i] = attr
+			}
+		}
+		dst.Set(reflect.ValueOf(attrs))
+		return
+	}
+
+	if _, _, ok := mergeKey(src.Index(0)); !ok {
+		// other lists, like licenses and resources, are replaced as a whole
+		if dst.Len() == 0 || mg.sourceDominant {
+			deepCopy(dst, src)
+		}
+		return
+	}
+
+	index := make(map[string]int, dst.Len())
+	for i := 0; i < dst.Len(); i++ {
+		key, _, _ := mergeKey(dst.Index(i))
+		index[key] = i
+	}
+
+	// source elements missing from dst are placed before the next element
+	// they have in common when merged deeply, like Maven orders inherited
+	// plugins, and after all of dst otherwise
+	var pending []reflect.Value
+	before := make(map[int][]reflect.Value)
+	deep := false
+	for i := 0; i < src.Len(); i++ {
+		if !mg.inherited(src.Index(i)) {
+			continue
+		}
+		var key string
+		key, deep, _ = mergeKey(src.Index(i))
+		if j, ok := index[key]; ok {
+			switch {
+			case deep:
+				mg.merge(dst.Index(j), src.Index(i))
+			case mg.sourceDominant:
+				deepCopy(dst.Index(j), src.Index(i))
+			}
+			if deep {
+				before[j] = append(before[j], pending...)
+				pending = nil
+			}
+			continue
+		}
+		c := reflect.New(src.Type().Elem())
+		deepCopy(c.Elem(), src.Index(i))
+		mg.filter(c)
+		pending = append(pending, c.Elem())
+	}
+
+	merged := reflect.MakeSlice(dst.Type(), 0, dst.Len()+src.Len())
+	for i := 0; i < dst.Len(); i++ {
+		merged = reflect.Append(merged, before[i]...)
+		merged = reflect.Append(merged, dst.Index(i))
+	}
+	merged = reflect.Append(merged, pending...)
+
+	dst.Set(merged)
+}
+
+func containsString(list reflect.Value, s string) bool {
+	for i := 0; i < list.Len(); i++ {
+		if list.Index(i).String() == s {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeProperties adds the properties of source to target, replacing the
+// ones target already has if sourceDominant.
+func mergeProperties(target, source *Properties, sourceDominant bool) {
+	if target.Fields == nil {
+		target.Fields = make(map[string]string, len(source.Fields))
+	}
+
+	order := target.Keys()
+	for _, key := range source.Keys() {
+		if _, ok := target.Fields[key]; !ok {
+			order = append(order, key)
+		} else if !sourceDominant {
+			continue
+		}
+		target.Fields[key] = source.Fields[key]
+	}
+	target.order = order
+}
+
+// Attributes controlling how configuration elements are merged.
+const (
+	combineChildren = "combine.children"
+	combineSelf     = "combine.self"
+)
+
+// mergeDOM merges the recessive configuration into the dominant one. Child
+// elements are merged by name and position unless the dominant element has
+// combine.children="append", in which case the recessive children are added
+// after its own, or combine.self="override", in which case the recessive
+// element is ignored.
+func mergeDOM(dominant, recessive *DOM) {
+	if domAttr(dominant, combineSelf) == "override" {
+		return
+	}
+
+	if dominant.Value == "" && len(dominant.Children) == 0 {
+		dominant.Value = recessive.Value
+	}
+	for _, attr := range recessive.Attrs {
+		if domAttr(dominant, attr.Name.Local) == "" && attr.Name.Local != combineSelf && attr.Name.Local != combineChildren {
+			dominant.Attrs = append(dominant.Attrs, attr)
+		}
+	}
+
+	if domAttr(dominant, combineChildren) == "append" {
+		for i := range recessive.Children {
+			dominant.Children = append(dominant.Children, *recessive.Children[i].Clone())
+		}
+		return
+	}
+
+	// the n-th recessive child of a name merges into the n-th dominant child
+	// of that name; recessive children whose name the dominant element does
+	// not use are added
+	used := make(map[string]int)
+	var added []DOM
+	for i := range recessive.Children {
+		child := &recessive.Children[i]
+		name := child.XMLName.Local
+
+		var matches []int
+		for j := range dominant.Children {
+			if dominant.Children[j].XMLName.Local == name {
+				matches = append(matches, j)
+			}
+		}
+		if len(matches) == 0 {
+			added = append(added, *child.Clone())
+			continue
+		}
+		if n := used[name]; n < len(matches) {
+			mergeDOM(&dominant.Children[matches[n]], child)
+			used[name]++
+		}
+	}
+	dominant.Children = append(dominant.Children, added...)
+}
+
+func domAttr(d *DOM, name string) string {
+	for _, attr := range d.Attrs {
+		if attr.Name.Space == "" && attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// Clone returns a deep copy of d.
+func (d *DOM) Clone() *DOM {
+	if d == nil {
+		return nil
+	}
+
+	var c DOM
+	deepCopy(reflect.ValueOf(&c).Elem(), reflect.ValueOf(d).Elem())
+	return &c
+}
@@ -0,0 +1,368 @@
+package pom
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Conflict is an element or value changed differently on both sides of a
+// three-way merge.
+type Conflict struct {
+	// Path locates the conflict like the Path of a Change.
+	Path string
+	// Base, Ours and Theirs are the values on each side, rendered as XML for
+	// elements. An empty value means the side does not have it.
+	Base   string
+	Ours   string
+	Theirs string
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s: ours %s, theirs %s, base %s", c.Path, conflictValue(c.Ours), conflictValue(c.Theirs), conflictValue(c.Base))
+}
+
+func conflictValue(v string) string {
+	if v == "" {
+		return "(none)"
+	}
+	return fmt.Sprintf("%q", v)
+}
+
+// Merge merges the changes made to base by ours and theirs. Dependencies,
+// plugins, executions, repositories, profiles and the other lists with an
+// identity are merged by key, properties by name, and modules as a set, so
+// edits to different elements never conflict, wherever they are in the
+// document.
+//
+// Where both sides changed the same value differently the result keeps ours,
+// the conflict is returned, and a comment describing it is added to the
+// enclosing element so that it shows when the result is written. A nil model
+// is the same as an empty one.
+func Merge(base, ours, theirs *Model) (*Model, []Conflict) {
+	if base == nil {
+		base = New()
+	}
+	if ours == nil {
+		ours = New()
+	}
+	if theirs == nil {
+		theirs = New()
+	}
+
+	result := ours.Clone()
+	m := &merge3{}
+	m.value(diffContext{path: "/project", element: "project"}, reflect.ValueOf(result).Elem(), reflect.ValueOf(base).Elem(), reflect.ValueOf(theirs).Elem(), reflect.Value{})
+	return result, m.conflicts
+}
+
+// merge3 merges the changes from base to theirs into dst, which starts out
+// as ours. owner is the closest element with a comment, where conflicts are
+// described.
+type merge3 struct {
+	conflicts []Conflict
+}
+
+// same reports whether a and b have the same content, regardless of order
+// and comments.
+func same(a, b reflect.Value) bool {
+	d := &differ{}
+	d.value(diffContext{}, a, b)
+	return len(d.changes) == 0
+}
+
+func (m *merge3) conflict(ctx diffContext, owner reflect.Value, base, ours, theirs string) {
+	c := Conflict{Path: ctx.path, Base: base, Ours: ours, Theirs: theirs}
+	m.conflicts = append(m.conflicts, c)
+
+	if !owner.IsValid() {
+		return
+	}
+	comment := owner.FieldByName("Comment")
+	note := " CONFLICT " + strings.ReplaceAll(c.String(), "--", "- -") + " "
+	if comment.String() != "" {
+		note = comment.String() + "\n" + note
+	}
+	comment.SetString(note)
+}
+
+// render returns v as it would be written in the document, for conflicts.
+func render(ctx diffContext, v reflect.Value) string {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct && v.Kind() != reflect.Slice {
+		return fmt.Sprint(v)
+	}
+
+	name := ctx.path[strings.LastIndexByte(ctx.path, '/')+1:]
+	name, _, _ = strings.Cut(name, "[")
+
+	var buf bytes.Buffer
+	if err := xml.NewEncoder(&buf).EncodeElement(v.Addr().Interface(), xml.StartElement{Name: xml.Name{Local: name}}); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+func (m *merge3) value(ctx diffContext, dst, base, theirs, owner reflect.Value) {
+	switch dst.Kind() {
+	case reflect.String, reflect.Bool:
+		m.whole(ctx, dst, base, theirs, owner)
+	case reflect.Pointer:
+		m.pointer(ctx, dst, base, theirs, owner)
+	case reflect.Struct:
+		m.fields(ctx, dst, base, theirs, owner)
+	case reflect.Slice:
+		m.slice(ctx, dst, base, theirs, owner)
+	}
+}
+
+// whole merges dst as a single value: the side that changed it wins, and
+// both changing it differently is a conflict.
+func (m *merge3) whole(ctx diffContext, dst, base, theirs, owner reflect.Value) {
+	switch {
+	case same(dst, theirs) || same(base, theirs):
+	case same(base, dst):
+		deepCopy(dst, theirs)
+	default:
+		m.conflict(ctx, owner, render(ctx, base), render(ctx, dst), render(ctx, theirs))
+	}
+}
+
+func (m *merge3) pointer(ctx diffContext, dst, base, theirs, owner reflect.Value) {
+	switch {
+	case same(dst, theirs) || same(base, theirs):
+		return
+	case same(base, dst):
+		dst.Set(reflect.Zero(dst.Type()))
+		deepCopy(dst, theirs)
+		return
+	}
+
+	switch dst.Interface().(type) {
+	case *Properties:
+		m.properties(ctx, dst, base, theirs)
+		return
+	case *DOM:
+		m.conflict(ctx, owner, render(ctx, base), render(ctx, dst), render(ctx, theirs))
+		return
+	}
+
+	if dst.IsNil() || theirs.IsNil() {
+		if !isListWrapper(dst.Type().Elem()) {
+			// removed on one side, changed on the other
+			m.conflict(ctx, owner, render(ctx, base), render(ctx, dst), render(ctx, theirs))
+			return
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+	}
+
+	elem := func(v reflect.Value) reflect.Value {
+		if v.IsNil() {
+			return reflect.New(v.Type().Elem()).Elem()
+		}
+		return v.Elem()
+	}
+	m.value(ctx, dst.Elem(), elem(base), elem(theirs), owner)
+}
+
+func (m *merge3) fields(ctx diffContext, dst, base, theirs, owner reflect.Value) {
+	t := dst.Type()
+	if f, ok := t.FieldByName("Comment"); ok && f.Tag.Get("xml") == ",comment" {
+		owner = dst
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if isEmbedded(f) {
+			m.fields(ctx, dst.Field(i), base.Field(i), theirs.Field(i), owner)
+			continue
+		}
+		name, ok := fieldName(f)
+		if !ok {
+			continue
+		}
+		m.value(ctx.child(name, f.Type.Kind() != reflect.String), dst.Field(i), base.Field(i), theirs.Field(i), owner)
+	}
+}
+
+func (m *merge3) slice(ctx diffContext, dst, base, theirs, owner reflect.Value) {
+	if dst.Type().Elem().Kind() == reflect.String {
+		m.stringSet(dst, base, theirs)
+		return
+	}
+
+	keyed := false
+	for _, list := range []reflect.Value{dst, base, theirs} {
+		if list.Len() > 0 {
+			keyed = diffKey(list.Index(0)) != ""
+			break
+		}
+	}
+	if !keyed || dst.Type().Elem() == reflect.TypeOf(xml.Attr{}) {
+		m.whole(ctx, dst, base, theirs, owner)
+		return
+	}
+
+	name := ctx.path[strings.LastIndexByte(ctx.path, '/')+1:]
+	_, baseItems := diffItems(base)
+	ourKeys, ourItems := diffItems(dst)
+	theirKeys, theirItems := diffItems(theirs)
+
+	var keys []string
+	items := make(map[string]reflect.Value)
+
+	for _, key := range ourKeys {
+		o := ourItems[key]
+		b, inBase := baseItems[key]
+		t, inTheirs := theirItems[key]
+		itemCtx := ctx.item(itemName(name, o), key, o)
+
+		switch {
+		case inBase && !inTheirs:
+			if same(b, o) {
+				continue
+			}
+			m.conflict(itemCtx, itemOwner(o, owner), render(itemCtx, b), render(itemCtx, o), "")
+		case inTheirs:
+			if !inBase {
+				b = reflect.New(o.Type()).Elem()
+			}
+			m.value(itemCtx, o, b, t, itemOwner(o, owner))
+		}
+		keys = append(keys, key)
+		items[key] = o
+	}
+
+	for i, key := range theirKeys {
+		if _, ok := ourItems[key]; ok {
+			continue
+		}
+		t := theirItems[key]
+		itemCtx := ctx.item(itemName(name, t), key, t)
+
+		if b, inBase := baseItems[key]; inBase {
+			if !same(b, t) {
+				m.conflict(itemCtx, owner, render(itemCtx, b), "", render(itemCtx, t))
+			}
+			continue
+		}
+
+		// place the new element after the one preceding it in theirs
+		at := 0
+		for j := i - 1; j >= 0; j-- {
+			if k := indexOf(keys, theirKeys[j]); k >= 0 {
+				at = k + 1
+				break
+			}
+		}
+		keys = append(keys[:at], append([]string{key}, keys[at:]...)...)
+		items[key] = t
+	}
+
+	merged := reflect.MakeSlice(dst.Type(), len(keys), len(keys))
+	for i, key := range keys {
+		deepCopy(merged.Index(i), items[key])
+	}
+	dst.Set(merged)
+}
+
+// itemOwner returns the element of a list if it can hold a comment.
+func itemOwner(item, owner reflect.Value) reflect.Value {
+	if f, ok := item.Type().FieldByName("Comment"); ok && f.Tag.Get("xml") == ",comment" {
+		return item
+	}
+	return owner
+}
+
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// stringSet merges lists of values like modules as sets: values removed by
+// theirs are removed and values added by theirs are appended.
+func (m *merge3) stringSet(dst, base, theirs reflect.Value) {
+	merged := reflect.MakeSlice(dst.Type(), 0, dst.Len()+theirs.Len())
+	for i := 0; i < dst.Len(); i++ {
+		s := dst.Index(i).String()
+		if containsString(base, s) && !containsString(theirs, s) {
+			continue
+		}
+		merged = reflect.Append(merged, dst.Index(i))
+	}
+	for i := 0; i < theirs.Len(); i++ {
+		s := theirs.Index(i).String()
+		if !containsString(base, s) && !containsString(merged, s) {
+			merged = reflect.Append(merged, theirs.Index(i))
+		}
+	}
+	if merged.Len() == 0 && dst.Len() == 0 {
+		return
+	}
+	dst.Set(merged)
+}
+
+// properties merges properties by name.
+func (m *merge3) properties(ctx diffContext, dst, base, theirs reflect.Value) {
+	props := func(v reflect.Value) *Properties {
+		if v.IsNil() {
+			return &Properties{}
+		}
+		return v.Interface().(*Properties)
+	}
+
+	if dst.IsNil() {
+		dst.Set(reflect.ValueOf(&Properties{}))
+	}
+	o, b, t := props(dst), props(base), props(theirs)
+	if o.Fields == nil {
+		o.Fields = make(map[string]string)
+	}
+	owner := dst.Elem()
+
+	keys := o.Keys()
+	for _, key := range t.Keys() {
+		if _, ok := o.Fields[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	var order []string
+	for _, key := range keys {
+		ov, inOurs := o.Fields[key]
+		bv, inBase := b.Fields[key]
+		tv, inTheirs := t.Fields[key]
+		propCtx := propertyContext(ctx, key)
+
+		switch {
+		case inOurs == inTheirs && ov == tv, inBase == inTheirs && bv == tv:
+			// theirs did not change it, or made the same change
+		case inBase == inOurs && bv == ov:
+			if inTheirs {
+				o.Fields[key] = tv
+			} else {
+				delete(o.Fields, key)
+			}
+		default:
+			m.conflict(propCtx, owner, bv, ov, tv)
+		}
+
+		if _, ok := o.Fields[key]; ok {
+			order = append(order, key)
+		}
+	}
+	o.order = order
+}
@@ -0,0 +1,109 @@
+package pom_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+func readMergeTriple(t *testing.T) (*pom.Model, *pom.Model, *pom.Model) {
+	t.Helper()
+
+	var models []*pom.Model
+	for _, name := range []string{"base.xml", "ours.xml", "theirs.xml"} {
+		m, err := pom.ReadFile(filepath.Join("testdata", "merge", name))
+		if err != nil {
+			t.Fatalf("Expected no errors reading %s, but found: %s", name, err.Error())
+		}
+		models = append(models, m)
+	}
+	return models[0], models[1], models[2]
+}
+
+func TestMerge(t *testing.T) {
+	t.Run("Should merge edits to different elements", func(t *testing.T) {
+		base, ours, theirs := readMergeTriple(t)
+
+		merged, _ := pom.Merge(base, ours, theirs)
+
+		if modules := strings.Join(merged.Modules.Module, ","); modules != "core,api,web" {
+			t.Errorf("Expected modules core,api,web, but found: %s", modules)
+		}
+
+		props := merged.Properties
+		if props.Fields["slf4j.version"] != "2.0.9" || props.Fields["jackson.version"] != "2.16.1" {
+			t.Errorf("Expected the property changes of both sides, but found: %v", props.Fields)
+		}
+		if _, ok := props.Fields["legacy"]; ok {
+			t.Errorf("Expected the legacy property to be removed")
+		}
+
+		var deps []string
+		for _, d := range merged.Dependencies.Dependency {
+			deps = append(deps, d.ArtifactId)
+		}
+		if got := strings.Join(deps, ","); got != "slf4j-api,jackson-databind,junit-jupiter,guava" {
+			t.Errorf("Expected slf4j-api,jackson-databind,junit-jupiter,guava, but found: %s", got)
+		}
+
+		surefire := findPlugin(merged, "maven-surefire-plugin")
+		if surefire.Version != "3.2.5" || surefire.Executions.Execution[0].Phase != "verify" {
+			t.Errorf("Expected the version of ours and the phase of theirs, but found: %s %s", surefire.Version, surefire.Executions.Execution[0].Phase)
+		}
+	})
+
+	t.Run("Should report conflicting edits and keep ours", func(t *testing.T) {
+		base, ours, theirs := readMergeTriple(t)
+
+		merged, conflicts := pom.Merge(base, ours, theirs)
+
+		if len(conflicts) != 1 {
+			t.Fatalf("Expected 1 conflict, but found: %v", conflicts)
+		}
+		c := conflicts[0]
+		if c.Path != "/project/dependencies/dependency[org.junit.jupiter:junit-jupiter:jar:]/version" ||
+			c.Base != "5.10.1" || c.Ours != "5.10.2" || c.Theirs != "5.10.3" {
+			t.Errorf("Expected the junit-jupiter version to conflict, but found: %s", c)
+		}
+
+		junit := findDependency(merged, "junit-jupiter")
+		if junit.Version != "5.10.2" || !strings.Contains(junit.Comment, "CONFLICT") {
+			t.Errorf("Expected ours with a conflict comment, but found: %s %q", junit.Version, junit.Comment)
+		}
+	})
+
+	t.Run("Should report an element changed on one side and removed on the other", func(t *testing.T) {
+		base, _, theirs := readMergeTriple(t)
+		ours := base.Clone()
+		ours.Dependencies.Dependency[1].Version = "2.16.0"
+
+		merged, conflicts := pom.Merge(base, ours, theirs)
+
+		if len(conflicts) != 1 || conflicts[0].Theirs != "" || !strings.Contains(conflicts[0].Ours, "2.16.0") {
+			t.Fatalf("Expected commons-io to conflict, but found: %v", conflicts)
+		}
+		if findDependency(merged, "commons-io") == nil {
+			t.Errorf("Expected ours to keep commons-io")
+		}
+	})
+
+	t.Run("Should take the changes of one side", func(t *testing.T) {
+		base, ours, _ := readMergeTriple(t)
+
+		merged, conflicts := pom.Merge(base, base, ours)
+		if len(conflicts) != 0 {
+			t.Fatalf("Expected no conflicts, but found: %v", conflicts)
+		}
+		if diff := pom.Diff(ours, merged); len(diff) != 0 {
+			t.Errorf("Expected the merge to equal ours, but found: %s", diff)
+		}
+
+		var buf bytes.Buffer
+		if err := merged.Write(&buf, nil); err != nil {
+			t.Errorf("Expected no errors writing the merge, but found: %s", err.Error())
+		}
+	})
+}
@@ -0,0 +1,218 @@
+// Package mvn reads the .mvn directory of Maven projects, which sits next to
+// the root POM of a multi-module project and configures the builds of all
+// its projects:
+//
+//	.mvn/maven.config                       command line options of Maven
+//	.mvn/jvm.config                         options of the JVM running Maven
+//	.mvn/extensions.xml                     core extensions of Maven
+//	.mvn/wrapper/maven-wrapper.properties   the Maven version of mvnw
+//
+// The options of maven.config apply to the effective models of the projects
+// through pom.MavenConfig.Apply, and select the projects of their reactor
+// through pom.Reactor.Selected.
+package mvn
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/obscurelyme/encoding/pom"
+	"github.com/obscurelyme/encoding/properties"
+)
+
+// Config is the content of a .mvn directory. Each file is nil when the
+// directory does not have it.
+type Config struct {
+	// Root is the directory of the .mvn directory, the root of the
+	// multi-module project.
+	Root       string
+	Maven      *pom.MavenConfig
+	JVM        *JVMConfig
+	Extensions *Extensions
+	Wrapper    *Wrapper
+}
+
+// Find reads the .mvn directory of the project in dir, from the root found
+// by pom.FindProjectRoot. It returns nil if there is no .mvn directory.
+func Find(dir string) (*Config, error) {
+	root, err := pom.FindProjectRoot(dir)
+	if err != nil || root == "" {
+		return nil, err
+	}
+	return Read(root)
+}
+
+// Read reads the .mvn directory in root.
+func Read(root string) (*Config, error) {
+	c := &Config{Root: root}
+	dir := filepath.Join(root, ".mvn")
+
+	var err error
+	if c.Maven, err = pom.ReadMavenConfig(filepath.Join(dir, "maven.config")); missing(err) {
+		c.Maven = nil
+	} else if err != nil {
+		return nil, err
+	}
+	if c.JVM, err = ReadJVMConfig(filepath.Join(dir, "jvm.config")); missing(err) {
+		c.JVM = nil
+	} else if err != nil {
+		return nil, err
+	}
+	if c.Extensions, err = ReadExtensions(filepath.Join(dir, "extensions.xml")); missing(err) {
+		c.Extensions = nil
+	} else if err != nil {
+		return nil, err
+	}
+	if c.Wrapper, err = ReadWrapper(filepath.Join(dir, "wrapper", "maven-wrapper.properties")); missing(err) {
+		c.Wrapper = nil
+	} else if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func missing(err error) bool {
+	return errors.Is(err, fs.ErrNotExist)
+}
+
+// JVMConfig is a .mvn/jvm.config file, holding the options of the JVM that
+// runs Maven.
+type JVMConfig struct {
+	// Path is the location of the file.
+	Path string
+	// Args are the options, split at whitespace like the mvn script does.
+	// Lines starting with # are comments.
+	Args []string
+	// SystemProperties are the system properties set by -D options.
+	SystemProperties map[string]string
+}
+
+// ReadJVMConfig reads the jvm.config file at path.
+func ReadJVMConfig(path string) (*JVMConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &JVMConfig{Path: path, SystemProperties: make(map[string]string)}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		c.Args = append(c.Args, strings.Fields(line)...)
+	}
+	for _, arg := range c.Args {
+		if property, ok := strings.CutPrefix(arg, "-D"); ok {
+			name, value, _ := strings.Cut(property, "=")
+			c.SystemProperties[name] = value
+		}
+	}
+	return c, nil
+}
+
+// Extensions is a .mvn/extensions.xml file, listing the core extensions
+// loaded by Maven before it reads the POMs, like those providing
+// packagings or lifecycle participants.
+type Extensions struct {
+	XMLName   xml.Name    `xml:"extensions"`
+	Extension []Extension `xml:"extension"`
+}
+
+// Extension is a core extension.
+type Extension struct {
+	GroupId    string `xml:"groupId"`
+	ArtifactId string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	// ClassLoadingStrategy is self-first, parent-first or plugin, self-first
+	// by default.
+	ClassLoadingStrategy string `xml:"classLoadingStrategy,omitempty"`
+	// Configuration is the configuration of the extension, which Maven 4
+	// allows.
+	Configuration *pom.DOM `xml:"configuration,omitempty"`
+}
+
+// String returns the groupId:artifactId:version of e.
+func (e *Extension) String() string {
+	return e.GroupId + ":" + e.ArtifactId + ":" + e.Version
+}
+
+// ReadExtensions reads the extensions.xml file at path.
+func ReadExtensions(path string) (*Extensions, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var e Extensions
+	if err := xml.NewDecoder(f).Decode(&e); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &e, nil
+}
+
+// Wrapper is a .mvn/wrapper/maven-wrapper.properties file, configuring the
+// Maven distribution that mvnw downloads and runs.
+type Wrapper struct {
+	// Path is the location of the file.
+	Path string
+	// DistributionURL is the URL of the Maven distribution.
+	DistributionURL string
+	// DistributionSha256Sum is the checksum of the distribution, if
+	// verified.
+	DistributionSha256Sum string
+	// DistributionType is only-script, script, bin or source, how mvnw gets
+	// the distribution; bin by default.
+	DistributionType string
+	// WrapperURL and WrapperSha256Sum are the URL and checksum of the
+	// maven-wrapper.jar of the bin and source types.
+	WrapperURL       string
+	WrapperSha256Sum string
+	// Properties are all the properties of the file.
+	Properties *properties.Properties
+}
+
+// ReadWrapper reads the maven-wrapper.properties file at path.
+func ReadWrapper(path string) (*Wrapper, error) {
+	p, err := properties.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	get := func(key string) string {
+		value, _ := p.Get(key)
+		return value
+	}
+	return &Wrapper{
+		Path:                  path,
+		DistributionURL:       get("distributionUrl"),
+		DistributionSha256Sum: get("distributionSha256Sum"),
+		DistributionType:      get("distributionType"),
+		WrapperURL:            get("wrapperUrl"),
+		WrapperSha256Sum:      get("wrapperSha256Sum"),
+		Properties:            p,
+	}, nil
+}
+
+// MavenVersion returns the version of the Maven distribution of w, like
+// 3.9.9 for .../apache-maven/3.9.9/apache-maven-3.9.9-bin.zip, or an empty
+// string if its URL has none.
+func (w *Wrapper) MavenVersion() string {
+	name := path.Base(w.DistributionURL)
+	version, ok := strings.CutPrefix(name, "apache-maven-")
+	if !ok {
+		return ""
+	}
+	for _, suffix := range []string{"-bin.zip", "-bin.tar.gz", "-src.zip", "-src.tar.gz"} {
+		if v, ok := strings.CutSuffix(version, suffix); ok {
+			return v
+		}
+	}
+	return ""
+}
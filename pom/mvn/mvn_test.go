@@ -0,0 +1,80 @@
+package mvn_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+	"github.com/obscurelyme/encoding/pom/mvn"
+)
+
+func TestFind(t *testing.T) {
+	root, _ := filepath.Abs(filepath.Join("testdata", "project"))
+	c, err := mvn.Find(filepath.Join("testdata", "project", "app"))
+	if err != nil || c == nil {
+		t.Fatalf("Expected the .mvn directory of the project, but found: %v %v", c, err)
+	}
+
+	t.Run("Should find the root of the project", func(t *testing.T) {
+		if c.Root != root {
+			t.Errorf("Expected %s, but found: %s", root, c.Root)
+		}
+	})
+
+	t.Run("Should read maven.config", func(t *testing.T) {
+		if c.Maven == nil || c.Maven.Properties["revision"] != "1.2.0" || strings.Join(c.Maven.ActiveProfiles, ",") != "ci" || strings.Join(c.Maven.Projects, ",") != "app" {
+			t.Errorf("Expected the options of maven.config, but found: %v", c.Maven)
+		}
+	})
+
+	t.Run("Should read jvm.config", func(t *testing.T) {
+		expected := "-Xmx2g -XX:+UseG1GC --add-opens java.base/java.lang=ALL-UNNAMED -Dfile.encoding=UTF-8"
+		if c.JVM == nil || strings.Join(c.JVM.Args, " ") != expected || c.JVM.SystemProperties["file.encoding"] != "UTF-8" {
+			t.Errorf("Expected the options of jvm.config, but found: %v", c.JVM)
+		}
+	})
+
+	t.Run("Should read extensions.xml", func(t *testing.T) {
+		if c.Extensions == nil || len(c.Extensions.Extension) != 2 {
+			t.Fatalf("Expected 2 extensions, but found: %v", c.Extensions)
+		}
+		if e := c.Extensions.Extension[1]; e.String() != "org.apache.maven.extensions:maven-build-cache-extension:1.2.0" || e.ClassLoadingStrategy != "parent-first" {
+			t.Errorf("Expected the build cache extension, but found: %v", e)
+		}
+	})
+
+	t.Run("Should read the wrapper properties", func(t *testing.T) {
+		if c.Wrapper == nil || c.Wrapper.DistributionType != "only-script" || c.Wrapper.MavenVersion() != "3.9.9" {
+			t.Errorf("Expected the wrapper of Maven 3.9.9, but found: %v", c.Wrapper)
+		}
+		if v, _ := c.Wrapper.Properties.Get("wrapperVersion"); v != "3.3.2" {
+			t.Errorf("Expected the wrapper version 3.3.2, but found: %s", v)
+		}
+	})
+
+	t.Run("Should apply maven.config to the reactor and the effective models", func(t *testing.T) {
+		r, err := pom.LoadReactor(c.Root)
+		if err != nil {
+			t.Fatalf("Expected no errors loading the reactor, but found: %s", err.Error())
+		}
+		projects, err := r.Selected()
+		if err != nil || len(projects) != 1 || projects[0].Model.ArtifactId != "app" {
+			t.Fatalf("Expected the app project, but found: %v %v", projects, err)
+		}
+
+		m, err := projects[0].Model.Effective(c.Maven.Apply(&pom.EffectiveOptions{Dir: filepath.Dir(projects[0].Path)}))
+		if err != nil || m.Version != "1.2.0" {
+			t.Errorf("Expected the version of the revision property, but found: %v %v", m, err)
+		}
+	})
+}
+
+func TestRead(t *testing.T) {
+	t.Run("Should leave missing files nil", func(t *testing.T) {
+		c, err := mvn.Read(t.TempDir())
+		if err != nil || c.Maven != nil || c.JVM != nil || c.Extensions != nil || c.Wrapper != nil {
+			t.Errorf("Expected no files, but found: %v %v", c, err)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package pom
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// VersionPart is one of the numbers of a version like 1.2.3.
+type VersionPart int
+
+const (
+	Major VersionPart = iota
+	Minor
+	// Incremental is the third number, as Maven calls it.
+	Incremental
+)
+
+const snapshotSuffix = "-SNAPSHOT"
+
+// IncrementVersion returns version with the number part incremented and the
+// numbers after it reset to 0, like 1.3.0 for the Minor part of 1.2.3. A
+// -SNAPSHOT suffix is kept and other qualifiers are dropped.
+func IncrementVersion(version string, part VersionPart) (string, error) {
+	numbers, err := versionNumbers(version)
+	if err != nil {
+		return "", err
+	}
+
+	for len(numbers) <= int(part) {
+		numbers = append(numbers, 0)
+	}
+	numbers[part]++
+	for i := int(part) + 1; i < len(numbers); i++ {
+		numbers[i] = 0
+	}
+
+	next := joinNumbers(numbers)
+	if strings.HasSuffix(version, snapshotSuffix) {
+		next += snapshotSuffix
+	}
+	return next, nil
+}
+
+// ReleaseVersion returns version without its -SNAPSHOT suffix.
+func ReleaseVersion(version string) string {
+	return strings.TrimSuffix(version, snapshotSuffix)
+}
+
+// versionNumbers returns the dot separated numbers version starts with.
+func versionNumbers(version string) ([]int, error) {
+	end := 0
+	for end < len(version) && (version[end] >= '0' && version[end] <= '9' || version[end] == '.' && end > 0) {
+		end++
+	}
+	prefix := strings.TrimRight(version[:end], ".")
+	if prefix == "" {
+		return nil, fmt.Errorf("pom: version %q does not start with a number", version)
+	}
+
+	var numbers []int
+	for _, s := range strings.Split(prefix, ".") {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("pom: invalid version %q: %w", version, err)
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers, nil
+}
+
+func joinNumbers(numbers []int) string {
+	s := make([]string, len(numbers))
+	for i, n := range numbers {
+		s[i] = strconv.Itoa(n)
+	}
+	return strings.Join(s, ".")
+}
@@ -0,0 +1,284 @@
+package pom
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MojoExecution is a goal of a plugin run by a build.
+type MojoExecution struct {
+	GroupId    string
+	ArtifactId string
+	Version    string
+	Goal       string
+	// ExecutionId is the id of the execution, default-<goal> for the goals
+	// bound by the lifecycle of the packaging.
+	ExecutionId string
+	Phase       string
+	// Configuration is the configuration of the execution merged with that
+	// of its plugin, or nil.
+	Configuration *DOM
+}
+
+// String returns the groupId:artifactId:version:goal (executionId) of e.
+func (e *MojoExecution) String() string {
+	return e.GroupId + ":" + e.ArtifactId + ":" + e.Version + ":" + e.Goal + " (" + e.ExecutionId + ")"
+}
+
+// DefaultPhaseResolver finds the phase a goal runs in when its execution
+// does not set one, given by the descriptor of its plugin.
+type DefaultPhaseResolver interface {
+	// DefaultPhase returns the default phase of the goal of plugin, or an
+	// empty string when the goal has none and does not run unless its
+	// execution sets a phase.
+	DefaultPhase(plugin *Plugin, goal string) (string, error)
+}
+
+// PlanOptions configures Plan.
+type PlanOptions struct {
+	// MavenVersion selects the versions of the plugins bound by the
+	// lifecycles, like LifecycleBindings.
+	MavenVersion string
+	// DefaultPhases finds the default phases of goals. When nil, those of
+	// the goals of common plugins are known and other goals without a
+	// phase do not run.
+	DefaultPhases DefaultPhaseResolver
+}
+
+// knownDefaultPhases are the default phases of the goals of common plugins,
+// by groupId:artifactId:goal.
+var knownDefaultPhases = map[string]string{
+	"org.apache.maven.plugins:maven-clean-plugin:clean":                  "clean",
+	"org.apache.maven.plugins:maven-resources-plugin:resources":          "process-resources",
+	"org.apache.maven.plugins:maven-resources-plugin:testResources":      "process-test-resources",
+	"org.apache.maven.plugins:maven-compiler-plugin:compile":             "compile",
+	"org.apache.maven.plugins:maven-compiler-plugin:testCompile":         "test-compile",
+	"org.apache.maven.plugins:maven-surefire-plugin:test":                "test",
+	"org.apache.maven.plugins:maven-failsafe-plugin:integration-test":    "integration-test",
+	"org.apache.maven.plugins:maven-failsafe-plugin:verify":              "verify",
+	"org.apache.maven.plugins:maven-jar-plugin:jar":                      "package",
+	"org.apache.maven.plugins:maven-jar-plugin:test-jar":                 "package",
+	"org.apache.maven.plugins:maven-war-plugin:war":                      "package",
+	"org.apache.maven.plugins:maven-ear-plugin:ear":                      "package",
+	"org.apache.maven.plugins:maven-ejb-plugin:ejb":                      "package",
+	"org.apache.maven.plugins:maven-rar-plugin:rar":                      "package",
+	"org.apache.maven.plugins:maven-source-plugin:jar":                   "package",
+	"org.apache.maven.plugins:maven-source-plugin:jar-no-fork":           "package",
+	"org.apache.maven.plugins:maven-source-plugin:test-jar":              "package",
+	"org.apache.maven.plugins:maven-source-plugin:test-jar-no-fork":      "package",
+	"org.apache.maven.plugins:maven-javadoc-plugin:jar":                  "package",
+	"org.apache.maven.plugins:maven-javadoc-plugin:test-jar":             "package",
+	"org.apache.maven.plugins:maven-shade-plugin:shade":                  "package",
+	"org.apache.maven.plugins:maven-dependency-plugin:copy-dependencies": "process-sources",
+	"org.apache.maven.plugins:maven-enforcer-plugin:enforce":             "validate",
+	"org.apache.maven.plugins:maven-gpg-plugin:sign":                     "verify",
+	"org.apache.maven.plugins:maven-install-plugin:install":              "install",
+	"org.apache.maven.plugins:maven-deploy-plugin:deploy":                "deploy",
+	"org.apache.maven.plugins:maven-site-plugin:site":                    "site",
+	"org.apache.maven.plugins:maven-site-plugin:deploy":                  "site-deploy",
+	"org.apache.maven.plugins:maven-plugin-plugin:descriptor":            "process-classes",
+	"org.jacoco:jacoco-maven-plugin:prepare-agent":                       "initialize",
+	"org.jacoco:jacoco-maven-plugin:report":                              "verify",
+	"org.jacoco:jacoco-maven-plugin:check":                               "verify",
+	"org.codehaus.mojo:build-helper-maven-plugin:add-source":             "generate-sources",
+	"org.codehaus.mojo:build-helper-maven-plugin:add-test-source":        "generate-test-sources",
+	"org.codehaus.mojo:flatten-maven-plugin:flatten":                     "process-resources",
+}
+
+// plannedExecution is a MojoExecution with its order in its phase.
+type plannedExecution struct {
+	MojoExecution
+	phase int
+	// priority orders the executions of a phase, lowest first: the goals
+	// bound by the lifecycle come first, like in Maven, unless executions
+	// set a higher priority.
+	priority int
+}
+
+// Plan returns the mojo executions a build of m, an effective model, runs
+// up to phase, like mvn phase, in the order they run.
+//
+// The goals bound by the lifecycle of the packaging of m are merged with the
+// build plugins of m and their plugin management, the executions of m with
+// ids like default-compile configuring them. The goals bound by the
+// lifecycle run first in their phase, then those of the executions of m in
+// the order of their plugins, unless they set a priority, which Maven 4.1.0
+// models allow. Executions without a phase run in the default phase of their
+// goals. Executions and plugins not inherited by m are not
+// in its effective model, and so do not run.
+//
+// A nil opts is the same as empty PlanOptions.
+func (m *Model) Plan(phase string, opts *PlanOptions) ([]MojoExecution, error) {
+	if opts == nil {
+		opts = &PlanOptions{}
+	}
+	lifecycle := LifecycleOf(phase)
+	if lifecycle == nil {
+		return nil, fmt.Errorf("pom: unknown phase %s", phase)
+	}
+	phases := lifecycle.Phases[:slices.Index(lifecycle.Phases, phase)+1]
+
+	bindings, err := LifecycleBindings(m.Packaging, opts.MavenVersion)
+	if err != nil {
+		return nil, err
+	}
+	plugins, priorities := m.lifecyclePlugins(bindings)
+
+	var planned []plannedExecution
+	for i := range plugins {
+		p := &plugins[i]
+		if p.Executions == nil {
+			continue
+		}
+		for _, x := range p.Executions.Execution {
+			id := orDefault(x.Id, DefaultExecutionId)
+			for _, g := range executionGoals(&x) {
+				goalPhase := x.Phase
+				if goalPhase == "" {
+					if goalPhase, err = defaultPhase(opts.DefaultPhases, p, g); err != nil {
+						return nil, err
+					}
+				}
+				at := slices.Index(phases, goalPhase)
+				if at < 0 {
+					continue
+				}
+				priority := priorities[p.Key()+":"+id+":"+g]
+				if n, err := strconv.Atoi(strings.TrimSpace(x.Priority)); err == nil {
+					priority = -n
+				}
+				planned = append(planned, plannedExecution{
+					MojoExecution: MojoExecution{
+						GroupId:       orDefault(p.GroupId, DefaultPluginGroupId),
+						ArtifactId:    p.ArtifactId,
+						Version:       p.Version,
+						Goal:          g,
+						ExecutionId:   id,
+						Phase:         goalPhase,
+						Configuration: executionConfiguration(p, &x),
+					},
+					phase:    at,
+					priority: priority,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(planned, func(i, j int) bool {
+		a, b := &planned[i], &planned[j]
+		if a.phase != b.phase {
+			return a.phase < b.phase
+		}
+		return a.priority < b.priority
+	})
+	executions := make([]MojoExecution, len(planned))
+	for i := range planned {
+		executions[i] = planned[i].MojoExecution
+	}
+	return executions, nil
+}
+
+func executionGoals(x *Execution) []string {
+	if x.Goals == nil {
+		return nil
+	}
+	return x.Goals.Goal
+}
+
+func defaultPhase(resolver DefaultPhaseResolver, p *Plugin, goal string) (string, error) {
+	if resolver != nil {
+		phase, err := resolver.DefaultPhase(p, goal)
+		if err != nil {
+			return "", fmt.Errorf("pom: cannot find the default phase of %s:%s: %w", p.Key(), goal, err)
+		}
+		return phase, nil
+	}
+	return knownDefaultPhases[p.Key()+":"+goal], nil
+}
+
+// executionConfiguration returns the configuration of x merged with that of
+// its plugin p, that of x taking precedence.
+func executionConfiguration(p *Plugin, x *Execution) *DOM {
+	switch {
+	case x.Configuration == nil && p.Configuration == nil:
+		return nil
+	case x.Configuration == nil:
+		return p.Configuration.Clone()
+	case p.Configuration == nil:
+		return x.Configuration.Clone()
+	}
+	c := x.Configuration.Clone()
+	mergeDOM(c, p.Configuration)
+	return c
+}
+
+// lifecyclePlugins returns the build plugins of m with the plugins of
+// bindings merged in, like Maven injects the lifecycle of the packaging.
+// The plugins of the lifecycle come first, in the order of their goals,
+// each after the plugins of m declared before it. The priorities of the
+// executions of the lifecycle, by groupId:artifactId:executionId:goal, are
+// negative to run them first in their phase.
+func (m *Model) lifecyclePlugins(bindings []Binding) ([]Plugin, map[string]int) {
+	var defaults []Plugin
+	index := make(map[string]int)
+	priorities := make(map[string]int)
+	perPhase := make(map[string]int)
+	for _, b := range bindings {
+		perPhase[b.Phase]++
+	}
+	seen := make(map[string]int)
+	for _, b := range bindings {
+		key := pluginKey(b.GroupId, b.ArtifactId)
+		i, ok := index[key]
+		if !ok {
+			i = len(defaults)
+			index[key] = i
+			defaults = append(defaults, Plugin{GroupId: b.GroupId, ArtifactId: b.ArtifactId, Version: b.Version, Executions: &Executions{}})
+		}
+		id := "default-" + b.Goal
+		defaults[i].Executions.Execution = append(defaults[i].Executions.Execution, Execution{Id: id, Phase: b.Phase, Goals: &Goals{Goal: []string{b.Goal}}})
+		priorities[key+":"+id+":"+b.Goal] = seen[b.Phase] - perPhase[b.Phase]
+		seen[b.Phase]++
+	}
+
+	managed := make(map[string]*Plugin)
+	if m.Build != nil && m.Build.PluginManagement != nil && m.Build.PluginManagement.Plugins != nil {
+		for i := range m.Build.PluginManagement.Plugins.Plugin {
+			p := &m.Build.PluginManagement.Plugins.Plugin[i]
+			managed[p.Key()] = p
+		}
+	}
+	for i := range defaults {
+		if mp, ok := managed[defaults[i].Key()]; ok {
+			merger{sourceDominant: true}.mergeInto(&defaults[i], mp)
+		}
+	}
+
+	var declared []Plugin
+	if m.Build != nil && m.Build.Plugins != nil {
+		declared = m.Build.Plugins.Plugin
+	}
+	before := make(map[int][]Plugin)
+	var pending []Plugin
+	for i := range declared {
+		p := &declared[i]
+		j, ok := index[p.Key()]
+		if !ok {
+			pending = append(pending, *p)
+			continue
+		}
+		merger{sourceDominant: true}.mergeInto(&defaults[j], p)
+		before[j] = append(before[j], pending...)
+		pending = nil
+	}
+
+	var plugins []Plugin
+	for i := range defaults {
+		plugins = append(plugins, before[i]...)
+		plugins = append(plugins, defaults[i])
+	}
+	return append(plugins, pending...), priorities
+}
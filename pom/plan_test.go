@@ -0,0 +1,128 @@
+package pom_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+// goalPhases are the default phases of goals, by artifactId:goal.
+type goalPhases map[string]string
+
+func (g goalPhases) DefaultPhase(p *pom.Plugin, goal string) (string, error) {
+	return g[p.ArtifactId+":"+goal], nil
+}
+
+func TestPlan(t *testing.T) {
+	eff := effectiveCore(t, pom.EffectiveOptions{})
+	plan := func(t *testing.T, m *pom.Model, phase string, opts *pom.PlanOptions) []string {
+		t.Helper()
+
+		executions, err := m.Plan(phase, opts)
+		if err != nil {
+			t.Fatalf("Expected no errors planning %s, but found: %s", phase, err.Error())
+		}
+		var found []string
+		for i := range executions {
+			found = append(found, executions[i].Phase+" "+executions[i].String())
+		}
+		return found
+	}
+
+	t.Run("Should run the lifecycle up to the phase", func(t *testing.T) {
+		expected := []string{
+			"process-resources org.apache.maven.plugins:maven-resources-plugin:3.3.1:resources (default-resources)",
+			"compile org.apache.maven.plugins:maven-compiler-plugin:3.11.0:compile (default-compile)",
+			"process-test-resources org.apache.maven.plugins:maven-resources-plugin:3.3.1:testResources (default-testResources)",
+			"test-compile org.apache.maven.plugins:maven-compiler-plugin:3.11.0:testCompile (default-testCompile)",
+			"test org.apache.maven.plugins:maven-surefire-plugin:3.2.5:test (default-test)",
+			"test org.apache.maven.plugins:maven-surefire-plugin:3.2.5:test (integration)",
+			"package org.apache.maven.plugins:maven-jar-plugin:3.3.0:jar (default-jar)",
+		}
+		found := plan(t, eff, "verify", &pom.PlanOptions{MavenVersion: "3.9.6"})
+		if strings.Join(found, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("Expected:\n%s\nbut found:\n%s", strings.Join(expected, "\n"), strings.Join(found, "\n"))
+		}
+	})
+
+	t.Run("Should merge the configuration of the plugin", func(t *testing.T) {
+		executions, _ := eff.Plan("compile", nil)
+		c := executions[len(executions)-1].Configuration
+		if c == nil {
+			t.Fatalf("Expected the configuration of the compiler plugin")
+		}
+		var names []string
+		for _, child := range c.Children {
+			names = append(names, child.XMLName.Local)
+		}
+		if found := strings.Join(names, " "); !strings.Contains(found, "release") || !strings.Contains(found, "showWarnings") {
+			t.Errorf("Expected the managed and declared configuration, but found: %s", found)
+		}
+	})
+
+	t.Run("Should bind executions to phases and priorities", func(t *testing.T) {
+		m, err := pom.Read(strings.NewReader(`<project>
+  <modelVersion>4.1.0</modelVersion>
+  <groupId>org.example</groupId>
+  <artifactId>example</artifactId>
+  <version>1.0</version>
+  <packaging>pom</packaging>
+  <build>
+    <plugins>
+      <plugin>
+        <artifactId>maven-enforcer-plugin</artifactId>
+        <version>3.4.1</version>
+        <executions>
+          <execution>
+            <id>enforce</id>
+            <goals><goal>enforce</goal></goals>
+          </execution>
+        </executions>
+      </plugin>
+      <plugin>
+        <artifactId>maven-antrun-plugin</artifactId>
+        <version>3.1.0</version>
+        <executions>
+          <execution>
+            <id>unbound</id>
+            <goals><goal>run</goal></goals>
+          </execution>
+          <execution>
+            <id>first</id>
+            <phase>install</phase>
+            <priority>10</priority>
+            <goals><goal>run</goal></goals>
+          </execution>
+        </executions>
+      </plugin>
+    </plugins>
+  </build>
+</project>`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []string{
+			"validate org.apache.maven.plugins:maven-enforcer-plugin:3.4.1:enforce (enforce)",
+			"install org.apache.maven.plugins:maven-antrun-plugin:3.1.0:run (first)",
+			"install org.apache.maven.plugins:maven-install-plugin:3.1.4:install (default-install)",
+		}
+		found := plan(t, m, "install", &pom.PlanOptions{MavenVersion: "4.0.0"})
+		if strings.Join(found, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("Expected:\n%s\nbut found:\n%s", strings.Join(expected, "\n"), strings.Join(found, "\n"))
+		}
+	})
+
+	t.Run("Should find default phases with the resolver", func(t *testing.T) {
+		found := plan(t, eff, "test", &pom.PlanOptions{DefaultPhases: goalPhases{"maven-surefire-plugin:test": "process-test-classes"}})
+		if len(found) != 6 || !strings.HasPrefix(found[4], "process-test-classes ") || !strings.HasSuffix(found[4], "(integration)") {
+			t.Errorf("Expected the integration execution in process-test-classes, but found: %v", found)
+		}
+	})
+
+	t.Run("Should reject unknown phases", func(t *testing.T) {
+		if _, err := eff.Plan("compiled", nil); err == nil {
+			t.Errorf("Expected an error for an unknown phase")
+		}
+	})
+}
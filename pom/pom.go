@@ -3,12 +3,13 @@ package pom
 import (
 	"encoding/xml"
 	"io"
+	"sort"
 	"strings"
 )
 
 // Official Pom Schema https://maven.apache.org/xsd/maven-4.0.0.xsd
 type Model struct {
-	XMLName        xml.Name `xml:"project"`
+	XMLName xml.Name `xml:"project"`
 	// Declares to which version of project descriptor this POM conforms.
 	ModelVersion string `xml:"modelVersion,omitempty"`
 	// The location of the parent project, if one exists. Values from the parent project will be the default for this project if they are left unspecified. The location is given as a group ID, artifact ID and version.
@@ -45,6 +46,10 @@ type Model struct {
 	Prerequisites *Prerequisites `xml:"prerequisites,omitempty"`
 	// The modules (sometimes called subprojects) to build as a part of this project. Each module listed is a relative path to the directory containing the module. To be consistent with the way default urls are calculated from parent, it is recommended to have module names match artifact ids.
 	Modules *Modules `xml:"modules,omitempty"`
+	// The subprojects (formerly called modules) to build as a part of this project. Each subproject listed is a relative path to the directory containing the subproject.
+	//
+	// Since: 4.1.0
+	Subprojects *Subprojects `xml:"subprojects,omitempty"`
 	// Specification for the SCM used by the project, such as CVS, Subversion, etc.
 	Scm *Scm `xml:"scm,omitempty"`
 	// The project's issue management system information.
@@ -73,30 +78,63 @@ type Model struct {
 	Reporting *Reporting `xml:"reporting,omitempty"`
 	// A listing of project-local build profiles which will modify the build process when activated.
 	Profiles *Profiles `xml:"profiles,omitempty"`
+	// When children inherit from project's url, append path or not? Note: While the type of this field is <code>String</code> for technical reasons, the semantic type is actually <code>Boolean</code>.
+	//
+	// Default value is: <code>true</code>
+	ChildProjectUrlInheritAppendPath string `xml:"child.project.url.inherit.append.path,attr,omitempty"`
+	// Indicates that this project is the root project, located in the upper directory of the source tree. This is the directory which will contain the .mvn directory.
+	//
+	// Since: 4.1.0
+	Root string `xml:"root,attr,omitempty"`
+
+	// Encoding is the character encoding the document was read in, as named by
+	// its XML declaration or byte order mark. Empty means UTF-8.
+	Encoding string `xml:"-"`
+
+	// Any holds elements that are not part of the POM schema and AnyAttrs the
+	// attributes, so that documents decoded in Lenient mode round trip. Every
+	// other element type has the same pair of fields.
+	Any      []DOM      `xml:",any"`
+	AnyAttrs []xml.Attr `xml:",any,attr"`
 }
 
 type Prerequisites struct {
 	Comment string `xml:",comment"`
 	// For a plugin project (packaging is <code>maven-plugin</code>), the minimum version of Maven required to use the resulting plugin.
-	Maven string `xml:"maven,omitempty"`
+	Maven    string     `xml:"maven,omitempty"`
+	Any      []DOM      `xml:",any"`
+	AnyAttrs []xml.Attr `xml:",any,attr"`
 }
 
 type Modules struct {
-	Comment string   `xml:",comment"`
-	Module  []string `xml:"module,omitempty"`
+	Comment  string     `xml:",comment"`
+	Module   []string   `xml:"module,omitempty"`
+	Any      []DOM      `xml:",any"`
+	AnyAttrs []xml.Attr `xml:",any,attr"`
+}
+
+type Subprojects struct {
+	Comment    string     `xml:",comment"`
+	Subproject []string   `xml:"subproject,omitempty"`
+	Any        []DOM      `xml:",any"`
+	AnyAttrs   []xml.Attr `xml:",any,attr"`
 }
 
 type Licenses struct {
-	Comment string    `xml:",comment"`
-	License []License `xml:"license,omitempty"`
+	Comment  string     `xml:",comment"`
+	License  []License  `xml:"license,omitempty"`
+	Any      []DOM      `xml:",any"`
+	AnyAttrs []xml.Attr `xml:",any,attr"`
 }
 
 type License struct {
-	Comment      string `xml:",comment"`
-	Name         string `xml:"name,omitempty"`
-	Url          string `xml:"url,omitempty"`
-	Distribution string `xml:"distribution,omitempty"`
-	Comments     string `xml:"comments,omitempty"`
+	Comment      string     `xml:",comment"`
+	Name         string     `xml:"name,omitempty"`
+	Url          string     `xml:"url,omitempty"`
+	Distribution string     `xml:"distribution,omitempty"`
+	Comments     string     `xml:"comments,omitempty"`
+	Any          []DOM      `xml:",any"`
+	AnyAttrs     []xml.Attr `xml:",any,attr"`
 }
 
 type CiManagement struct {
@@ -104,22 +142,30 @@ type CiManagement struct {
 	System    string     `xml:"system,omitempty"`
 	Url       string     `xml:"url,omitempty"`
 	Notifiers *Notifiers `xml:"notifiers,omitempty"`
+	Any       []DOM      `xml:",any"`
+	AnyAttrs  []xml.Attr `xml:",any,attr"`
 }
 
 type Notifiers struct {
 	Comment  string     `xml:",comment"`
 	Notifier []Notifier `xml:"notifier,omitempty"`
+	Any      []DOM      `xml:",any"`
+	AnyAttrs []xml.Attr `xml:",any,attr"`
 }
 
 type Notifier struct {
-	Comment       string `xml:",comment"`
-	Type          string `xml:"type,omitempty"`
-	SendOnError   bool   `xml:"sendOnError,omitempty"`
-	SendOnFailure bool   `xml:"sendOnFailure,omitempty"`
-	SendOnSuccess bool   `xml:"sendOnSuccess,omitempty"`
-	SendOnWarning bool   `xml:"sendOnWarning,omitempty"`
-	Address       string `xml:"address,omitempty"`
-	Configuration *DOM   `xml:"configuration,omitempty"`
+	Comment string `xml:",comment"`
+	Type    string `xml:"type,omitempty"`
+	// The notifier settings default to true when absent, so they are kept as
+	// strings to preserve an explicit false.
+	SendOnError   string     `xml:"sendOnError,omitempty"`
+	SendOnFailure string     `xml:"sendOnFailure,omitempty"`
+	SendOnSuccess string     `xml:"sendOnSuccess,omitempty"`
+	SendOnWarning string     `xml:"sendOnWarning,omitempty"`
+	Address       string     `xml:"address,omitempty"`
+	Configuration *DOM       `xml:"configuration,omitempty"`
+	Any           []DOM      `xml:",any"`
+	AnyAttrs      []xml.Attr `xml:",any,attr"`
 }
 
 type Scm struct {
@@ -136,17 +182,28 @@ type Scm struct {
 	DeveloperConnection string `xml:"developerConnection,omitempty"`
 	Tag                 string `xml:"tag,omitempty"`
 	Url                 string `xml:"url,omitempty"`
+	// Whether the connection, developerConnection and url are extended with the
+	// artifactId of children inheriting them. Each defaults to true.
+	ChildScmConnectionInheritAppendPath          string     `xml:"child.scm.connection.inherit.append.path,attr,omitempty"`
+	ChildScmDeveloperConnectionInheritAppendPath string     `xml:"child.scm.developerConnection.inherit.append.path,attr,omitempty"`
+	ChildScmUrlInheritAppendPath                 string     `xml:"child.scm.url.inherit.append.path,attr,omitempty"`
+	Any                                          []DOM      `xml:",any"`
+	AnyAttrs                                     []xml.Attr `xml:",any,attr"`
 }
 
 type IssueManagement struct {
-	Comment string `xml:",comment"`
-	System  string `xml:"system,omitempty"`
-	Url     string `xml:"url,omitempty"`
+	Comment  string     `xml:",comment"`
+	System   string     `xml:"system,omitempty"`
+	Url      string     `xml:"url,omitempty"`
+	Any      []DOM      `xml:",any"`
+	AnyAttrs []xml.Attr `xml:",any,attr"`
 }
 
 type DependencyManagement struct {
 	Comment      string        `xml:",comment"`
 	Dependencies *Dependencies `xml:"dependencies,omitempty"`
+	Any          []DOM         `xml:",any"`
+	AnyAttrs     []xml.Attr    `xml:",any,attr"`
 }
 
 type Dependency struct {
@@ -160,53 +217,69 @@ type Dependency struct {
 	SystemPath string      `xml:"systemPath,omitempty"`
 	Exclusions *Exclusions `xml:"exclusions,omitempty"`
 	Optional   string      `xml:"optional,omitempty"`
+	Any        []DOM       `xml:",any"`
+	AnyAttrs   []xml.Attr  `xml:",any,attr"`
 }
 
 type Exclusions struct {
 	Comment   string      `xml:",comment"`
 	Exclusion []Exclusion `xml:"exclusion,omitempty"`
+	Any       []DOM       `xml:",any"`
+	AnyAttrs  []xml.Attr  `xml:",any,attr"`
 }
 
 type Exclusion struct {
-	Comment    string `xml:",comment"`
-	ArtifactId string `xml:"artifactId,omitempty"`
-	GroupId    string `xml:"groupId,omitempty"`
+	Comment    string     `xml:",comment"`
+	ArtifactId string     `xml:"artifactId,omitempty"`
+	GroupId    string     `xml:"groupId,omitempty"`
+	Any        []DOM      `xml:",any"`
+	AnyAttrs   []xml.Attr `xml:",any,attr"`
 }
 
 type Parent struct {
-	Comment      string `xml:",comment"`
-	GroupId      string `xml:"groupId,omitempty"`
-	ArtifactId   string `xml:"artifactId,omitempty"`
-	Version      string `xml:"version,omitempty"`
-	RelativePath string `xml:"relativePath,omitempty"`
+	Comment      string     `xml:",comment"`
+	GroupId      string     `xml:"groupId,omitempty"`
+	ArtifactId   string     `xml:"artifactId,omitempty"`
+	Version      string     `xml:"version,omitempty"`
+	RelativePath string     `xml:"relativePath,omitempty"`
+	Any          []DOM      `xml:",any"`
+	AnyAttrs     []xml.Attr `xml:",any,attr"`
 }
 
 type Developers struct {
 	Comment   string      `xml:",comment"`
 	Developer []Developer `xml:"developer,omitempty"`
+	Any       []DOM       `xml:",any"`
+	AnyAttrs  []xml.Attr  `xml:",any,attr"`
 }
 
 type Developer struct {
-	Comment         string `xml:",comment"`
-	Id              string `xml:"id,omitempty"`
-	Name            string `xml:"name,omitempty"`
-	Email           string `xml:"email,omitempty"`
-	Url             string `xml:"url,omitempty"`
-	Organization    string `xml:"organization,omitempty"`
-	OrganizationUrl string `xml:"organizationUrl,omitempty"`
-	Roles           *Roles `xml:"roles,omitempty"`
-	Timezone        string `xml:"timezone:omitempty"`
-	Properties      *DOM   `xml:"properties,omitempty"`
+	Comment         string      `xml:",comment"`
+	Id              string      `xml:"id,omitempty"`
+	Name            string      `xml:"name,omitempty"`
+	Email           string      `xml:"email,omitempty"`
+	Url             string      `xml:"url,omitempty"`
+	Organization    string      `xml:"organization,omitempty"`
+	OrganizationUrl string      `xml:"organizationUrl,omitempty"`
+	Roles           *Roles      `xml:"roles,omitempty"`
+	Timezone        string      `xml:"timezone,omitempty"`
+	Properties      *Properties `xml:"properties,omitempty"`
+	Any             []DOM       `xml:",any"`
+	AnyAttrs        []xml.Attr  `xml:",any,attr"`
 }
 
 type Roles struct {
-	Comment string   `xml:",comment"`
-	Role    []string `xml:"role,omitempty"`
+	Comment  string     `xml:",comment"`
+	Role     []string   `xml:"role,omitempty"`
+	Any      []DOM      `xml:",any"`
+	AnyAttrs []xml.Attr `xml:",any,attr"`
 }
 
 type MailingLists struct {
 	Comment     string        `xml:",comment"`
 	MailingList []MailingList `xml:"mailingList,omitempty"`
+	Any         []DOM         `xml:",any"`
+	AnyAttrs    []xml.Attr    `xml:",any,attr"`
 }
 
 type MailingList struct {
@@ -216,32 +289,53 @@ type MailingList struct {
 	Unsubscribe   string         `xml:"unsubscribe,omitempty"`
 	Post          string         `xml:"post,omitempty"`
 	Archive       string         `xml:"archive,omitempty"`
-	OtherArchives []OtherArchive `xml:"otherArchives,omitempty"`
+	OtherArchives *OtherArchives `xml:"otherArchives,omitempty"`
+	Any           []DOM          `xml:",any"`
+	AnyAttrs      []xml.Attr     `xml:",any,attr"`
 }
 
-type OtherArchive struct {
-	Comment      string `xml:",comment"`
-	OtherArchive string `xml:"otherArchive,omitempty"`
+type OtherArchives struct {
+	Comment      string     `xml:",comment"`
+	OtherArchive []string   `xml:"otherArchive,omitempty"`
+	Any          []DOM      `xml:",any"`
+	AnyAttrs     []xml.Attr `xml:",any,attr"`
 }
 
 type Contributors struct {
 	Comment     string        `xml:",comment"`
 	Contributor []Contributor `xml:"contributor,omitempty"`
+	Any         []DOM         `xml:",any"`
+	AnyAttrs    []xml.Attr    `xml:",any,attr"`
 }
 
+// Description of a person who has contributed to the project, but who does not have commit privileges. Usually, these contributions come in the form of patches submitted.
 type Contributor struct {
-	Developer
+	Comment         string      `xml:",comment"`
+	Name            string      `xml:"name,omitempty"`
+	Email           string      `xml:"email,omitempty"`
+	Url             string      `xml:"url,omitempty"`
+	Organization    string      `xml:"organization,omitempty"`
+	OrganizationUrl string      `xml:"organizationUrl,omitempty"`
+	Roles           *Roles      `xml:"roles,omitempty"`
+	Timezone        string      `xml:"timezone,omitempty"`
+	Properties      *Properties `xml:"properties,omitempty"`
+	Any             []DOM       `xml:",any"`
+	AnyAttrs        []xml.Attr  `xml:",any,attr"`
 }
 
 type Organization struct {
-	Comment string `xml:",comment"`
-	Name    string `xml:"name,omitempty"`
-	Url     string `xml:"url,omitempty"`
+	Comment  string     `xml:",comment"`
+	Name     string     `xml:"name,omitempty"`
+	Url      string     `xml:"url,omitempty"`
+	Any      []DOM      `xml:",any"`
+	AnyAttrs []xml.Attr `xml:",any,attr"`
 }
 
 type Dependencies struct {
 	Comment    string       `xml:",comment"`
 	Dependency []Dependency `xml:"dependency,omitempty"`
+	Any        []DOM        `xml:",any"`
+	AnyAttrs   []xml.Attr   `xml:",any,attr"`
 }
 
 type DistributionManagement struct {
@@ -250,29 +344,38 @@ type DistributionManagement struct {
 	SnapshotRepository *DeploymentRepository `xml:"snapshotRepository,omitempty"`
 	Site               *Site                 `xml:"site,omitempty"`
 	DownloadUrl        string                `xml:"downloadUrl,omitempty"`
-	Reloction          *Relocation           `xml:"relocation,omitempty"`
+	Relocation         *Relocation           `xml:"relocation,omitempty"`
 	Status             string                `xml:"status,omitempty"`
+	Any                []DOM                 `xml:",any"`
+	AnyAttrs           []xml.Attr            `xml:",any,attr"`
 }
 
 type DeploymentRepository struct {
-	Comment       string            `xml:",comment"`
-	UniqueVersion bool              `xml:"uniqueVersion,omitempty"`
+	Comment string `xml:",comment"`
+	// Whether to assign snapshots a unique version comprised of the timestamp and build number, or to use the same version each time. Defaults to true when absent.
+	UniqueVersion string            `xml:"uniqueVersion,omitempty"`
 	Releases      *RepositoryPolicy `xml:"releases,omitempty"`
 	Snapshots     *RepositoryPolicy `xml:"snapshots,omitempty"`
 	Id            string            `xml:"id,omitempty"`
 	Name          string            `xml:"name,omitempty"`
 	Url           string            `xml:"url,omitempty"`
 	Layout        string            `xml:"layout,omitempty"`
+	Any           []DOM             `xml:",any"`
+	AnyAttrs      []xml.Attr        `xml:",any,attr"`
 }
 
 type Repositories struct {
 	Comment    string       `xml:",comment"`
 	Repository []Repository `xml:"repository,omitempty"`
+	Any        []DOM        `xml:",any"`
+	AnyAttrs   []xml.Attr   `xml:",any,attr"`
 }
 
 type PluginRepositories struct {
-	Comment    string       `xml:",comment"`
-	Repository []Repository `xml:"repository,omitempty"`
+	Comment          string       `xml:",comment"`
+	PluginRepository []Repository `xml:"pluginRepository,omitempty"`
+	Any              []DOM        `xml:",any"`
+	AnyAttrs         []xml.Attr   `xml:",any,attr"`
 }
 
 type Repository struct {
@@ -283,14 +386,18 @@ type Repository struct {
 	Name      string            `xml:"name,omitempty"`
 	Url       string            `xml:"url,omitempty"`
 	Layout    string            `xml:"layout,omitempty"`
+	Any       []DOM             `xml:",any"`
+	AnyAttrs  []xml.Attr        `xml:",any,attr"`
 }
 
 // Download policy
 type RepositoryPolicy struct {
-	Comment        string `xml:",comment"`
-	Enabled        string `xml:"enabled,omitempty"`
-	UpdatePolicy   string `xml:"updatPolicy,omitempty"`
-	ChecksumPolicy string `xml:"checksumPolicy,omitempty"`
+	Comment        string     `xml:",comment"`
+	Enabled        string     `xml:"enabled,omitempty"`
+	UpdatePolicy   string     `xml:"updatePolicy,omitempty"`
+	ChecksumPolicy string     `xml:"checksumPolicy,omitempty"`
+	Any            []DOM      `xml:",any"`
+	AnyAttrs       []xml.Attr `xml:",any,attr"`
 }
 
 type Site struct {
@@ -298,19 +405,27 @@ type Site struct {
 	Id      string `xml:"id,omitempty"`
 	Name    string `xml:"name,omitempty"`
 	Url     string `xml:"url,omitempty"`
+	// Whether the url is extended with the artifactId of children inheriting it. Defaults to true.
+	ChildSiteUrlInheritAppendPath string     `xml:"child.site.url.inherit.append.path,attr,omitempty"`
+	Any                           []DOM      `xml:",any"`
+	AnyAttrs                      []xml.Attr `xml:",any,attr"`
 }
 
 type Relocation struct {
-	Comment    string `xml:",comment"`
-	GroupId    string `xml:"groupId,omitempty"`
-	ArtifactId string `xml:"artifactId,omitempty"`
-	Version    string `xml:"version,omitempty"`
-	Message    string `xml:"message,omitempty"`
+	Comment    string     `xml:",comment"`
+	GroupId    string     `xml:"groupId,omitempty"`
+	ArtifactId string     `xml:"artifactId,omitempty"`
+	Version    string     `xml:"version,omitempty"`
+	Message    string     `xml:"message,omitempty"`
+	Any        []DOM      `xml:",any"`
+	AnyAttrs   []xml.Attr `xml:",any,attr"`
 }
 
 type Reports struct {
-	Comment string   `xml:",comment"`
-	Report  []string `xml:"report,omitempty"`
+	Comment  string     `xml:",comment"`
+	Report   []string   `xml:"report,omitempty"`
+	Any      []DOM      `xml:",any"`
+	AnyAttrs []xml.Attr `xml:",any,attr"`
 }
 
 type Reporting struct {
@@ -318,11 +433,15 @@ type Reporting struct {
 	ExcludeDefaults string         `xml:"excludeDefaults,omitempty"`
 	OutputDirectory string         `xml:"outputDirectory,omitempty"`
 	Plugins         *ReportPlugins `xml:"plugins,omitempty"`
+	Any             []DOM          `xml:",any"`
+	AnyAttrs        []xml.Attr     `xml:",any,attr"`
 }
 
 type ReportPlugins struct {
-	Comment string         `xml:",comment"`
-	Plugins []ReportPlugin `xml:"plugins,omitempty"`
+	Comment  string         `xml:",comment"`
+	Plugin   []ReportPlugin `xml:"plugin,omitempty"`
+	Any      []DOM          `xml:",any"`
+	AnyAttrs []xml.Attr     `xml:",any,attr"`
 }
 
 type ReportPlugin struct {
@@ -335,48 +454,63 @@ type ReportPlugin struct {
 	ReportSets    *ReportSets `xml:"reportSets,omitempty"`
 	Inherited     string      `xml:"inherited,omitempty"`
 	Configuration *DOM        `xml:"configuration,omitempty"`
+	Any           []DOM       `xml:",any"`
+	AnyAttrs      []xml.Attr  `xml:",any,attr"`
 }
 
 type ReportSets struct {
 	Comment   string      `xml:",comment"`
 	ReportSet []ReportSet `xml:"reportSet,omitempty"`
+	Any       []DOM       `xml:",any"`
+	AnyAttrs  []xml.Attr  `xml:",any,attr"`
 }
 
 type ReportSet struct {
-	Comment       string   `xml:",comment"`
-	Id            string   `xml:"id,omitempty"`
-	Reports       *Reports `xml:"reports,omitempty"`
-	Inherited     string   `xml:"inherited,omitempty"`
-	Configuration *DOM     `xml:"configuration,omitempty"`
+	Comment       string     `xml:",comment"`
+	Id            string     `xml:"id,omitempty"`
+	Reports       *Reports   `xml:"reports,omitempty"`
+	Inherited     string     `xml:"inherited,omitempty"`
+	Configuration *DOM       `xml:"configuration,omitempty"`
+	Any           []DOM      `xml:",any"`
+	AnyAttrs      []xml.Attr `xml:",any,attr"`
 }
 
 type PluginManagement struct {
-	Comment string   `xml:",comment"`
-	Plugins *Plugins `xml:"plugins,omitempty"`
+	Comment  string     `xml:",comment"`
+	Plugins  *Plugins   `xml:"plugins,omitempty"`
+	Any      []DOM      `xml:",any"`
+	AnyAttrs []xml.Attr `xml:",any,attr"`
 }
 
 type Plugins struct {
-	Comment string   `xml:",comment"`
-	Plugin  []Plugin `xml:"plugin,omitempty"`
+	Comment  string     `xml:",comment"`
+	Plugin   []Plugin   `xml:"plugin,omitempty"`
+	Any      []DOM      `xml:",any"`
+	AnyAttrs []xml.Attr `xml:",any,attr"`
 }
 
 type Plugin struct {
-	Comment      string        `xml:",comment"`
-	GroupId      string        `xml:"groupId,omitempty"`
-	ArtifactId   string        `xml:"artifactId,omitempty"`
-	Version      string        `xml:"version,omitempty"`
-	Extensions   bool          `xml:"extensions,omitempty"`
+	Comment    string `xml:",comment"`
+	GroupId    string `xml:"groupId,omitempty"`
+	ArtifactId string `xml:"artifactId,omitempty"`
+	Version    string `xml:"version,omitempty"`
+	// Whether to load Maven extensions (such as packaging and type handlers) from this plugin. For performance reasons, this should only be enabled when necessary. Note: While the type of this field is <code>String</code> for technical reasons, the semantic type is actually <code>Boolean</code>. Default value is <code>false</code>.
+	Extensions   string        `xml:"extensions,omitempty"`
 	Executions   *Executions   `xml:"executions,omitempty"`
 	Dependencies *Dependencies `xml:"dependencies,omitempty"`
 	// Deprecated: Not used by Maven. Use Goals within Execution instead
-	Goals         *Goals `xml:"goals,omitempty"`
-	Inherited     string `xml:"inherited,omitempty"`
-	Configuration *DOM   `xml:"configuration,omitempty"`
+	Goals         *Goals     `xml:"goals,omitempty"`
+	Inherited     string     `xml:"inherited,omitempty"`
+	Configuration *DOM       `xml:"configuration,omitempty"`
+	Any           []DOM      `xml:",any"`
+	AnyAttrs      []xml.Attr `xml:",any,attr"`
 }
 
 type Executions struct {
 	Comment   string      `xml:",comment"`
 	Execution []Execution `xml:"execution,omitempty"`
+	Any       []DOM       `xml:",any"`
+	AnyAttrs  []xml.Attr  `xml:",any,attr"`
 }
 
 type Execution struct {
@@ -384,51 +518,90 @@ type Execution struct {
 	Id      string `xml:"id,omitempty"`
 	Phase   string `xml:"phase,omitempty"`
 	Goals   *Goals `xml:"goals,omitempty"`
+	// Whether any configuration should be propagated to child POMs. Note: While the type of this field is <code>String</code> for technical reasons, the semantic type is actually <code>Boolean</code>. Default value is <code>true</code>.
+	Inherited     string `xml:"inherited,omitempty"`
+	Configuration *DOM   `xml:"configuration,omitempty"`
+	// The priority of this execution compared to other executions which are bound to the same phase. Executions with a higher priority run first.
+	//
+	// Since: 4.1.0
+	Priority string     `xml:"priority,omitempty"`
+	Any      []DOM      `xml:",any"`
+	AnyAttrs []xml.Attr `xml:",any,attr"`
 }
 
 type Goals struct {
-	Comment string   `xml:",comment"`
-	Goal    []string `xml:"goal,omitempty"`
+	Comment  string     `xml:",comment"`
+	Goal     []string   `xml:"goal,omitempty"`
+	Any      []DOM      `xml:",any"`
+	AnyAttrs []xml.Attr `xml:",any,attr"`
 }
 
 type Resources struct {
+	Comment  string     `xml:",comment"`
+	Resource []Resource `xml:"resource,omitempty"`
+	Any      []DOM      `xml:",any"`
+	AnyAttrs []xml.Attr `xml:",any,attr"`
+}
+
+type TestResources struct {
+	Comment      string     `xml:",comment"`
+	TestResource []Resource `xml:"testResource,omitempty"`
+	Any          []DOM      `xml:",any"`
+	AnyAttrs     []xml.Attr `xml:",any,attr"`
+}
+
+// This element describes all of the classpath resources associated with a project or unit tests.
+type Resource struct {
 	Comment string `xml:",comment"`
 	/*
 		Describe the resource target path. The path is relative to the target/classes directory
 
 		IE: ${project.build.outputDirectory}
 	*/
-	TargetPath string    `xml:"targetPath,omitempty"`
-	Filtering  string    `xml:"filtering,omitempty"`
-	Directory  string    `xml:"directory,omitempty"`
-	Includes   *Includes `xml:"includes,omitempty"`
-	Excludes   *Excludes `xml:"excludes,omitempty"`
+	TargetPath string `xml:"targetPath,omitempty"`
+	Filtering  string `xml:"filtering,omitempty"`
+	// FOR INTERNAL USE ONLY. This is a unique identifier assigned to each resource to allow Maven to merge changes to this resource that take place during the execution of a plugin.
+	MergeId   string     `xml:"mergeId,omitempty"`
+	Directory string     `xml:"directory,omitempty"`
+	Includes  *Includes  `xml:"includes,omitempty"`
+	Excludes  *Excludes  `xml:"excludes,omitempty"`
+	Any       []DOM      `xml:",any"`
+	AnyAttrs  []xml.Attr `xml:",any,attr"`
 }
 
 type Includes struct {
-	Comment string   `xml:",comment"`
-	Include []string `xml:"include,omitempty"`
+	Comment  string     `xml:",comment"`
+	Include  []string   `xml:"include,omitempty"`
+	Any      []DOM      `xml:",any"`
+	AnyAttrs []xml.Attr `xml:",any,attr"`
 }
 
 type Excludes struct {
-	Comment string   `xml:",comment"`
-	Exclude []string `xml:"exclude,omitempty"`
+	Comment  string     `xml:",comment"`
+	Exclude  []string   `xml:"exclude,omitempty"`
+	Any      []DOM      `xml:",any"`
+	AnyAttrs []xml.Attr `xml:",any,attr"`
 }
 
 type Filters struct {
-	Comment string `xml:",comment"`
+	Comment  string     `xml:",comment"`
+	Filter   []string   `xml:"filter,omitempty"`
+	Any      []DOM      `xml:",any"`
+	AnyAttrs []xml.Attr `xml:",any,attr"`
 }
 
 type BuildBase struct {
 	Comment          string            `xml:",comment"`
 	DefaultGoal      string            `xml:"defaultGoal,omitempty"`
 	Resources        *Resources        `xml:"resources,omitempty"`
-	TestResources    *Resources        `xml:"testResources,omitempty"`
+	TestResources    *TestResources    `xml:"testResources,omitempty"`
 	Directory        string            `xml:"directory,omitempty"`
 	FinalName        string            `xml:"finalName,omitempty"`
 	Filters          *Filters          `xml:"filters,omitempty"`
 	PluginManagement *PluginManagement `xml:"pluginManagement,omitempty"`
 	Plugins          *Plugins          `xml:"plugins,omitempty"`
+	Any              []DOM             `xml:",any"`
+	AnyAttrs         []xml.Attr        `xml:",any,attr"`
 }
 
 type Build struct {
@@ -439,41 +612,89 @@ type Build struct {
 	OutputDirectory       string      `xml:"outputDirectory,omitempty"`
 	TestOutputDirectory   string      `xml:"testOutputDirectory,omitempty"`
 	Extensions            *Extensions `xml:"extensions,omitempty"`
+	// All the sources to compile and resources files to copy for a project or its unit tests. Replaces the source, test source and resource directories.
+	//
+	// Since: 4.1.0
+	Sources *Sources `xml:"sources,omitempty"`
 	BuildBase
 }
 
+type Sources struct {
+	Comment  string     `xml:",comment"`
+	Source   []Source   `xml:"source,omitempty"`
+	Any      []DOM      `xml:",any"`
+	AnyAttrs []xml.Attr `xml:",any,attr"`
+}
+
+// A source directory of the project, along with the language and scope of the files it contains.
+//
+// Since: 4.1.0
+type Source struct {
+	Comment string `xml:",comment"`
+	// Either <code>main</code> or <code>test</code>. Default value is <code>main</code>.
+	Scope string `xml:"scope,omitempty"`
+	// The language of the files, such as <code>java</code> or <code>resources</code>. Default value is <code>java</code>.
+	Lang string `xml:"lang,omitempty"`
+	// The name of the Java module the sources belong to, for modular projects.
+	Module string `xml:"module,omitempty"`
+	// The Java release the sources target, for multi-release JARs.
+	TargetVersion string `xml:"targetVersion,omitempty"`
+	// The directory holding the files, relative to the project. Defaults to <code>src/${scope}/${lang}</code>.
+	Directory string    `xml:"directory,omitempty"`
+	Includes  *Includes `xml:"includes,omitempty"`
+	Excludes  *Excludes `xml:"excludes,omitempty"`
+	// Whether to filter resource files, replacing ${...} expressions.
+	StringFiltering string `xml:"stringFiltering,omitempty"`
+	// Where resource files are copied, relative to the output directory.
+	TargetPath string     `xml:"targetPath,omitempty"`
+	Enabled    string     `xml:"enabled,omitempty"`
+	Any        []DOM      `xml:",any"`
+	AnyAttrs   []xml.Attr `xml:",any,attr"`
+}
+
 type Extensions struct {
 	Comment   string      `xml:",comment"`
 	Extension []Extension `xml:"extension,omitempty"`
+	Any       []DOM       `xml:",any"`
+	AnyAttrs  []xml.Attr  `xml:",any,attr"`
 }
 
 type Extension struct {
-	Comment    string `xml:",comment"`
-	GroupId    string `xml:"groupId,omitempty"`
-	ArtifactId string `xml:"artifactId,omitempty"`
-	Version    string `xml:"version,omitempty"`
+	Comment    string     `xml:",comment"`
+	GroupId    string     `xml:"groupId,omitempty"`
+	ArtifactId string     `xml:"artifactId,omitempty"`
+	Version    string     `xml:"version,omitempty"`
+	Any        []DOM      `xml:",any"`
+	AnyAttrs   []xml.Attr `xml:",any,attr"`
 }
 
 type Profiles struct {
-	Comment string    `xml:",comment"`
-	Profile []Profile `xml:"profile,omitempty"`
+	Comment  string     `xml:",comment"`
+	Profile  []Profile  `xml:"profile,omitempty"`
+	Any      []DOM      `xml:",any"`
+	AnyAttrs []xml.Attr `xml:",any,attr"`
 }
 
 // Modifications to the build process which is activated based on environmental parameters or command line arguments.
 type Profile struct {
-	Comment                string                  `xml:",comment"`
-	Id                     string                  `xml:"id,omitempty"`
-	Activation             *Activation             `xml:"activation,omitempty"`
-	Build                  *BuildBase              `xml:"build,omitempty"`
-	Modules                *Modules                `xml:"modules,omitempty"`
+	Comment    string      `xml:",comment"`
+	Id         string      `xml:"id,omitempty"`
+	Activation *Activation `xml:"activation,omitempty"`
+	Build      *BuildBase  `xml:"build,omitempty"`
+	Modules    *Modules    `xml:"modules,omitempty"`
+	// Since: 4.1.0
+	Subprojects            *Subprojects            `xml:"subprojects,omitempty"`
 	DistributionManagement *DistributionManagement `xml:"distributionManagement,omitempty"`
-	Properties             *DOM                    `xml:"properties,omitempty"`
+	Properties             *Properties             `xml:"properties,omitempty"`
+	DependencyManagement   *DependencyManagement   `xml:"dependencyManagement,omitempty"`
 	Dependencies           *Dependencies           `xml:"dependencies,omitempty"`
 	Repositories           *Repositories           `xml:"repositories,omitempty"`
 	PluginRepositories     *PluginRepositories     `xml:"pluginRepositories,omitempty"`
 	// Deprecated: Not used by Maven
 	Reports   *Reports   `xml:"reports,omitempty"`
 	Reporting *Reporting `xml:"reporting,omitempty"`
+	Any       []DOM      `xml:",any"`
+	AnyAttrs  []xml.Attr `xml:",any,attr"`
 }
 
 type Activation struct {
@@ -483,20 +704,30 @@ type Activation struct {
 	OS              *ActivationOS       `xml:"os,omitempty"`
 	Property        *ActivationProperty `xml:"property,omitempty"`
 	File            *ActivationFile     `xml:"file,omitempty"`
+	// An expression that activates the profile when it evaluates to true, such as <code>exists("${project.basedir}/src") &amp;&amp; ${os.name} == 'linux'</code>. Replaces the other activation elements.
+	//
+	// Since: 4.1.0
+	Condition string     `xml:"condition,omitempty"`
+	Any       []DOM      `xml:",any"`
+	AnyAttrs  []xml.Attr `xml:",any,attr"`
 }
 
 type ActivationProperty struct {
-	Comment string `xml:",comment"`
-	Name    string `xml:"name,omitempty"`
-	Value   string `xml:"value,omitempty"`
+	Comment  string     `xml:",comment"`
+	Name     string     `xml:"name,omitempty"`
+	Value    string     `xml:"value,omitempty"`
+	Any      []DOM      `xml:",any"`
+	AnyAttrs []xml.Attr `xml:",any,attr"`
 }
 
 type ActivationOS struct {
-	Comment string `xml:",comment"`
-	Name    string `xml:"name,omitempty"`
-	Family  string `xml:"family,omitempty"`
-	Arch    string `xml:"arch,omitempty"`
-	Version string `xml:"version,omitempty"`
+	Comment  string     `xml:",comment"`
+	Name     string     `xml:"name,omitempty"`
+	Family   string     `xml:"family,omitempty"`
+	Arch     string     `xml:"arch,omitempty"`
+	Version  string     `xml:"version,omitempty"`
+	Any      []DOM      `xml:",any"`
+	AnyAttrs []xml.Attr `xml:",any,attr"`
 }
 
 type ActivationFile struct {
@@ -504,16 +735,57 @@ type ActivationFile struct {
 	// The name of the file that must be missing to activate the profile.
 	Missing string `xml:"missing,omitempty"`
 	// The name of the file that must exist to activate the profile.
-	Exists string `xml:"exists,omitempty"`
+	Exists   string     `xml:"exists,omitempty"`
+	Any      []DOM      `xml:",any"`
+	AnyAttrs []xml.Attr `xml:",any,attr"`
 }
 
 type Properties struct {
 	Comment string            `xml:",comment"`
 	Fields  map[string]string `xml:"-"`
+	// order remembers the document order of Fields so that encoding is stable.
+	order []string
+}
+
+// Keys returns the property names in document order, followed by any names
+// added to Fields since decoding in sorted order.
+func (p *Properties) Keys() []string {
+	keys := make([]string, 0, len(p.Fields))
+	seen := make(map[string]bool, len(p.Fields))
+
+	for _, key := range p.order {
+		if _, ok := p.Fields[key]; ok && !seen[key] {
+			keys = append(keys, key)
+			seen[key] = true
+		}
+	}
+
+	var added []string
+	for key := range p.Fields {
+		if !seen[key] {
+			added = append(added, key)
+		}
+	}
+	sort.Strings(added)
+
+	return append(keys, added...)
+}
+
+// Set sets the value of the property key. New keys come after the existing
+// ones in Keys, in the order they are set.
+func (p *Properties) Set(key, value string) {
+	if p.Fields == nil {
+		p.Fields = make(map[string]string)
+	}
+	if _, ok := p.Fields[key]; !ok {
+		p.order = append(p.order, key)
+	}
+	p.Fields[key] = value
 }
 
 func (p *Properties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	p.Fields = make(map[string]string)
+	p.order = nil
 
 	type element struct {
 		XMLName xml.Name
@@ -522,10 +794,10 @@ func (p *Properties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error
 
 	for {
 		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
 		if err != nil {
-			if err.Error() == "EOF" {
-				break
-			}
 			return err
 		}
 
@@ -537,6 +809,9 @@ func (p *Properties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error
 			}
 			key := el.XMLName.Local
 			value := strings.TrimSpace(el.Value)
+			if _, ok := p.Fields[key]; !ok {
+				p.order = append(p.order, key)
+			}
 			p.Fields[key] = value
 		}
 	}
@@ -550,10 +825,10 @@ func (p *Properties) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 		return err
 	}
 
-	// Iterate through the map and create XML elements for each key-value pair
-	for key, value := range p.Fields {
+	// Create XML elements for each key-value pair in a stable order
+	for _, key := range p.Keys() {
 		element := xml.StartElement{Name: xml.Name{Local: key}}
-		if err := e.EncodeElement(value, element); err != nil {
+		if err := e.EncodeElement(p.Fields[key], element); err != nil {
 			return err
 		}
 	}
@@ -579,6 +854,7 @@ func (a *DOM) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 
 	a.XMLName.Local = start.Name.Local
 	// a.XMLName.Space = start.Name.Space
+	a.Attrs = append([]xml.Attr(nil), start.Attr...)
 
 	for {
 		t, err := d.Token()
@@ -612,16 +888,29 @@ func (a *DOM) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 }
 
 func (a *DOM) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
-	start.Name = a.XMLName
-	start.Attr = nil // NOTE: just omitting the attrs
+	if a.XMLName.Local != "" {
+		start.Name = a.XMLName
+	}
+	start.Attr = nil
+
+	// NOTE: attributes in a namespace other than xmlns are omitted, the decoder
+	// does not keep the prefix they were written with
+	for _, attr := range a.Attrs {
+		switch attr.Name.Space {
+		case "":
+			start.Attr = append(start.Attr, attr)
+		case "xmlns":
+			start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xmlns:" + attr.Name.Local}, Value: attr.Value})
+		}
+	}
 
 	// Encode the start of the element
 	if err := e.EncodeToken(start); err != nil {
 		return err
 	}
 
-	for _, child := range a.Children {
-		if err := e.Encode(child); err != nil {
+	for i := range a.Children {
+		if err := e.Encode(&a.Children[i]); err != nil {
 			return err
 		}
 	}
@@ -632,5 +921,5 @@ func (a *DOM) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 		}
 	}
 
-	return e.EncodeToken(xml.EndElement{Name: a.XMLName})
+	return e.EncodeToken(start.End())
 }
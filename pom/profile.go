@@ -0,0 +1,219 @@
+package pom
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// activator decides which profiles of a POM are active.
+type activator struct {
+	// props are the user and system properties that property, jdk and os
+	// activation test, such as java.version and os.name.
+	props    map[string]string
+	active   []string
+	inactive []string
+}
+
+// activeProfiles returns the active profiles of m in declaration order.
+// Profiles listed in inactive never are, profiles listed in active always
+// are, and the others are when their activation matches. Profiles that are
+// active by default are only active when no other profile of m is.
+func (a *activator) activeProfiles(m *Model, dir string) []*Profile {
+	if m.Profiles == nil {
+		return nil
+	}
+
+	var active, byDefault []*Profile
+	for i := range m.Profiles.Profile {
+		p := &m.Profiles.Profile[i]
+		switch {
+		case slices.Contains(a.inactive, p.Id):
+		case slices.Contains(a.active, p.Id) || a.matches(p.Activation, dir):
+			active = append(active, p)
+		case p.Activation != nil && p.Activation.ActiveByDefault:
+			byDefault = append(byDefault, p)
+		}
+	}
+	if len(active) == 0 {
+		return byDefault
+	}
+	return active
+}
+
+// matches reports whether every condition of act holds. Activation by a
+// Maven 4 condition expression is not evaluated, so such profiles are only
+// active when listed explicitly.
+func (a *activator) matches(act *Activation, dir string) bool {
+	if act == nil || act.Condition != "" {
+		return false
+	}
+	if act.JDK == "" && act.OS == nil && act.Property == nil && act.File == nil {
+		return false
+	}
+
+	return (act.JDK == "" || a.matchesJDK(act.JDK)) &&
+		(act.OS == nil || a.matchesOS(act.OS)) &&
+		(act.Property == nil || a.matchesProperty(act.Property)) &&
+		(act.File == nil || a.matchesFile(act.File, dir))
+}
+
+func (a *activator) matchesProperty(p *ActivationProperty) bool {
+	name, negated := strings.CutPrefix(p.Name, "!")
+	if name == "" {
+		return false
+	}
+	value, defined := a.props[name]
+
+	if p.Value == "" {
+		return defined != negated
+	}
+	expected, negatedValue := strings.CutPrefix(p.Value, "!")
+	return (defined && value == expected) != negatedValue
+}
+
+func (a *activator) matchesJDK(spec string) bool {
+	version := a.props["java.version"]
+	if version == "" {
+		return false
+	}
+
+	spec, negated := strings.CutPrefix(spec, "!")
+	if strings.HasPrefix(spec, "[") || strings.HasPrefix(spec, "(") {
+		r, err := ParseVersionRange(spec)
+		return err == nil && r.Contains(version) != negated
+	}
+	return strings.HasPrefix(version, spec) != negated
+}
+
+func (a *activator) matchesOS(o *ActivationOS) bool {
+	name := strings.ToLower(orDefault(a.props["os.name"], osName()))
+	arch := strings.ToLower(orDefault(a.props["os.arch"], osArch()))
+	version := strings.ToLower(a.props["os.version"])
+
+	return matchesValue(o.Name, name) &&
+		matchesValue(o.Arch, arch) &&
+		matchesValue(o.Version, version) &&
+		(o.Family == "" || matchesFamily(o.Family, name))
+}
+
+// matchesValue compares a possibly negated expected value with actual,
+// ignoring case. An empty expectation always matches.
+func matchesValue(expected, actual string) bool {
+	if expected == "" {
+		return true
+	}
+	expected, negated := strings.CutPrefix(strings.ToLower(expected), "!")
+	return (expected == actual) != negated
+}
+
+func matchesFamily(family, name string) bool {
+	family, negated := strings.CutPrefix(strings.ToLower(family), "!")
+
+	var match bool
+	windows := strings.Contains(name, "windows")
+	switch family {
+	case "windows", "dos":
+		match = windows
+	case "mac":
+		match = strings.Contains(name, "mac")
+	case "unix":
+		match = !windows && !strings.Contains(name, "openvms")
+	default:
+		match = strings.Contains(name, family)
+	}
+	return match != negated
+}
+
+// osName returns the os.name Java reports for the running operating system.
+func osName() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "Mac OS X"
+	case "windows":
+		return "Windows"
+	case "linux":
+		return "Linux"
+	case "freebsd":
+		return "FreeBSD"
+	}
+	return runtime.GOOS
+}
+
+// osArch returns the os.arch Java reports for the running architecture.
+func osArch() string {
+	switch runtime.GOARCH {
+	case "arm64":
+		return "aarch64"
+	case "386":
+		return "x86"
+	}
+	return runtime.GOARCH
+}
+
+func (a *activator) matchesFile(f *ActivationFile, dir string) bool {
+	if f.Exists != "" {
+		path, ok := activationPath(f.Exists, dir)
+		if !ok {
+			return false
+		}
+		if _, err := os.Stat(path); err != nil {
+			return false
+		}
+	}
+	if f.Missing != "" {
+		path, ok := activationPath(f.Missing, dir)
+		if !ok {
+			return false
+		}
+		if _, err := os.Stat(path); err == nil {
+			return false
+		}
+	}
+	return f.Exists != "" || f.Missing != ""
+}
+
+// activationPath resolves the file of a file activation relative to dir,
+// where ${basedir} and ${project.basedir} stand for dir.
+func activationPath(path, dir string) (string, bool) {
+	path = strings.NewReplacer("${basedir}", dir, "${project.basedir}", dir).Replace(path)
+	if strings.Contains(path, "${") || !filepath.IsAbs(path) && dir == "" {
+		return "", false
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return path, true
+}
+
+// injectProfile merges the elements of an active profile into m, the profile
+// taking precedence.
+func injectProfile(m *Model, p *Profile) {
+	mg := merger{sourceDominant: true}
+
+	mergeField(mg, &m.Modules, p.Modules)
+	mergeField(mg, &m.Subprojects, p.Subprojects)
+	mergeField(mg, &m.DistributionManagement, p.DistributionManagement)
+	mergeField(mg, &m.Properties, p.Properties)
+	mergeField(mg, &m.DependencyManagement, p.DependencyManagement)
+	mergeField(mg, &m.Dependencies, p.Dependencies)
+	mergeField(mg, &m.Repositories, p.Repositories)
+	mergeField(mg, &m.PluginRepositories, p.PluginRepositories)
+	mergeField(mg, &m.Reports, p.Reports)
+	mergeField(mg, &m.Reporting, p.Reporting)
+
+	if p.Build != nil {
+		if m.Build == nil {
+			m.Build = &Build{}
+		}
+		mg.mergeInto(&m.Build.BuildBase, p.Build)
+	}
+}
+
+// mergeField merges source into the element *target, creating it if needed.
+func mergeField[T any](mg merger, target **T, source *T) {
+	mg.merge(reflect.ValueOf(target).Elem(), reflect.ValueOf(source))
+}
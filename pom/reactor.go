@@ -0,0 +1,280 @@
+package pom
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Reactor is a multi-module project: a POM and, recursively, the modules and
+// subprojects it lists, including those of its profiles. The POMs are held
+// as Documents so that edits keep their formatting.
+type Reactor struct {
+	// Projects are the POMs of the reactor, the root first, then the modules
+	// depth first in declaration order.
+	Projects []*Project
+	// Config is the .mvn/maven.config file next to the root POM, nil if
+	// there is none.
+	Config *MavenConfig
+	// Properties are user properties, like -D options on the command line,
+	// taking precedence over those of Config and of the POMs when versions
+	// are resolved.
+	Properties map[string]string
+}
+
+// Project is a POM of a Reactor.
+type Project struct {
+	// Path is the file of the POM.
+	Path string
+	// Model is the POM as it was loaded, without the edits of Document.
+	Model *Model
+	// Document is the POM with the edits made so far.
+	Document *Document
+
+	data []byte
+}
+
+// GroupId returns the groupId of the project as loaded, inherited from its parent if
+// it has none of its own.
+func (p *Project) GroupId() string {
+	return groupIdOf(p.Model)
+}
+
+// Version returns the version of the project as loaded, inherited from its parent if
+// it has none of its own.
+func (p *Project) Version() string {
+	return versionOf(p.Model)
+}
+
+func groupIdOf(m *Model) string {
+	if m.GroupId == "" && m.Parent != nil {
+		return m.Parent.GroupId
+	}
+	return m.GroupId
+}
+
+func versionOf(m *Model) string {
+	if m.Version == "" && m.Parent != nil {
+		return m.Parent.Version
+	}
+	return m.Version
+}
+
+// Modified reports whether Document was edited.
+func (p *Project) Modified() bool {
+	return !bytes.Equal(p.data, p.Document.Bytes())
+}
+
+// Changes returns the differences between Model and Document.
+func (p *Project) Changes() (Changes, error) {
+	edited, err := p.Document.Model()
+	if err != nil {
+		return nil, err
+	}
+	return Diff(p.Model, edited), nil
+}
+
+// LoadReactor reads the POM at path, its modules and the .mvn/maven.config
+// file next to it. path may be the directory of the POM.
+func LoadReactor(path string) (*Reactor, error) {
+	r := &Reactor{}
+	if err := r.load(path, make(map[string]bool)); err != nil {
+		return nil, err
+	}
+
+	c, err := ReadMavenConfig(mavenConfigPath(filepath.Dir(r.Root().Path)))
+	switch {
+	case err == nil:
+		r.Config = c
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Reactor) load(path string, seen map[string]bool) error {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, "pom.xml")
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if seen[abs] {
+		return nil
+	}
+	seen[abs] = true
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	d, err := ParseDocument(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	m, err := d.Model()
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	r.Projects = append(r.Projects, &Project{Path: path, Model: m, Document: d, data: data})
+
+	for _, module := range modulesOf(m) {
+		if err := r.load(filepath.Join(filepath.Dir(path), filepath.FromSlash(module)), seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// modulesOf returns the modules and subprojects of m and of its profiles.
+func modulesOf(m *Model) []string {
+	var modules []string
+	add := func(mods *Modules, subprojects *Subprojects) {
+		if mods != nil {
+			modules = append(modules, mods.Module...)
+		}
+		if subprojects != nil {
+			modules = append(modules, subprojects.Subproject...)
+		}
+	}
+
+	add(m.Modules, m.Subprojects)
+	if m.Profiles != nil {
+		for _, p := range m.Profiles.Profile {
+			add(p.Modules, p.Subprojects)
+		}
+	}
+	return modules
+}
+
+// Root returns the first project of r.
+func (r *Reactor) Root() *Project {
+	return r.Projects[0]
+}
+
+// project returns the project with groupId and artifactId, or nil.
+func (r *Reactor) project(groupId, artifactId string) *Project {
+	for _, p := range r.Projects {
+		if p.GroupId() == groupId && p.Model.ArtifactId == artifactId {
+			return p
+		}
+	}
+	return nil
+}
+
+// Select returns the projects of r chosen by selectors, in the order of r,
+// like the -pl option of Maven. A selector is the path of a project
+// relative to the root, or its [groupId]:artifactId. Selectors prefixed by
+// ! or - exclude projects; when all do, they exclude them from all the
+// projects of r. A selector matching no project is an error unless prefixed
+// by ?. No selectors select all the projects.
+func (r *Reactor) Select(selectors []string) ([]*Project, error) {
+	include := make(map[*Project]bool)
+	exclude := make(map[*Project]bool)
+	all := true
+	for _, selector := range selectors {
+		selector = strings.TrimSpace(selector)
+		excluded := false
+		if s, ok := strings.CutPrefix(selector, "!"); ok {
+			selector, excluded = s, true
+		} else if s, ok := strings.CutPrefix(selector, "-"); ok {
+			selector, excluded = s, true
+		} else {
+			selector = strings.TrimPrefix(selector, "+")
+			all = false
+		}
+		selector, optional := strings.CutPrefix(selector, "?")
+
+		p := r.selected(selector)
+		switch {
+		case p == nil && !optional:
+			return nil, fmt.Errorf("pom: no project %s in the reactor", selector)
+		case p == nil:
+		case excluded:
+			exclude[p] = true
+		default:
+			include[p] = true
+		}
+	}
+
+	var projects []*Project
+	for _, p := range r.Projects {
+		if (all || include[p]) && !exclude[p] {
+			projects = append(projects, p)
+		}
+	}
+	return projects, nil
+}
+
+// Selected returns the projects of r selected by the -pl options of Config,
+// all of them if there are none.
+func (r *Reactor) Selected() ([]*Project, error) {
+	if r.Config == nil {
+		return r.Select(nil)
+	}
+	return r.Select(r.Config.Projects)
+}
+
+// selected returns the project of r matching selector, or nil.
+func (r *Reactor) selected(selector string) *Project {
+	if groupId, artifactId, ok := strings.Cut(selector, ":"); ok {
+		for _, p := range r.Projects {
+			if (groupId == "" || p.GroupId() == groupId) && p.Model.ArtifactId == artifactId {
+				return p
+			}
+		}
+		return nil
+	}
+
+	root := filepath.Dir(r.Root().Path)
+	path := filepath.Join(root, filepath.FromSlash(selector))
+	for _, p := range r.Projects {
+		if filepath.Dir(p.Path) == path || p.Path == path {
+			return p
+		}
+	}
+	return nil
+}
+
+// Write writes the modified projects and Config back to their files, after
+// which the Model of the projects holds the edits.
+func (r *Reactor) Write() error {
+	var errs []error
+	if c := r.Config; c != nil && c.Modified() {
+		if err := writeFile(c.Path, c.data); err != nil {
+			errs = append(errs, err)
+		} else {
+			c.original = c.data
+		}
+	}
+	for _, p := range r.Projects {
+		if !p.Modified() {
+			continue
+		}
+		m, err := p.Document.Model()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := writeFile(p.Path, p.Document.Bytes()); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		p.Model, p.data = m, p.Document.Bytes()
+	}
+	return errors.Join(errs...)
+}
+
+// writeFile replaces the content of the existing file at path with data.
+func writeFile(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, info.Mode().Perm())
+}
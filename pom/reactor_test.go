@@ -0,0 +1,198 @@
+package pom_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+// copyReactor copies a reactor of testdata into a temporary directory and
+// loads it from there.
+func copyReactor(t *testing.T, name string) *pom.Reactor {
+	t.Helper()
+
+	dir := t.TempDir()
+	src := filepath.Join("testdata", name)
+	err := filepath.WalkDir(src, func(path string, e fs.DirEntry, err error) error {
+		if err != nil || e.IsDir() {
+			return err
+		}
+		rel, _ := filepath.Rel(src, path)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(rel)), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dir, rel), data, 0o644)
+	})
+	if err != nil {
+		t.Fatalf("Expected no errors copying the reactor, but found: %s", err.Error())
+	}
+	r, err := pom.LoadReactor(dir)
+	if err != nil {
+		t.Fatalf("Expected no errors loading the reactor, but found: %s", err.Error())
+	}
+	return r
+}
+
+func projectText(t *testing.T, r *pom.Reactor, artifactId string) string {
+	t.Helper()
+
+	for _, p := range r.Projects {
+		if p.Model.ArtifactId == artifactId {
+			return string(p.Document.Bytes())
+		}
+	}
+	t.Fatalf("Expected project %s in the reactor", artifactId)
+	return ""
+}
+
+func TestReactor(t *testing.T) {
+	t.Run("Should load the modules, including those of profiles", func(t *testing.T) {
+		r := copyReactor(t, "reactor")
+
+		var ids []string
+		for _, p := range r.Projects {
+			ids = append(ids, p.Model.ArtifactId)
+		}
+		if strings.Join(ids, ",") != "reactor,core,app,tools" {
+			t.Errorf("Expected reactor,core,app,tools, but found: %s", strings.Join(ids, ","))
+		}
+	})
+
+	t.Run("Should set the version of the projects and their references", func(t *testing.T) {
+		r := copyReactor(t, "reactor")
+
+		if err := r.SetVersion("1.1.0-SNAPSHOT"); err != nil {
+			t.Fatalf("Expected no errors setting the version, but found: %s", err.Error())
+		}
+
+		root := projectText(t, r, "reactor")
+		if !strings.Contains(root, "<artifactId>reactor</artifactId>\n  <version>1.1.0-SNAPSHOT</version>") {
+			t.Errorf("Expected the root version to be set, but found:\n%s", root)
+		}
+		if !strings.Contains(root, "<core.version>1.1.0-SNAPSHOT</core.version>") || !strings.Contains(root, "<version>${project.version}</version>") {
+			t.Errorf("Expected the property to be set and the expression kept, but found:\n%s", root)
+		}
+
+		app := projectText(t, r, "app")
+		if strings.Count(app, "1.1.0-SNAPSHOT") != 2 || !strings.Contains(app, "<artifactId>lib</artifactId>\n      <version>1.0.0-SNAPSHOT</version>") {
+			t.Errorf("Expected the parent and core versions to be set, but found:\n%s", app)
+		}
+
+		tools := projectText(t, r, "tools")
+		if !strings.Contains(tools, "<version>0.3.0</version>") || !strings.Contains(tools, "<version>1.1.0-SNAPSHOT</version>\n  </parent>") {
+			t.Errorf("Expected only the parent version to be set, but found:\n%s", tools)
+		}
+	})
+
+	t.Run("Should report the changes until written", func(t *testing.T) {
+		r := copyReactor(t, "reactor")
+		if err := r.SetVersion("2.0.0"); err != nil {
+			t.Fatalf("Expected no errors setting the version, but found: %s", err.Error())
+		}
+
+		changes, err := r.Projects[2].Changes()
+		if err != nil || len(changes) != 2 || changes[0].Path != "/project/parent/version" || changes[0].New != "2.0.0" {
+			t.Errorf("Expected the parent and dependency changes, but found: %v %v", changes, err)
+		}
+
+		if err := r.Write(); err != nil {
+			t.Fatalf("Expected no errors writing the reactor, but found: %s", err.Error())
+		}
+		if changes, _ := r.Projects[2].Changes(); len(changes) != 0 || r.Projects[2].Modified() {
+			t.Errorf("Expected no changes after writing, but found: %v", changes)
+		}
+		data, _ := os.ReadFile(r.Projects[2].Path)
+		if !strings.Contains(string(data), "<version>2.0.0</version>") {
+			t.Errorf("Expected the written version, but found:\n%s", data)
+		}
+	})
+
+	t.Run("Should select projects by path and coordinates", func(t *testing.T) {
+		r := copyReactor(t, "reactor")
+
+		for selectors, expected := range map[string]string{
+			"":                         "reactor,core,app,tools",
+			"app,:core":                "core,app",
+			"org.example:tools,./core": "core,tools",
+			"!app,-:reactor":           "core,tools",
+			"core,?missing":            "core",
+		} {
+			projects, err := r.Select(strings.FieldsFunc(selectors, func(r rune) bool { return r == ',' }))
+			var ids []string
+			for _, p := range projects {
+				ids = append(ids, p.Model.ArtifactId)
+			}
+			if err != nil || strings.Join(ids, ",") != expected {
+				t.Errorf("Expected %s for %s, but found: %v %v", expected, selectors, ids, err)
+			}
+		}
+		if _, err := r.Select([]string{"missing"}); err == nil {
+			t.Errorf("Expected an error for a missing project")
+		}
+	})
+
+	t.Run("Should select the projects of maven.config", func(t *testing.T) {
+		r := copyReactor(t, "reactor")
+		path := filepath.Join(filepath.Dir(r.Root().Path), ".mvn", "maven.config")
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		os.WriteFile(path, []byte("-pl app --projects=tools\n"), 0o644)
+
+		r, err := pom.LoadReactor(r.Root().Path)
+		if err != nil {
+			t.Fatalf("Expected no errors loading the reactor, but found: %s", err.Error())
+		}
+		projects, err := r.Selected()
+		if err != nil || len(projects) != 2 || projects[0].Model.ArtifactId != "app" || projects[1].Model.ArtifactId != "tools" {
+			t.Errorf("Expected app and tools, but found: %v %v", projects, err)
+		}
+	})
+}
+
+func TestRelease(t *testing.T) {
+	t.Run("Should compute the next development version", func(t *testing.T) {
+		for version, expected := range map[string]string{
+			"1.2.3":          "1.2.4-SNAPSHOT",
+			"1.2":            "1.3-SNAPSHOT",
+			"2.0-rc-1":       "2.0-rc-2-SNAPSHOT",
+			"1.0.9-SNAPSHOT": "1.0.10-SNAPSHOT",
+			"1.0.RELEASE":    "1.1.RELEASE-SNAPSHOT",
+			"2024.09":        "2024.10-SNAPSHOT",
+		} {
+			if next, err := pom.NextDevelopmentVersion(version); err != nil || next != expected {
+				t.Errorf("Expected %s after %s, but found: %s %v", expected, version, next, err)
+			}
+		}
+	})
+
+	t.Run("Should prepare the release and continue development", func(t *testing.T) {
+		r := copyReactor(t, "reactor")
+
+		release, err := r.PrepareRelease(nil)
+		if err != nil {
+			t.Fatalf("Expected no errors preparing the release, but found: %s", err.Error())
+		}
+		if *release != (pom.Release{Version: "1.0.0", DevelopmentVersion: "1.0.1-SNAPSHOT", Tag: "reactor-1.0.0"}) {
+			t.Errorf("Expected the default release, but found: %+v", release)
+		}
+		root := projectText(t, r, "reactor")
+		if !strings.Contains(root, "<tag>reactor-1.0.0</tag>") || !strings.Contains(root, "<version>1.0.0</version>") {
+			t.Errorf("Expected the release version and tag, but found:\n%s", root)
+		}
+
+		if err := r.ContinueDevelopment(release); err != nil {
+			t.Fatalf("Expected no errors continuing development, but found: %s", err.Error())
+		}
+		root = projectText(t, r, "reactor")
+		if !strings.Contains(root, "<tag>HEAD</tag>") || !strings.Contains(root, "<version>1.0.1-SNAPSHOT</version>") {
+			t.Errorf("Expected the development version and tag, but found:\n%s", root)
+		}
+	})
+}
@@ -0,0 +1,271 @@
+package pom
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// Mode selects how a Decoder treats elements and attributes that are not part
+// of the POM schema.
+type Mode int
+
+const (
+	// Lenient keeps unrecognized elements and attributes in the Any and
+	// AnyAttrs fields of the element they appear in, so they are written back
+	// out by Write.
+	Lenient Mode = iota
+	// Strict decodes the whole document and then fails with an
+	// *UnrecognizedError listing every unrecognized element and attribute.
+	Strict
+)
+
+// Unrecognized is an element or attribute that is not part of the POM schema.
+type Unrecognized struct {
+	// Path is the slash separated path to the element, such as
+	// /project/dependencies/dependancy. Attributes end in /@name.
+	Path   string
+	Attr   bool
+	Line   int
+	Column int
+}
+
+// A Decoder reads a POM document from an input stream.
+type Decoder struct {
+	// Mode defaults to Lenient.
+	Mode Mode
+
+	r            io.Reader
+	unrecognized []Unrecognized
+}
+
+// NewDecoder returns a Lenient decoder reading from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r}
+}
+
+// Decode reads the POM document into m.
+//
+// Documents in UTF-16 (with or without a byte order mark) and in the single
+// byte encodings ISO-8859-1, ISO-8859-15, windows-1252 and US-ASCII are
+// converted to UTF-8 as they are read; the encoding is kept in Model.Encoding.
+//
+// The returned error is a *SyntaxError, *RootElementError, *ModelVersionError,
+// *EncodingError or, in Strict mode, *UnrecognizedError.
+func (dec *Decoder) Decode(m *Model) error {
+	s := new(sniffer)
+	r, err := s.reader(dec.r)
+	if err != nil {
+		return err
+	}
+
+	d := xml.NewDecoder(r)
+	d.CharsetReader = s.charsetReader
+
+	t := &tracker{d: d}
+	err = t.decode(m)
+	dec.unrecognized = t.unrecognized
+	if err != nil {
+		return err
+	}
+	m.Encoding = s.encoding
+	m.AnyAttrs = removeNamespaceAttrs(m.AnyAttrs)
+
+	if dec.Mode == Strict && len(t.unrecognized) > 0 {
+		return &UnrecognizedError{Nodes: t.unrecognized}
+	}
+
+	return nil
+}
+
+// Unrecognized returns the elements and attributes outside the POM schema
+// found by the last call to Decode, in document order.
+func (dec *Decoder) Unrecognized() []Unrecognized {
+	return dec.unrecognized
+}
+
+// Read decodes a POM document from r in Lenient mode.
+func Read(r io.Reader) (*Model, error) {
+	m := New()
+	if err := NewDecoder(r).Decode(m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// ReadFile decodes the POM document stored at path.
+func ReadFile(path string) (*Model, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+// ReadFS decodes the POM document stored at path within fsys.
+func ReadFS(fsys fs.FS, path string) (*Model, error) {
+	f, err := fsys.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+func removeNamespaceAttrs(attrs []xml.Attr) []xml.Attr {
+	var kept []xml.Attr
+	for _, attr := range attrs {
+		if !isNamespaceAttr(attr) {
+			kept = append(kept, attr)
+		}
+	}
+	return kept
+}
+
+// tracker sits between the raw decoder and the decoder that fills the Model,
+// remembering where tokens of interest started so errors can point at them,
+// and which elements the Model has no field for.
+type tracker struct {
+	d      *xml.Decoder
+	depth  int
+	line   int
+	column int
+
+	modelVersionLine   int
+	modelVersionColumn int
+
+	// types holds the schema of every open element; nil for elements whose
+	// content is not checked.
+	types        []*elementType
+	path         []string
+	unrecognized []Unrecognized
+}
+
+func (t *tracker) Token() (xml.Token, error) {
+	t.line, t.column = t.d.InputPos()
+
+	tok, err := t.d.Token()
+	if err != nil {
+		return tok, err
+	}
+
+	switch el := tok.(type) {
+	case xml.StartElement:
+		if t.depth == 1 && el.Name.Local == "modelVersion" {
+			t.modelVersionLine, t.modelVersionColumn = t.line, t.column
+		}
+		t.depth++
+		t.push(el)
+	case xml.EndElement:
+		t.depth--
+		t.types = t.types[:len(t.types)-1]
+		t.path = t.path[:len(t.path)-1]
+	}
+
+	return tok, nil
+}
+
+func (t *tracker) push(el xml.StartElement) {
+	t.path = append(t.path, el.Name.Local)
+
+	var et *elementType
+	switch {
+	case len(t.types) == 0:
+		et = modelElementType()
+	case t.types[len(t.types)-1] == nil || t.types[len(t.types)-1].opaque:
+		// inside content that is not checked
+	default:
+		var ok bool
+		if et, ok = t.types[len(t.types)-1].elements[el.Name.Local]; !ok {
+			t.report(t.currentPath(), false)
+		}
+	}
+	t.types = append(t.types, et)
+
+	if et == nil || et.opaque {
+		return
+	}
+	for _, attr := range el.Attr {
+		if !isNamespaceAttr(attr) && !et.attrs[attr.Name.Local] {
+			t.report(t.currentPath()+"/@"+attr.Name.Local, true)
+		}
+	}
+}
+
+func (t *tracker) currentPath() string {
+	return "/" + strings.Join(t.path, "/")
+}
+
+func (t *tracker) report(path string, attr bool) {
+	t.unrecognized = append(t.unrecognized, Unrecognized{
+		Path:   path,
+		Attr:   attr,
+		Line:   t.line,
+		Column: t.column,
+	})
+}
+
+func (t *tracker) decode(m *Model) error {
+	d := xml.NewTokenDecoder(t)
+
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return &SyntaxError{Line: t.line, Column: t.column, Err: io.ErrUnexpectedEOF}
+		}
+		if err != nil {
+			return t.wrap(err)
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		if start.Name.Local != "project" {
+			return &RootElementError{Name: start.Name, Line: t.line, Column: t.column}
+		}
+
+		if err := d.DecodeElement(m, &start); err != nil {
+			return t.wrap(err)
+		}
+
+		break
+	}
+
+	if m.ModelVersion != "" && !supportedModelVersions[m.ModelVersion] {
+		return &ModelVersionError{
+			Version: m.ModelVersion,
+			Line:    t.modelVersionLine,
+			Column:  t.modelVersionColumn,
+		}
+	}
+
+	return nil
+}
+
+func (t *tracker) wrap(err error) error {
+	line, column := t.d.InputPos()
+
+	var encErr *EncodingError
+	if errors.As(err, &encErr) {
+		encErr.Line, encErr.Column = line, column
+		return encErr
+	}
+
+	// the decoder may have read past the line of a syntax error, its
+	// column would be that of another position
+	var synErr *xml.SyntaxError
+	if errors.As(err, &synErr) {
+		line, column = synErr.Line, 0
+	}
+
+	return &SyntaxError{Line: line, Column: column, Err: err}
+}
@@ -0,0 +1,155 @@
+package pom_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+func TestRead(t *testing.T) {
+	t.Run("Should read a pom from a reader", func(t *testing.T) {
+		m, err := pom.Read(bytes.NewReader(testPomFile))
+		if err != nil {
+			t.Errorf("Expected no errors reading pom, but found: %s", err.Error())
+			return
+		}
+
+		if m.ArtifactId != "your-project-name" {
+			t.Errorf("Expected artifactId your-project-name, but found: %s", m.ArtifactId)
+		}
+		if m.Properties.Fields["maven.compiler.source"] != "1.7" {
+			t.Errorf("Expected maven.compiler.source 1.7, but found: %s", m.Properties.Fields["maven.compiler.source"])
+		}
+	})
+
+	t.Run("Should read a pom from a file and a file system", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		setup(tmpDir)
+
+		if _, err := pom.ReadFile(filepath.Join(tmpDir, "pom.xml")); err != nil {
+			t.Errorf("Expected no errors reading pom file, but found: %s", err.Error())
+		}
+		if _, err := pom.ReadFS(os.DirFS(tmpDir), "pom.xml"); err != nil {
+			t.Errorf("Expected no errors reading pom from fs, but found: %s", err.Error())
+		}
+	})
+
+	t.Run("Should report malformed XML with its position", func(t *testing.T) {
+		_, err := pom.Read(strings.NewReader("<project>\n  <groupId>a</artifactId>\n</project>"))
+
+		var synErr *pom.SyntaxError
+		if !errors.As(err, &synErr) {
+			t.Errorf("Expected a SyntaxError, but found: %v", err)
+			return
+		}
+		if synErr.Line != 2 || synErr.Column != 0 {
+			t.Errorf("Expected error on line 2 without a column, but found: %d %d", synErr.Line, synErr.Column)
+		}
+		if msg := err.Error(); !strings.HasPrefix(msg, "pom: malformed XML at line 2: ") {
+			t.Errorf("Expected the line only in the message, but found: %s", msg)
+		}
+	})
+
+	t.Run("Should report an unexpected root element", func(t *testing.T) {
+		_, err := pom.Read(strings.NewReader("<?xml version=\"1.0\"?>\n<settings/>"))
+
+		var rootErr *pom.RootElementError
+		if !errors.As(err, &rootErr) {
+			t.Errorf("Expected a RootElementError, but found: %v", err)
+			return
+		}
+		if rootErr.Name.Local != "settings" || rootErr.Line != 2 {
+			t.Errorf("Expected <settings> on line 2, but found: <%s> on line %d", rootErr.Name.Local, rootErr.Line)
+		}
+	})
+
+	t.Run("Should report an unknown modelVersion", func(t *testing.T) {
+		_, err := pom.Read(strings.NewReader("<project>\n  <modelVersion>3.0.0</modelVersion>\n</project>"))
+
+		var versionErr *pom.ModelVersionError
+		if !errors.As(err, &versionErr) {
+			t.Errorf("Expected a ModelVersionError, but found: %v", err)
+			return
+		}
+		if versionErr.Version != "3.0.0" || versionErr.Line != 2 || versionErr.Column != 3 {
+			t.Errorf("Expected 3.0.0 at 2:3, but found: %s at %d:%d", versionErr.Version, versionErr.Line, versionErr.Column)
+		}
+	})
+
+	t.Run("Should report an unsupported encoding", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"pom.xml": {Data: []byte("<?xml version=\"1.0\" encoding=\"EBCDIC-XYZ\"?>\n<project/>")},
+		}
+		_, err := pom.ReadFS(fsys, "pom.xml")
+
+		var encErr *pom.EncodingError
+		if !errors.As(err, &encErr) {
+			t.Errorf("Expected an EncodingError, but found: %v", err)
+			return
+		}
+		if encErr.Encoding != "EBCDIC-XYZ" || encErr.Line != 1 {
+			t.Errorf("Expected EBCDIC-XYZ on line 1, but found: %s on line %d", encErr.Encoding, encErr.Line)
+		}
+	})
+}
+
+func TestWrite(t *testing.T) {
+	m, err := pom.Read(bytes.NewReader(testPomFile))
+	if err != nil {
+		t.Fatalf("Expected no errors reading pom, but found: %s", err.Error())
+	}
+
+	t.Run("Should write the schema header and declaration by default", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := m.Write(&buf, nil); err != nil {
+			t.Errorf("Expected no errors writing pom, but found: %s", err.Error())
+			return
+		}
+
+		out := buf.String()
+		if !strings.HasPrefix(out, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<project xmlns=\"http://maven.apache.org/POM/4.0.0\"") {
+			t.Errorf("Expected declaration and namespace, but found: %s", out)
+		}
+		if !strings.Contains(out, "\n  <modelVersion>4.0.0</modelVersion>\n") {
+			t.Errorf("Expected two space indentation, but found: %s", out)
+		}
+	})
+
+	t.Run("Should honor write options", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := m.Write(&buf, &pom.WriteOptions{}); err != nil {
+			t.Errorf("Expected no errors writing pom, but found: %s", err.Error())
+			return
+		}
+
+		out := buf.String()
+		if !strings.HasPrefix(out, "<project><modelVersion>") {
+			t.Errorf("Expected a bare single line document, but found: %s", out)
+		}
+	})
+
+	t.Run("Should round trip properties in document order", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := m.Write(&buf, nil); err != nil {
+			t.Errorf("Expected no errors writing pom, but found: %s", err.Error())
+			return
+		}
+
+		again, err := pom.Read(&buf)
+		if err != nil {
+			t.Errorf("Expected no errors reading written pom, but found: %s", err.Error())
+			return
+		}
+
+		keys := strings.Join(again.Properties.Keys(), ",")
+		if keys != "maven.compiler.source,maven.compiler.target,project.build.sourceEncoding" {
+			t.Errorf("Expected properties in document order, but found: %s", keys)
+		}
+	})
+}
@@ -0,0 +1,231 @@
+package pom
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SetVersion changes the version of the projects of r that have the version
+// of the root to version, like the set goal of the Maven versions plugin. The
+// references to those projects are updated along: the parents of the
+// modules, and the dependencies and plugins with the old version, or with a
+// property holding it. When the version of the root is made of CI-friendly
+// properties, like ${revision}, those are set instead, in Config if it
+// defines them. Versions given by other expressions, like
+// ${project.version}, are left alone.
+func (r *Reactor) SetVersion(version string) error {
+	models, err := r.models()
+	if err != nil {
+		return err
+	}
+
+	old, err := r.resolveVersion(r.Root(), models)
+	if err != nil {
+		return err
+	}
+
+	changed := make(map[string]bool)
+	for p, m := range models {
+		if v, err := r.resolveVersion(p, models); err == nil && v == old {
+			changed[groupIdOf(m)+":"+m.ArtifactId] = true
+		}
+	}
+
+	if expression := versionOf(models[r.Root()]); expression != old {
+		if err := r.setCIFriendly(expression, version, models); err != nil {
+			return err
+		}
+	}
+
+	var errs []error
+	for _, p := range r.Projects {
+		if err := r.setVersion(p, models, old, version, changed); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.Path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Reactor) setVersion(p *Project, models map[*Project]*Model, old, version string, changed map[string]bool) error {
+	m := models[p]
+
+	var edits []func() error
+	setAt := func(path, current string) {
+		switch {
+		case current == old:
+			edits = append(edits, func() error { return p.Document.Set(path, version) })
+		case isCIFriendly(current):
+			// Set along with the version of the root.
+		case strings.HasPrefix(current, "${") && strings.HasSuffix(current, "}"):
+			name := current[2 : len(current)-1]
+			if owner := r.propertyOwner(p, models, name); owner != nil && models[owner].Properties.Fields[name] == old {
+				edits = append(edits, func() error { return owner.Document.Set("/project/properties/"+name, version) })
+			}
+		}
+	}
+
+	if changed[groupIdOf(m)+":"+m.ArtifactId] && m.Version != "" {
+		setAt("/project/version", m.Version)
+	}
+	m.Walk(func(path string, element any) {
+		switch e := element.(type) {
+		case *Parent:
+			if changed[e.GroupId+":"+e.ArtifactId] {
+				setAt(path+"/version", e.Version)
+			}
+		case *Dependency:
+			if changed[e.GroupId+":"+e.ArtifactId] {
+				setAt(path+"/version", e.Version)
+			}
+		case *Plugin:
+			if changed[e.Key()] {
+				setAt(path+"/version", e.Version)
+			}
+		}
+	})
+
+	for _, edit := range edits {
+		if err := edit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// propertyOwner returns the project defining the property name for p: p
+// itself or its closest parent within the reactor.
+func (r *Reactor) propertyOwner(p *Project, models map[*Project]*Model, name string) *Project {
+	seen := make(map[*Project]bool)
+	for p != nil && !seen[p] {
+		seen[p] = true
+		m := models[p]
+		if m.Properties != nil {
+			if _, ok := m.Properties.Fields[name]; ok {
+				return p
+			}
+		}
+		if m.Parent == nil {
+			return nil
+		}
+		p = r.project(m.Parent.GroupId, m.Parent.ArtifactId)
+	}
+	return nil
+}
+
+// SetScmTag sets the scm tag of the projects of r that declare an scm
+// element of their own.
+func (r *Reactor) SetScmTag(tag string) error {
+	var errs []error
+	for _, p := range r.Projects {
+		if !p.Document.Has("/project/scm") {
+			continue
+		}
+		if err := p.Document.Set("/project/scm/tag", tag); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.Path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NextDevelopmentVersion returns the SNAPSHOT version that follows the
+// release version, like the release plugin suggests it: the last number of
+// version incremented, such as 1.2.4-SNAPSHOT for 1.2.3 and 2.0-rc-2-SNAPSHOT
+// for 2.0-rc-1.
+func NextDevelopmentVersion(version string) (string, error) {
+	version = ReleaseVersion(version)
+
+	end := len(version)
+	for end > 0 && (version[end-1] < '0' || version[end-1] > '9') {
+		end--
+	}
+	start := end
+	for start > 0 && version[start-1] >= '0' && version[start-1] <= '9' {
+		start--
+	}
+	if start == end {
+		return "", fmt.Errorf("pom: version %q has no number to increment", version)
+	}
+
+	n, err := strconv.ParseUint(version[start:end], 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("pom: invalid version %q: %w", version, err)
+	}
+	next := strconv.FormatUint(n+1, 10)
+	if pad := end - start - len(next); pad > 0 {
+		next = strings.Repeat("0", pad) + next
+	}
+	return version[:start] + next + version[end:] + snapshotSuffix, nil
+}
+
+// ReleaseOptions configures Reactor.PrepareRelease. Empty fields take the
+// defaults of the release plugin.
+type ReleaseOptions struct {
+	// ReleaseVersion defaults to the resolved version of the root without
+	// -SNAPSHOT.
+	ReleaseVersion string
+	// DevelopmentVersion defaults to NextDevelopmentVersion of the release
+	// version.
+	DevelopmentVersion string
+	// Tag defaults to artifactId-version of the root.
+	Tag string
+}
+
+// Release describes a release prepared by Reactor.PrepareRelease.
+type Release struct {
+	Version            string
+	DevelopmentVersion string
+	Tag                string
+}
+
+// PrepareRelease sets the release version and scm tag in r, like the
+// prepare goal of the release plugin before it commits and tags the
+// release. Once that is done, r is ready for ContinueDevelopment. A nil opts
+// takes all the defaults.
+func (r *Reactor) PrepareRelease(opts *ReleaseOptions) (*Release, error) {
+	if opts == nil {
+		opts = &ReleaseOptions{}
+	}
+
+	release := &Release{
+		Version:            opts.ReleaseVersion,
+		DevelopmentVersion: opts.DevelopmentVersion,
+		Tag:                opts.Tag,
+	}
+	if release.Version == "" {
+		current, err := r.Version()
+		if err != nil {
+			return nil, err
+		}
+		release.Version = ReleaseVersion(current)
+	}
+	if release.DevelopmentVersion == "" {
+		next, err := NextDevelopmentVersion(release.Version)
+		if err != nil {
+			return nil, err
+		}
+		release.DevelopmentVersion = next
+	}
+	if release.Tag == "" {
+		release.Tag = r.Root().Model.ArtifactId + "-" + release.Version
+	}
+
+	if err := r.SetVersion(release.Version); err != nil {
+		return nil, err
+	}
+	if err := r.SetScmTag(release.Tag); err != nil {
+		return nil, err
+	}
+	return release, nil
+}
+
+// ContinueDevelopment sets the development version of release in r, and
+// resets the scm tag to HEAD, like the release plugin after it tagged the
+// release.
+func (r *Reactor) ContinueDevelopment(release *Release) error {
+	if err := r.SetVersion(release.DevelopmentVersion); err != nil {
+		return err
+	}
+	return r.SetScmTag("HEAD")
+}
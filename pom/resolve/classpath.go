@@ -0,0 +1,247 @@
+package resolve
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/obscurelyme/encoding/manifest"
+	"github.com/obscurelyme/encoding/pom"
+)
+
+// PathScope is a step of a build that sees some of the dependencies of a
+// project, named like in Maven 4.
+type PathScope string
+
+const (
+	// MainCompile is the compilation of the main sources: compile,
+	// compile-only, provided and system dependencies.
+	MainCompile PathScope = "main-compile"
+	// MainRuntime is the execution of the main classes: compile and runtime
+	// dependencies.
+	MainRuntime PathScope = "main-runtime"
+	// TestCompile is the compilation of the test sources: compile, provided,
+	// system, test and test-only dependencies.
+	TestCompile PathScope = "test-compile"
+	// TestRuntime is the execution of the tests: all the dependencies but
+	// compile-only and test-only ones.
+	TestRuntime PathScope = "test-runtime"
+)
+
+// pathScopes are the dependency scopes of each PathScope.
+var pathScopes = map[PathScope][]string{
+	MainCompile: {pom.ScopeCompile, pom.ScopeCompileOnly, pom.ScopeProvided, pom.ScopeSystem},
+	MainRuntime: {pom.ScopeCompile, pom.ScopeRuntime},
+	TestCompile: {pom.ScopeCompile, pom.ScopeProvided, pom.ScopeSystem, pom.ScopeTest, pom.ScopeTestOnly},
+	TestRuntime: {pom.ScopeCompile, pom.ScopeRuntime, pom.ScopeProvided, pom.ScopeSystem, pom.ScopeTest, pom.ScopeTestRuntime},
+}
+
+// ParsePathScope returns the PathScope named s, accepting the classpath
+// names of Maven 3 too: compile, runtime and test, the latter for
+// TestRuntime.
+func ParsePathScope(s string) (PathScope, error) {
+	switch s {
+	case "compile":
+		return MainCompile, nil
+	case "runtime":
+		return MainRuntime, nil
+	case "test":
+		return TestRuntime, nil
+	}
+	if _, ok := pathScopes[PathScope(s)]; ok {
+		return PathScope(s), nil
+	}
+	return "", fmt.Errorf("resolve: unknown path scope %q", s)
+}
+
+// Placement selects whether dependencies go on the module path or the
+// classpath.
+type Placement int
+
+const (
+	// PlaceClasspath puts every dependency on the classpath, unless its type
+	// is modular-jar.
+	PlaceClasspath Placement = iota
+	// PlaceModulePath puts every dependency on the module path, unless its
+	// type is classpath-jar.
+	PlaceModulePath
+	// PlaceAuto puts the jars that are modules, with a module descriptor or
+	// an Automatic-Module-Name, on the module path and the others on the
+	// classpath, like the compiler plugin does for modular projects. It
+	// reads the jars.
+	PlaceAuto
+)
+
+// ClasspathOptions configures Node.Classpath.
+type ClasspathOptions struct {
+	// Repository holds the files of the dependencies.
+	Repository pom.LocalRepository
+	// Placement defaults to PlaceClasspath.
+	Placement Placement
+	// Basedir is the directory of the project. When set, the output
+	// directories of the project come first, those of its build or
+	// target/classes and target/test-classes.
+	Basedir string
+}
+
+// Entry is a file on a classpath or module path.
+type Entry struct {
+	// Node is the dependency of the file, nil for the output directories of
+	// the project.
+	Node *Node
+	Path string
+	// ModulePath is whether the file goes on the module path.
+	ModulePath bool
+}
+
+// Classpath is the files a step of a build sees, in order.
+type Classpath []Entry
+
+// ClassPath returns the files of c on the classpath, joined by the path list
+// separator of the operating system.
+func (c Classpath) ClassPath() string {
+	return c.join(false)
+}
+
+// ModulePath returns the files of c on the module path, joined by the path
+// list separator of the operating system.
+func (c Classpath) ModulePath() string {
+	return c.join(true)
+}
+
+func (c Classpath) join(modulePath bool) string {
+	var paths []string
+	for _, e := range c {
+		if e.ModulePath == modulePath {
+			paths = append(paths, e.Path)
+		}
+	}
+	return strings.Join(paths, string(os.PathListSeparator))
+}
+
+// Classpath returns the files of the graph below n, the root of a graph
+// returned by Resolve, that the build step scope sees, in the order Maven
+// puts them: depth first in declaration order. Omitted nodes and types that
+// are not added to the classpath, like pom, are left out.
+func (n *Node) Classpath(scope PathScope, opts *ClasspathOptions) (Classpath, error) {
+	scopes, ok := pathScopes[scope]
+	if !ok {
+		return nil, fmt.Errorf("resolve: unknown path scope %q", scope)
+	}
+	if opts == nil {
+		opts = &ClasspathOptions{}
+	}
+
+	var cp Classpath
+	if opts.Basedir != "" {
+		cp = append(cp, outputDirectories(n.Model, scope, opts.Basedir)...)
+	}
+
+	var err error
+	n.Walk(func(c *Node) {
+		d := &c.Dependency
+		if c == n || c.Omitted != "" || err != nil || !contains(scopes, d.Scope) || !pom.LookupArtifactHandler(d.Type).AddedToClasspath {
+			return
+		}
+
+		e := Entry{Node: c, Path: opts.Repository.ArtifactPath(d)}
+		switch {
+		case d.Type == "modular-jar":
+			e.ModulePath = true
+		case d.Type == "classpath-jar":
+		case opts.Placement == PlaceModulePath:
+			e.ModulePath = true
+		case opts.Placement == PlaceAuto:
+			e.ModulePath, err = isModule(e.Path)
+		}
+		cp = append(cp, e)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+// outputDirectories returns the output directories of the project of m in
+// basedir that scope sees.
+func outputDirectories(m *pom.Model, scope PathScope, basedir string) []Entry {
+	output, testOutput := OutputDirectories(m, basedir)
+	switch scope {
+	case TestCompile, TestRuntime:
+		return []Entry{{Path: testOutput}, {Path: output}}
+	}
+	return []Entry{{Path: output}}
+}
+
+// OutputDirectories returns the directories the classes and test classes
+// of the project of m in basedir are compiled to: those of its build, or
+// target/classes and target/test-classes.
+func OutputDirectories(m *pom.Model, basedir string) (output, testOutput string) {
+	output, testOutput = "target/classes", "target/test-classes"
+	if m != nil && m.Build != nil {
+		output = orDefault(m.Build.OutputDirectory, output)
+		testOutput = orDefault(m.Build.TestOutputDirectory, testOutput)
+	}
+	dir := func(p string) string {
+		if filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(basedir, filepath.FromSlash(p))
+	}
+	return dir(output), dir(testOutput)
+}
+
+// isModule reports whether the jar at path has a module descriptor or an
+// Automatic-Module-Name. Directories are not modules.
+func isModule(p string) (bool, error) {
+	if info, err := os.Stat(p); err == nil && info.IsDir() {
+		return false, nil
+	}
+
+	r, err := zip.OpenReader(p)
+	if err != nil {
+		return false, fmt.Errorf("resolve: cannot read %s: %w", p, err)
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		switch {
+		case f.Name == "module-info.class":
+			return true, nil
+		case strings.HasPrefix(f.Name, "META-INF/versions/") && path.Base(f.Name) == "module-info.class":
+			return true, nil
+		case f.Name == "META-INF/MANIFEST.MF":
+			named, err := hasAutomaticModuleName(f)
+			if err != nil || named {
+				return named, err
+			}
+		}
+	}
+	return false, nil
+}
+
+func hasAutomaticModuleName(f *zip.File) (bool, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return false, err
+	}
+	defer rc.Close()
+
+	mf, err := manifest.Read(rc)
+	if err != nil {
+		return false, err
+	}
+	return mf.AutomaticModuleName() != "", nil
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package resolve_test
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+	"github.com/obscurelyme/encoding/pom/resolve"
+)
+
+// artifactIds returns the artifactIds of the dependencies of cp, with the
+// output directories of the project as their path.
+func artifactIds(cp resolve.Classpath) string {
+	var ids []string
+	for _, e := range cp {
+		if e.Node == nil {
+			ids = append(ids, filepath.ToSlash(e.Path))
+		} else {
+			ids = append(ids, e.Node.Dependency.ArtifactId)
+		}
+	}
+	return strings.Join(ids, " ")
+}
+
+// writeJar writes a jar holding the named files, empty, to path.
+func writeJar(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for name, content := range files {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClasspath(t *testing.T) {
+	root := resolveProject(t, true)
+
+	t.Run("Should follow the scope matrix", func(t *testing.T) {
+		for scope, expected := range map[resolve.PathScope]string{
+			resolve.MainCompile: "web core ranged lib common",
+			resolve.MainRuntime: "web core logging ranged lib common",
+			resolve.TestCompile: "web core ranged util lib common",
+			resolve.TestRuntime: "web core logging ranged util lib common",
+		} {
+			cp, err := root.Classpath(scope, &resolve.ClasspathOptions{Repository: repository})
+			if err != nil {
+				t.Errorf("Expected no errors computing the %s classpath, but found: %s", scope, err.Error())
+				continue
+			}
+			if ids := artifactIds(cp); ids != expected {
+				t.Errorf("Expected %s classpath %s, but found: %s", scope, expected, ids)
+			}
+		}
+	})
+
+	t.Run("Should locate the files in the repository", func(t *testing.T) {
+		cp, err := root.Classpath(resolve.MainRuntime, &resolve.ClasspathOptions{Repository: repository})
+		if err != nil {
+			t.Fatalf("Expected no errors, but found: %s", err.Error())
+		}
+		expected := filepath.Join("testdata", "repository", "org", "example", "web", "1.0", "web-1.0.jar")
+		if cp[0].Path != expected {
+			t.Errorf("Expected %s, but found: %s", expected, cp[0].Path)
+		}
+		if cp.ModulePath() != "" || len(strings.Split(cp.ClassPath(), string(os.PathListSeparator))) != len(cp) {
+			t.Errorf("Expected everything on the classpath, but found: %s", cp.ClassPath())
+		}
+	})
+
+	t.Run("Should put the output directories first", func(t *testing.T) {
+		cp, err := root.Classpath(resolve.TestCompile, &resolve.ClasspathOptions{Repository: repository, Basedir: "app"})
+		if err != nil {
+			t.Fatalf("Expected no errors, but found: %s", err.Error())
+		}
+		expected := "app/target/test-classes app/target/classes web core ranged util lib common"
+		if ids := artifactIds(cp); ids != expected {
+			t.Errorf("Expected %s, but found: %s", expected, ids)
+		}
+	})
+
+	t.Run("Should place modules on the module path", func(t *testing.T) {
+		dir := t.TempDir()
+		local := pom.LocalRepository{Dir: dir}
+		root.Walk(func(n *resolve.Node) {
+			if n != root {
+				writeJar(t, local.ArtifactPath(&n.Dependency), map[string]string{"Empty.class": ""})
+			}
+		})
+		writeJar(t, local.Path("org.example", "core", "1.0", "", "jar"), map[string]string{"META-INF/versions/9/module-info.class": ""})
+		writeJar(t, local.Path("org.example", "common", "1.0", "", "jar"), map[string]string{"META-INF/MANIFEST.MF": "Manifest-Version: 1.0\r\nAutomatic-Module-Name: org.example.common\r\n"})
+
+		cp, err := root.Classpath(resolve.MainRuntime, &resolve.ClasspathOptions{Repository: local, Placement: resolve.PlaceAuto})
+		if err != nil {
+			t.Fatalf("Expected no errors, but found: %s", err.Error())
+		}
+		var modules []string
+		for _, e := range cp {
+			if e.ModulePath {
+				modules = append(modules, e.Node.Dependency.ArtifactId)
+			}
+		}
+		if strings.Join(modules, " ") != "core common" {
+			t.Errorf("Expected core and common on the module path, but found: %s", modules)
+		}
+
+		cp, _ = root.Classpath(resolve.MainRuntime, &resolve.ClasspathOptions{Repository: local, Placement: resolve.PlaceModulePath})
+		if cp.ClassPath() != "" {
+			t.Errorf("Expected everything on the module path, but found: %s", cp.ClassPath())
+		}
+	})
+
+	t.Run("Should report missing jars when detecting modules", func(t *testing.T) {
+		if _, err := root.Classpath(resolve.MainRuntime, &resolve.ClasspathOptions{Repository: repository, Placement: resolve.PlaceAuto}); err == nil {
+			t.Errorf("Expected an error")
+		}
+	})
+
+	t.Run("Should parse path scopes", func(t *testing.T) {
+		for s, expected := range map[string]resolve.PathScope{"compile": resolve.MainCompile, "test": resolve.TestRuntime, "test-compile": resolve.TestCompile} {
+			if scope, err := resolve.ParsePathScope(s); err != nil || scope != expected {
+				t.Errorf("Expected %s for %s, but found: %s %v", expected, s, scope, err)
+			}
+		}
+		if _, err := resolve.ParsePathScope("import"); err == nil {
+			t.Errorf("Expected an error")
+		}
+	})
+}
@@ -0,0 +1,169 @@
+package resolve
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// edge links a node to one of its children in the graph formats.
+type edge struct {
+	from, to int
+	child    *Node
+}
+
+// numbered returns the nodes below n in walk order, n first, and the edges
+// between them by index.
+func numbered(n *Node) ([]*Node, []edge) {
+	var nodes []*Node
+	var edges []edge
+	index := make(map[*Node]int)
+	n.Walk(func(c *Node) {
+		index[c] = len(nodes)
+		nodes = append(nodes, c)
+		if c.parent != nil {
+			edges = append(edges, edge{from: index[c.parent], to: index[c], child: c})
+		}
+	})
+	return nodes, edges
+}
+
+// WriteDOT writes the graph below n to w in the DOT language of Graphviz.
+// Omitted nodes and the edges to them are dashed, and edges are labeled with
+// the annotations of the child.
+func (n *Node) WriteDOT(w io.Writer) error {
+	quote := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	nodes, edges := numbered(n)
+
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "digraph \"%s\" {\n", quote.Replace(n.String()))
+	for i, c := range nodes {
+		style := ""
+		if c.Omitted != "" {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(b, "  n%d [label=\"%s\"%s];\n", i, quote.Replace(c.String()), style)
+	}
+	for _, e := range edges {
+		var attrs []string
+		if notes := e.child.Notes(); len(notes) > 0 {
+			attrs = append(attrs, fmt.Sprintf("label=\"%s\"", quote.Replace(strings.Join(notes, "; "))))
+		}
+		if e.child.Omitted != "" {
+			attrs = append(attrs, "style=dashed")
+		}
+		if len(attrs) > 0 {
+			fmt.Fprintf(b, "  n%d -> n%d [%s];\n", e.from, e.to, strings.Join(attrs, ", "))
+		} else {
+			fmt.Fprintf(b, "  n%d -> n%d;\n", e.from, e.to)
+		}
+	}
+	b.WriteString("}\n")
+	return b.Flush()
+}
+
+// WriteMermaid writes the graph below n to w as a Mermaid flowchart.
+// Omitted nodes are linked with dotted arrows, and arrows are labeled with
+// the annotations of the child.
+func (n *Node) WriteMermaid(w io.Writer) error {
+	quote := strings.NewReplacer(`"`, "#quot;")
+	nodes, edges := numbered(n)
+
+	b := bufio.NewWriter(w)
+	b.WriteString("graph TD\n")
+	for i, c := range nodes {
+		fmt.Fprintf(b, "  n%d[\"%s\"]\n", i, quote.Replace(c.String()))
+	}
+	for _, e := range edges {
+		arrow := "-->"
+		if e.child.Omitted != "" {
+			arrow = "-.->"
+		}
+		if notes := e.child.Notes(); len(notes) > 0 {
+			arrow += "|\"" + quote.Replace(strings.Join(notes, "; ")) + "\"|"
+		}
+		fmt.Fprintf(b, "  n%d %s n%d\n", e.from, arrow, e.to)
+	}
+	return b.Flush()
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"http://graphml.graphdrawing.org/xmlns graphml"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	Id   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	Id          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	Id   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteGraphML writes the graph below n to w in GraphML. Nodes have a label
+// and an omitted attribute, edges the scope of their target and its
+// annotations.
+func (n *Node) WriteGraphML(w io.Writer) error {
+	nodes, edges := numbered(n)
+
+	g := graphML{
+		Keys: []graphMLKey{
+			{Id: "label", For: "node", Name: "label", Type: "string"},
+			{Id: "omitted", For: "node", Name: "omitted", Type: "string"},
+			{Id: "scope", For: "edge", Name: "scope", Type: "string"},
+			{Id: "notes", For: "edge", Name: "notes", Type: "string"},
+		},
+		Graph: graphMLGraph{Id: n.String(), EdgeDefault: "directed"},
+	}
+	for i, c := range nodes {
+		node := graphMLNode{Id: fmt.Sprintf("n%d", i), Data: []graphMLData{{Key: "label", Value: c.String()}}}
+		if c.Omitted != "" {
+			node.Data = append(node.Data, graphMLData{Key: "omitted", Value: string(c.Omitted)})
+		}
+		g.Graph.Nodes = append(g.Graph.Nodes, node)
+	}
+	for _, e := range edges {
+		edge := graphMLEdge{Source: fmt.Sprintf("n%d", e.from), Target: fmt.Sprintf("n%d", e.to)}
+		edge.Data = append(edge.Data, graphMLData{Key: "scope", Value: e.child.Dependency.Scope})
+		if notes := e.child.Notes(); len(notes) > 0 {
+			edge.Data = append(edge.Data, graphMLData{Key: "notes", Value: strings.Join(notes, "; ")})
+		}
+		g.Graph.Edges = append(g.Graph.Edges, edge)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	if err := e.Encode(g); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,54 @@
+package resolve
+
+import (
+	"encoding/json"
+	"io"
+)
+
+type jsonNode struct {
+	GroupId           string      `json:"groupId"`
+	ArtifactId        string      `json:"artifactId"`
+	Version           string      `json:"version"`
+	Type              string      `json:"type"`
+	Classifier        string      `json:"classifier,omitempty"`
+	Scope             string      `json:"scope,omitempty"`
+	Optional          bool        `json:"optional,omitempty"`
+	PremanagedVersion string      `json:"premanagedVersion,omitempty"`
+	PremanagedScope   string      `json:"premanagedScope,omitempty"`
+	ScopeUpdatedFrom  string      `json:"scopeUpdatedFrom,omitempty"`
+	Omitted           Omission    `json:"omitted,omitempty"`
+	WinnerVersion     string      `json:"winnerVersion,omitempty"`
+	Children          []*jsonNode `json:"children,omitempty"`
+}
+
+func newJSONNode(n *Node) *jsonNode {
+	d := &n.Dependency
+	j := &jsonNode{
+		GroupId:           d.GroupId,
+		ArtifactId:        d.ArtifactId,
+		Version:           d.Version,
+		Type:              d.Type,
+		Classifier:        d.Classifier,
+		Scope:             d.Scope,
+		Optional:          d.Optional == "true",
+		PremanagedVersion: n.PremanagedVersion,
+		PremanagedScope:   n.PremanagedScope,
+		ScopeUpdatedFrom:  n.ScopeUpdatedFrom,
+		Omitted:           n.Omitted,
+	}
+	if n.Winner != nil {
+		j.WinnerVersion = n.Winner.Dependency.Version
+	}
+	for _, c := range n.Children {
+		j.Children = append(j.Children, newJSONNode(c))
+	}
+	return j
+}
+
+// WriteJSON writes the graph below n to w as nested JSON objects with the
+// coordinates, scope and annotations of each node and its children.
+func (n *Node) WriteJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(newJSONNode(n))
+}
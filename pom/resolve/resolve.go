@@ -0,0 +1,502 @@
+// Package resolve resolves the transitive dependencies of a project the way
+// Maven does: the nearest declaration of a dependency wins, the first one
+// among those at the same depth, the dependency management of the project
+// applies to all of them, and scopes propagate along Maven's scope matrix.
+package resolve
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+// Node is a dependency in a resolved graph.
+type Node struct {
+	// Dependency holds the resolved version and scope of the dependency. The
+	// root of a graph holds the coordinates of the project, with its
+	// packaging as type and no scope.
+	Dependency pom.Dependency
+	// Model is the effective POM of the dependency, nil for system
+	// dependencies.
+	Model    *pom.Model
+	Children []*Node
+
+	// PremanagedVersion and PremanagedScope are the version and scope the
+	// dependency was declared with, when the dependency management of the
+	// project changed them.
+	PremanagedVersion string
+	PremanagedScope   string
+	// ScopeUpdatedFrom is the scope the dependency would have had if it had
+	// not been widened to the scope of a dependency it won against.
+	ScopeUpdatedFrom string
+	// Omitted is why the node is not part of the graph in verbose graphs,
+	// where the dependencies omitted in favour of a nearer one are kept.
+	// Omitted nodes have no children, except in full graphs.
+	Omitted Omission
+	// Winner is the node an omitted node lost against, nil for the nodes
+	// OmittedWithParent.
+	Winner *Node
+
+	parent *Node
+	// declared is the scope of the dependency in the POM of its parent,
+	// after dependency management.
+	declared string
+	// losers are the parents of the dependencies this node won against, and
+	// the scopes declared there, for scope widening.
+	losers []loser
+}
+
+type loser struct {
+	parent   *Node
+	declared string
+}
+
+// Omission is why a dependency is omitted from a graph.
+type Omission string
+
+const (
+	// OmittedForDuplicate marks dependencies resolved nearer to the root
+	// with the same version.
+	OmittedForDuplicate Omission = "duplicate"
+	// OmittedForConflict marks dependencies resolved nearer to the root with
+	// another version.
+	OmittedForConflict Omission = "conflict"
+	// OmittedForCycle marks dependencies on one of their own ancestors.
+	OmittedForCycle Omission = "cycle"
+	// OmittedWithParent marks the dependencies of omitted nodes in full
+	// graphs that are not resolved anywhere else.
+	OmittedWithParent Omission = "parent"
+)
+
+// omission returns why a dependency of parent with version loses against
+// winner.
+func omission(parent, winner *Node, version string) Omission {
+	for n := parent; n != nil; n = n.parent {
+		if n == winner {
+			return OmittedForCycle
+		}
+	}
+	if version == winner.Dependency.Version {
+		return OmittedForDuplicate
+	}
+	return OmittedForConflict
+}
+
+// Parent returns the node that depends on n, nil for the root.
+func (n *Node) Parent() *Node {
+	return n.parent
+}
+
+// Walk calls fn for n and its descendants, depth first in declaration order.
+// The omitted nodes of verbose graphs are visited too.
+func (n *Node) Walk(fn func(n *Node)) {
+	fn(n)
+	for _, c := range n.Children {
+		c.Walk(fn)
+	}
+}
+
+// VersionLister lists the versions of an artifact, to select one from a
+// version range. LocalRepository implements it.
+type VersionLister interface {
+	Versions(groupId, artifactId string) ([]string, error)
+}
+
+// Options configures Resolve.
+type Options struct {
+	// Resolver reads the POMs of the dependencies and their parents. When it
+	// is a VersionLister too, version ranges are resolved to the highest
+	// version within the range.
+	Resolver pom.ModelResolver
+	// Properties are passed to the effective models of the dependencies, for
+	// the activation of their profiles.
+	Properties map[string]string
+	// Verbose keeps the dependencies omitted in favour of nearer ones in the
+	// graph, like the verbose tree of the Maven dependency plugin.
+	Verbose bool
+	// Full implies Verbose and expands the dependencies omitted for a
+	// conflict too, like the graph Maven collects before resolving the
+	// conflicts, so that every version requested by a path of the graph is
+	// in it. The nodes below an omitted node are omitted, and the omitted
+	// nodes of a version and scope are expanded once.
+	Full bool
+}
+
+// Resolve returns the dependency graph of project, an effective model like
+// the one returned by Model.Effective. Each dependency appears once in the
+// graph, where it is nearest to the root. The error is an
+// *pom.UnresolvableModelError when the POM of a dependency cannot be found.
+func Resolve(project *pom.Model, opts *Options) (*Node, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	r := &resolver{
+		opts:     opts,
+		managed:  make(map[string]pom.Dependency),
+		models:   make(map[string]*pom.Model),
+		resolved: make(map[string]*Node),
+		expanded: make(map[string]bool),
+	}
+	if project.DependencyManagement != nil && project.DependencyManagement.Dependencies != nil {
+		for _, d := range project.DependencyManagement.Dependencies.Dependency {
+			r.managed[d.ManagementKey()] = d
+		}
+	}
+
+	root := &Node{
+		Dependency: pom.Dependency{
+			GroupId:    project.GroupId,
+			ArtifactId: project.ArtifactId,
+			Version:    project.Version,
+			Type:       orDefault(project.Packaging, "jar"),
+		},
+		Model: project,
+	}
+	if root.Dependency.GroupId == "" && project.Parent != nil {
+		root.Dependency.GroupId = project.Parent.GroupId
+	}
+	if root.Dependency.Version == "" && project.Parent != nil {
+		root.Dependency.Version = project.Parent.Version
+	}
+
+	if err := r.resolve(root); err != nil {
+		return nil, err
+	}
+	r.widenScopes(root)
+	return root, nil
+}
+
+type resolver struct {
+	opts *Options
+	// managed is the dependency management of the project by management key.
+	managed map[string]pom.Dependency
+	// models caches the effective POMs by groupId:artifactId:version.
+	models map[string]*pom.Model
+	// resolved holds the nodes of the graph by management key.
+	resolved map[string]*Node
+	// expanded holds the omitted nodes expanded in full graphs by management
+	// key, version and scope.
+	expanded map[string]bool
+}
+
+// pending is a node whose dependencies are yet to be resolved.
+type pending struct {
+	node       *Node
+	exclusions []pom.Exclusion
+}
+
+// resolve builds the graph below root breadth first, so that the nearest
+// declaration of a dependency is met first.
+func (r *resolver) resolve(root *Node) error {
+	queue := []pending{{node: root}}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+
+		deps, err := r.dependencies(p.node)
+		if err != nil {
+			return err
+		}
+		for _, d := range deps {
+			direct := p.node == root
+			if !direct && !isTransitive(d) {
+				continue
+			}
+			if d.Scope == pom.ScopeImport || isExcluded(p.exclusions, d) {
+				continue
+			}
+
+			declared := d
+			var exclusions []pom.Exclusion
+			if !direct {
+				exclusions = r.manage(&d)
+			}
+			scope := orDefault(d.Scope, pom.ScopeCompile)
+			if !direct {
+				if scope = deriveScope(p.node.Dependency.Scope, scope); scope == "" {
+					continue
+				}
+			}
+
+			key := d.ManagementKey()
+			winner, conflict := r.resolved[key]
+			omitted := p.node.Omitted != ""
+			version, err := r.selectVersion(&d)
+			if err != nil && !conflict && !omitted {
+				return err
+			} else if err != nil {
+				version = d.Version
+			}
+
+			child := &Node{parent: p.node, declared: orDefault(d.Scope, pom.ScopeCompile)}
+			child.Dependency = pom.Dependency{
+				GroupId:    d.GroupId,
+				ArtifactId: d.ArtifactId,
+				Version:    version,
+				Type:       orDefault(d.Type, "jar"),
+				Classifier: d.Classifier,
+				Scope:      scope,
+				SystemPath: d.SystemPath,
+				Exclusions: d.Exclusions,
+				Optional:   d.Optional,
+			}
+			if d.Version != declared.Version {
+				child.PremanagedVersion = declared.Version
+			}
+			if d.Scope != declared.Scope {
+				child.PremanagedScope = orDefault(declared.Scope, pom.ScopeCompile)
+			}
+
+			if omitted {
+				// Below an omitted node, in a full graph.
+				switch {
+				case isAncestor(p.node, key):
+					child.Omitted = OmittedForCycle
+				case conflict:
+					child.Winner = winner
+					child.Omitted = omission(p.node, winner, version)
+				default:
+					child.Omitted = OmittedWithParent
+				}
+				p.node.Children = append(p.node.Children, child)
+				if (child.Omitted == OmittedForConflict || child.Omitted == OmittedWithParent) && r.expandOnce(child) {
+					queue = append(queue, expand(child, d, exclusions, p.exclusions))
+				}
+				continue
+			}
+
+			if conflict {
+				if !direct {
+					winner.losers = append(winner.losers, loser{parent: p.node, declared: child.declared})
+				}
+				if r.opts.Verbose || r.opts.Full {
+					child.Winner = winner
+					child.Omitted = omission(p.node, winner, version)
+					p.node.Children = append(p.node.Children, child)
+					if r.opts.Full && child.Omitted == OmittedForConflict && r.expandOnce(child) {
+						queue = append(queue, expand(child, d, exclusions, p.exclusions))
+					}
+				}
+				continue
+			}
+
+			r.resolved[key] = child
+			p.node.Children = append(p.node.Children, child)
+			queue = append(queue, expand(child, d, exclusions, p.exclusions))
+		}
+	}
+	return nil
+}
+
+// expand returns child, the node of dependency d, to be expanded with the
+// exclusions of its management, its declaration and its parent.
+func expand(child *Node, d pom.Dependency, managed, inherited []pom.Exclusion) pending {
+	exclusions := managed
+	if d.Exclusions != nil {
+		exclusions = append(exclusions, d.Exclusions.Exclusion...)
+	}
+	return pending{node: child, exclusions: append(exclusions, inherited...)}
+}
+
+// expandOnce reports whether the omitted node n is the first of its version
+// and scope to be expanded. The others would have the same dependencies, and
+// expanding each of them could grow the graph exponentially.
+func (r *resolver) expandOnce(n *Node) bool {
+	key := n.Dependency.ManagementKey() + ":" + n.Dependency.Version + ":" + n.Dependency.Scope
+	if r.expanded[key] {
+		return false
+	}
+	r.expanded[key] = true
+	return true
+}
+
+// isAncestor reports whether n or one of its ancestors has the management
+// key key.
+func isAncestor(n *Node, key string) bool {
+	for ; n != nil; n = n.parent {
+		if n.Dependency.ManagementKey() == key {
+			return true
+		}
+	}
+	return false
+}
+
+// dependencies returns the dependencies declared by the POM of n, reading it
+// first unless n is the root.
+func (r *resolver) dependencies(n *Node) ([]pom.Dependency, error) {
+	if n.Model == nil {
+		if n.declared == pom.ScopeSystem {
+			return nil, nil
+		}
+		m, err := r.model(n.Dependency.GroupId, n.Dependency.ArtifactId, n.Dependency.Version)
+		if err != nil {
+			return nil, err
+		}
+		n.Model = m
+	}
+	if n.Model.Dependencies == nil {
+		return nil, nil
+	}
+	return n.Model.Dependencies.Dependency, nil
+}
+
+// model returns the effective POM of groupId:artifactId:version.
+func (r *resolver) model(groupId, artifactId, version string) (*pom.Model, error) {
+	key := groupId + ":" + artifactId + ":" + version
+	if m, ok := r.models[key]; ok {
+		return m, nil
+	}
+	if r.opts.Resolver == nil {
+		return nil, &pom.UnresolvableModelError{GroupId: groupId, ArtifactId: artifactId, Version: version, Err: fmt.Errorf("no resolver")}
+	}
+
+	m, err := r.opts.Resolver.ResolveModel(groupId, artifactId, version)
+	if err != nil {
+		return nil, &pom.UnresolvableModelError{GroupId: groupId, ArtifactId: artifactId, Version: version, Err: err}
+	}
+	m, err = m.Effective(&pom.EffectiveOptions{Resolver: r.opts.Resolver, Properties: r.opts.Properties})
+	if err != nil {
+		return nil, err
+	}
+	r.models[key] = m
+	return m, nil
+}
+
+// manage applies the dependency management of the project to the transitive
+// dependency d, and returns the exclusions it adds.
+func (r *resolver) manage(d *pom.Dependency) []pom.Exclusion {
+	m, ok := r.managed[d.ManagementKey()]
+	if !ok {
+		return nil
+	}
+	if m.Version != "" {
+		d.Version = m.Version
+	}
+	if m.Scope != "" {
+		d.Scope = m.Scope
+	}
+	if m.Exclusions != nil {
+		return m.Exclusions.Exclusion
+	}
+	return nil
+}
+
+// selectVersion returns the version of d, the highest one listed by the
+// resolver within its range if it has one.
+func (r *resolver) selectVersion(d *pom.Dependency) (string, error) {
+	vr, err := pom.ParseVersionRange(d.Version)
+	if err != nil || vr.Recommended != "" {
+		return d.Version, nil
+	}
+
+	lister, ok := r.opts.Resolver.(VersionLister)
+	if !ok {
+		return "", fmt.Errorf("resolve: cannot resolve version range %s of %s:%s without a version lister", d.Version, d.GroupId, d.ArtifactId)
+	}
+	versions, err := lister.Versions(d.GroupId, d.ArtifactId)
+	if err != nil {
+		return "", fmt.Errorf("resolve: cannot list versions of %s:%s: %w", d.GroupId, d.ArtifactId, err)
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return pom.CompareVersions(versions[i], versions[j]) > 0
+	})
+	for _, v := range versions {
+		if vr.Contains(v) {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("resolve: no version of %s:%s within %s", d.GroupId, d.ArtifactId, d.Version)
+}
+
+// widenScopes gives the nodes that won against dependencies of a wider scope
+// that scope, as Maven does, then propagates the scopes to their
+// descendants until nothing changes.
+func (r *resolver) widenScopes(root *Node) {
+	for changed := true; changed; {
+		changed = false
+		root.Walk(func(n *Node) {
+			if n.parent == nil || n.parent == root {
+				return
+			}
+			derived := deriveScope(n.parent.Dependency.Scope, n.declared)
+			scope := derived
+			for _, l := range n.losers {
+				if s := deriveScope(l.parent.Dependency.Scope, l.declared); scopeRank[s] > scopeRank[scope] {
+					scope = s
+				}
+			}
+			if scope != "" && scopeRank[scope] > scopeRank[n.Dependency.Scope] {
+				n.Dependency.Scope = scope
+				changed = true
+			}
+			if n.Dependency.Scope != derived {
+				n.ScopeUpdatedFrom = derived
+			} else {
+				n.ScopeUpdatedFrom = ""
+			}
+		})
+	}
+}
+
+// scopeRank orders scopes from the narrowest to the widest.
+var scopeRank = map[string]int{
+	pom.ScopeTest:     1,
+	pom.ScopeProvided: 2,
+	pom.ScopeSystem:   2,
+	pom.ScopeRuntime:  3,
+	pom.ScopeCompile:  4,
+}
+
+// isTransitive reports whether a dependency of a dependency belongs to the
+// graph of the project.
+func isTransitive(d pom.Dependency) bool {
+	if d.Optional == "true" {
+		return false
+	}
+	switch d.Scope {
+	case pom.ScopeTest, pom.ScopeProvided, pom.ScopeCompileOnly, pom.ScopeTestOnly, pom.ScopeTestRuntime:
+		return false
+	}
+	return true
+}
+
+// deriveScope returns the scope of a dependency declared with scope by a
+// dependency of parent scope, or "" if it is not part of the graph.
+func deriveScope(parent, scope string) string {
+	switch scope {
+	case pom.ScopeCompile:
+		return parent
+	case pom.ScopeRuntime:
+		if parent == pom.ScopeCompile {
+			return pom.ScopeRuntime
+		}
+		return parent
+	case pom.ScopeSystem:
+		if parent == pom.ScopeTest {
+			return parent
+		}
+		return pom.ScopeSystem
+	case pom.ScopeProvided, pom.ScopeTest:
+		return ""
+	}
+	return ""
+}
+
+func isExcluded(exclusions []pom.Exclusion, d pom.Dependency) bool {
+	for _, e := range exclusions {
+		if (e.GroupId == "*" || e.GroupId == d.GroupId) && (e.ArtifactId == "*" || e.ArtifactId == d.ArtifactId) {
+			return true
+		}
+	}
+	return false
+}
+
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
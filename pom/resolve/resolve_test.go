@@ -0,0 +1,269 @@
+package resolve_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+	"github.com/obscurelyme/encoding/pom/resolve"
+)
+
+var repository = pom.LocalRepository{Dir: filepath.Join("testdata", "repository")}
+
+func resolveProject(t *testing.T, verbose bool) *resolve.Node {
+	t.Helper()
+
+	m, err := pom.ReadFile(filepath.Join("testdata", "pom.xml"))
+	if err != nil {
+		t.Fatalf("Expected no errors reading pom, but found: %s", err.Error())
+	}
+	eff, err := m.Effective(&pom.EffectiveOptions{Resolver: repository})
+	if err != nil {
+		t.Fatalf("Expected no errors building the effective model, but found: %s", err.Error())
+	}
+	root, err := resolve.Resolve(eff, &resolve.Options{Resolver: repository, Verbose: verbose})
+	if err != nil {
+		t.Fatalf("Expected no errors resolving dependencies, but found: %s", err.Error())
+	}
+	return root
+}
+
+func TestResolve(t *testing.T) {
+	root := resolveProject(t, false)
+
+	t.Run("Should print the graph like the dependency plugin", func(t *testing.T) {
+		var b strings.Builder
+		if err := root.WriteText(&b); err != nil {
+			t.Fatalf("Expected no errors writing the tree, but found: %s", err.Error())
+		}
+
+		expected := `org.example:app:jar:1.0.0
++- org.example:web:jar:1.0:compile
+|  +- org.example:core:jar:1.0:compile
+|  +- org.example:logging:jar:1.1:runtime (version managed from 1.0)
+|  \- org.example:ranged:jar:1.2:compile
+\- org.example:util:jar:1.0:test
+   \- org.example:lib:jar:1.0:compile (scope updated from test)
+      \- org.example:common:jar:1.0:compile
+`
+		if b.String() != expected {
+			t.Errorf("Expected tree:\n%s\nbut found:\n%s", expected, b.String())
+		}
+	})
+
+	t.Run("Should keep track of parents and models", func(t *testing.T) {
+		var lib *resolve.Node
+		root.Walk(func(n *resolve.Node) {
+			if n.Dependency.ArtifactId == "lib" {
+				lib = n
+			}
+		})
+		if lib == nil {
+			t.Fatalf("Expected lib in the graph")
+		}
+		if lib.Parent().Dependency.ArtifactId != "util" {
+			t.Errorf("Expected lib under util, but found: %s", lib.Parent())
+		}
+		if lib.Model == nil || lib.Model.Parent == nil || lib.Model.Parent.ArtifactId != "base" {
+			t.Errorf("Expected the effective model of lib")
+		}
+	})
+
+	t.Run("Should report missing POMs", func(t *testing.T) {
+		m := &pom.Model{GroupId: "org.example", ArtifactId: "app", Version: "1", Dependencies: &pom.Dependencies{
+			Dependency: []pom.Dependency{{GroupId: "org.example", ArtifactId: "missing", Version: "1.0"}},
+		}}
+		_, err := resolve.Resolve(m, &resolve.Options{Resolver: repository})
+		var unresolvable *pom.UnresolvableModelError
+		if !errors.As(err, &unresolvable) || unresolvable.ArtifactId != "missing" {
+			t.Errorf("Expected an UnresolvableModelError for missing, but found: %v", err)
+		}
+	})
+}
+
+func TestVerbose(t *testing.T) {
+	root := resolveProject(t, true)
+
+	t.Run("Should keep omitted dependencies", func(t *testing.T) {
+		var b strings.Builder
+		if err := root.WriteText(&b); err != nil {
+			t.Fatalf("Expected no errors writing the tree, but found: %s", err.Error())
+		}
+
+		expected := `org.example:app:jar:1.0.0
++- org.example:web:jar:1.0:compile
+|  +- org.example:core:jar:1.0:compile
+|  |  \- (org.example:lib:jar:1.0:compile - omitted for duplicate)
+|  +- org.example:logging:jar:1.1:runtime (version managed from 1.0)
+|  \- org.example:ranged:jar:1.2:compile
+\- org.example:util:jar:1.0:test
+   +- org.example:lib:jar:1.0:compile (scope updated from test)
+   |  \- org.example:common:jar:1.0:compile
+   \- (org.example:core:jar:1.5:test - omitted for conflict with 1.0)
+`
+		if b.String() != expected {
+			t.Errorf("Expected tree:\n%s\nbut found:\n%s", expected, b.String())
+		}
+	})
+
+	t.Run("Should write JSON", func(t *testing.T) {
+		var b strings.Builder
+		if err := root.WriteJSON(&b); err != nil {
+			t.Fatalf("Expected no errors writing JSON, but found: %s", err.Error())
+		}
+
+		var tree struct {
+			Children []struct {
+				ArtifactId string
+				Children   []struct {
+					ArtifactId, Version, Omitted, WinnerVersion string
+				}
+			}
+		}
+		if err := json.Unmarshal([]byte(b.String()), &tree); err != nil {
+			t.Fatalf("Expected valid JSON, but found: %s", err.Error())
+		}
+		core := tree.Children[1].Children[1]
+		if core.ArtifactId != "core" || core.Version != "1.5" || core.Omitted != "conflict" || core.WinnerVersion != "1.0" {
+			t.Errorf("Expected the omitted core, but found: %+v", core)
+		}
+	})
+
+	t.Run("Should write graphs", func(t *testing.T) {
+		for name, c := range map[string]struct {
+			write    func(io.Writer) error
+			expected []string
+		}{
+			"DOT": {root.WriteDOT, []string{
+				"digraph \"org.example:app:jar:1.0.0\" {\n",
+				"  n0 [label=\"org.example:app:jar:1.0.0\"];\n",
+				"  n3 [label=\"org.example:lib:jar:1.0:compile\", style=dashed];\n",
+				"  n2 -> n3 [label=\"omitted for duplicate\", style=dashed];\n",
+				"  n0 -> n1;\n",
+			}},
+			"Mermaid": {root.WriteMermaid, []string{
+				"graph TD\n",
+				"  n4[\"org.example:logging:jar:1.1:runtime\"]\n",
+				"  n1 -->|\"version managed from 1.0\"| n4\n",
+				"  n6 -.->|\"omitted for conflict with 1.0\"| n9\n",
+			}},
+			"GraphML": {root.WriteGraphML, []string{
+				`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">`,
+				`<node id="n9">`,
+				`<data key="omitted">conflict</data>`,
+				`<edge source="n6" target="n9">`,
+			}},
+		} {
+			var b strings.Builder
+			if err := c.write(&b); err != nil {
+				t.Fatalf("Expected no errors writing %s, but found: %s", name, err.Error())
+			}
+			for _, e := range c.expected {
+				if !strings.Contains(b.String(), e) {
+					t.Errorf("Expected %s to contain %q, but found:\n%s", name, e, b.String())
+				}
+			}
+		}
+	})
+}
+
+func TestFull(t *testing.T) {
+	m, err := pom.ReadFile(filepath.Join("testdata", "pom.xml"))
+	if err != nil {
+		t.Fatalf("Expected no errors reading pom, but found: %s", err.Error())
+	}
+	eff, err := m.Effective(&pom.EffectiveOptions{Resolver: repository})
+	if err != nil {
+		t.Fatalf("Expected no errors building the effective model, but found: %s", err.Error())
+	}
+	root, err := resolve.Resolve(eff, &resolve.Options{Resolver: repository, Full: true})
+	if err != nil {
+		t.Fatalf("Expected no errors resolving dependencies, but found: %s", err.Error())
+	}
+
+	t.Run("Should expand the dependencies omitted for a conflict", func(t *testing.T) {
+		var b strings.Builder
+		if err := root.WriteText(&b); err != nil {
+			t.Fatalf("Expected no errors writing the tree, but found: %s", err.Error())
+		}
+
+		expected := `org.example:app:jar:1.0.0
++- org.example:web:jar:1.0:compile
+|  +- org.example:core:jar:1.0:compile
+|  |  \- (org.example:lib:jar:1.0:compile - omitted for duplicate)
+|  +- org.example:logging:jar:1.1:runtime (version managed from 1.0)
+|  \- org.example:ranged:jar:1.2:compile
+\- org.example:util:jar:1.0:test
+   +- org.example:lib:jar:1.0:compile (scope updated from test)
+   |  \- org.example:common:jar:1.0:compile
+   \- (org.example:core:jar:1.5:test - omitted for conflict with 1.0)
+      +- (org.example:lib:jar:1.0:test - omitted for duplicate)
+      \- (org.example:tools:jar:1.0:test - omitted with its parent)
+`
+		if b.String() != expected {
+			t.Errorf("Expected tree:\n%s\nbut found:\n%s", expected, b.String())
+		}
+	})
+
+	t.Run("Should expand the omitted dependencies of a version once", func(t *testing.T) {
+		// a<i> and b<i> depend on a<i+1> and b<i+1> of the same version, and
+		// the project on a0 1 and b0 2: every version 2 below b0 is omitted,
+		// at 2^i places of level i.
+		dir := t.TempDir()
+		const levels = 12
+		for i := 0; i < levels; i++ {
+			for _, a := range []string{"a", "b"} {
+				for _, v := range []string{"1", "2"} {
+					var deps string
+					if i+1 < levels {
+						deps = fmt.Sprintf(`<dependencies>
+    <dependency><groupId>org.example</groupId><artifactId>a%d</artifactId><version>%s</version></dependency>
+    <dependency><groupId>org.example</groupId><artifactId>b%d</artifactId><version>%s</version></dependency>
+  </dependencies>`, i+1, v, i+1, v)
+					}
+					artifactId := fmt.Sprintf("%s%d", a, i)
+					p := filepath.Join(dir, "org", "example", artifactId, v, artifactId+"-"+v+".pom")
+					os.MkdirAll(filepath.Dir(p), 0o755)
+					content := fmt.Sprintf(`<project>
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>org.example</groupId>
+  <artifactId>%s</artifactId>
+  <version>%s</version>
+  %s
+</project>`, artifactId, v, deps)
+					if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+		}
+
+		project := &pom.Model{GroupId: "org.example", ArtifactId: "app", Version: "1.0"}
+		project.Dependencies = &pom.Dependencies{Dependency: []pom.Dependency{
+			{GroupId: "org.example", ArtifactId: "a0", Version: "1"},
+			{GroupId: "org.example", ArtifactId: "b0", Version: "2"},
+		}}
+		root, err := resolve.Resolve(project, &resolve.Options{Resolver: pom.LocalRepository{Dir: dir}, Full: true})
+		if err != nil {
+			t.Fatalf("Expected no errors resolving dependencies, but found: %s", err.Error())
+		}
+
+		var count func(n *resolve.Node) int
+		count = func(n *resolve.Node) int {
+			c := 1
+			for _, child := range n.Children {
+				c += count(child)
+			}
+			return c
+		}
+		if found := count(root); found > 8*levels {
+			t.Errorf("Expected at most %d nodes, but found: %d", 8*levels, found)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package resolve
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// String returns the coordinates of the dependency of n like Maven prints
+// them, groupId:artifactId:type[:classifier]:version[:scope].
+func (n *Node) String() string {
+	d := &n.Dependency
+	s := d.GroupId + ":" + d.ArtifactId + ":" + d.Type
+	if d.Classifier != "" {
+		s += ":" + d.Classifier
+	}
+	s += ":" + d.Version
+	if d.Scope != "" {
+		s += ":" + d.Scope
+	}
+	if d.Optional == "true" {
+		s += " (optional)"
+	}
+	return s
+}
+
+// Notes returns the annotations of n in the words of the Maven dependency
+// plugin, like "version managed from 1.0" or "omitted for duplicate".
+func (n *Node) Notes() []string {
+	var notes []string
+	if n.PremanagedVersion != "" {
+		notes = append(notes, "version managed from "+n.PremanagedVersion)
+	}
+	if n.PremanagedScope != "" {
+		notes = append(notes, "scope managed from "+n.PremanagedScope)
+	}
+	if n.ScopeUpdatedFrom != "" {
+		notes = append(notes, "scope updated from "+n.ScopeUpdatedFrom)
+	}
+	switch n.Omitted {
+	case OmittedForDuplicate:
+		notes = append(notes, "omitted for duplicate")
+	case OmittedForConflict:
+		notes = append(notes, "omitted for conflict with "+n.Winner.Dependency.Version)
+	case OmittedForCycle:
+		notes = append(notes, "omitted for cycle")
+	case OmittedWithParent:
+		notes = append(notes, "omitted with its parent")
+	}
+	return notes
+}
+
+// label returns the line of n in a text tree.
+func (n *Node) label() string {
+	notes := strings.Join(n.Notes(), "; ")
+	switch {
+	case n.Omitted != "":
+		return "(" + n.String() + " - " + notes + ")"
+	case notes != "":
+		return n.String() + " (" + notes + ")"
+	}
+	return n.String()
+}
+
+// WriteText writes the graph below n to w as a tree, like the tree goal of
+// the Maven dependency plugin, with its annotations.
+func (n *Node) WriteText(w io.Writer) error {
+	b := bufio.NewWriter(w)
+	b.WriteString(n.label() + "\n")
+	writeChildren(b, n, "")
+	return b.Flush()
+}
+
+func writeChildren(b *bufio.Writer, n *Node, prefix string) {
+	for i, c := range n.Children {
+		branch, indent := "+- ", "|  "
+		if i == len(n.Children)-1 {
+			branch, indent = "\\- ", "   "
+		}
+		b.WriteString(prefix + branch + c.label() + "\n")
+		writeChildren(b, c, prefix+indent)
+	}
+}
@@ -0,0 +1,68 @@
+package pom
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// A ModelResolver finds the POM of a project from its coordinates. It is used
+// for parents that are not part of the source tree and for imported boms.
+type ModelResolver interface {
+	ResolveModel(groupId, artifactId, version string) (*Model, error)
+}
+
+// LocalRepository is a local Maven repository, such as ~/.m2/repository. It
+// resolves models from the POMs stored in it.
+type LocalRepository struct {
+	Dir string
+}
+
+// DefaultLocalRepository returns the repository in the .m2 directory of the
+// user's home directory.
+func DefaultLocalRepository() (LocalRepository, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return LocalRepository{}, err
+	}
+
+	return LocalRepository{Dir: filepath.Join(home, ".m2", "repository")}, nil
+}
+
+// Path returns the location of an artifact file within r, such as
+// org/slf4j/slf4j-api/2.0.9/slf4j-api-2.0.9.jar for the jar extension of
+// org.slf4j:slf4j-api:2.0.9.
+func (r LocalRepository) Path(groupId, artifactId, version, classifier, extension string) string {
+	name := artifactId + "-" + version
+	if classifier != "" {
+		name += "-" + classifier
+	}
+
+	return filepath.Join(r.Dir, filepath.FromSlash(strings.ReplaceAll(groupId, ".", "/")), artifactId, version, name+"."+extension)
+}
+
+// ResolveModel reads the POM of groupId:artifactId:version from r.
+func (r LocalRepository) ResolveModel(groupId, artifactId, version string) (*Model, error) {
+	return ReadFile(r.Path(groupId, artifactId, version, "", "pom"))
+}
+
+// Versions returns the versions of groupId:artifactId that have a POM in r,
+// in no particular order.
+func (r LocalRepository) Versions(groupId, artifactId string) ([]string, error) {
+	dir := filepath.Join(r.Dir, filepath.FromSlash(strings.ReplaceAll(groupId, ".", "/")), artifactId)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(r.Path(groupId, artifactId, e.Name(), "", "pom")); err == nil {
+			versions = append(versions, e.Name())
+		}
+	}
+	return versions, nil
+}
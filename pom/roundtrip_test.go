@@ -0,0 +1,61 @@
+package pom_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+func decodeStrict(t *testing.T, data []byte) *pom.Model {
+	t.Helper()
+
+	d := pom.NewDecoder(bytes.NewReader(data))
+	d.Mode = pom.Strict
+
+	m := pom.New()
+	if err := d.Decode(m); err != nil {
+		t.Fatalf("Expected no errors decoding pom, but found: %s", err.Error())
+	}
+	return m
+}
+
+func TestRoundTrip(t *testing.T) {
+	files, err := filepath.Glob("testdata/roundtrip/*.xml")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("Expected a round trip corpus, but found: %v %v", files, err)
+	}
+
+	for _, file := range files {
+		t.Run("Should round trip "+filepath.Base(file), func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatalf("Expected no errors reading %s, but found: %s", file, err.Error())
+			}
+
+			first := decodeStrict(t, data)
+
+			var buf bytes.Buffer
+			if err := first.Write(&buf, nil); err != nil {
+				t.Fatalf("Expected no errors writing pom, but found: %s", err.Error())
+			}
+			written := buf.String()
+
+			second := decodeStrict(t, buf.Bytes())
+			if !reflect.DeepEqual(first, second) {
+				t.Errorf("Expected the written pom to decode to the same model, but found:\n%s", written)
+			}
+
+			buf.Reset()
+			if err := second.Write(&buf, nil); err != nil {
+				t.Fatalf("Expected no errors writing pom, but found: %s", err.Error())
+			}
+			if buf.String() != written {
+				t.Errorf("Expected writing to be stable, but found:\n%s\nthen:\n%s", written, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package pom
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// elementType lists the child elements and attributes allowed within an
+// element of the Model, derived from the xml tags of the struct decoding it.
+type elementType struct {
+	elements map[string]*elementType
+	attrs    map[string]bool
+	// opaque elements decode arbitrary content, like configuration blocks and
+	// properties, so nothing inside them is unrecognized.
+	opaque bool
+}
+
+var modelElementType = sync.OnceValue(func() *elementType {
+	return newElementType(reflect.TypeOf(Model{}), make(map[reflect.Type]*elementType))
+})
+
+var unmarshalerType = reflect.TypeOf((*xml.Unmarshaler)(nil)).Elem()
+
+func newElementType(t reflect.Type, seen map[reflect.Type]*elementType) *elementType {
+	if et, ok := seen[t]; ok {
+		return et
+	}
+
+	et := &elementType{
+		elements: make(map[string]*elementType),
+		attrs:    make(map[string]bool),
+		opaque:   reflect.PointerTo(t).Implements(unmarshalerType),
+	}
+	seen[t] = et
+
+	if !et.opaque && t.Kind() == reflect.Struct {
+		et.addFields(t, seen)
+	}
+
+	return et
+}
+
+func (et *elementType) addFields(t reflect.Type, seen map[reflect.Type]*elementType) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, hasTag := f.Tag.Lookup("xml")
+
+		if f.Anonymous && !hasTag {
+			et.addFields(indirectType(f.Type), seen)
+			continue
+		}
+		if !f.IsExported() || f.Name == "XMLName" || tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if !hasTag {
+			name = f.Name
+		}
+		if name == "" {
+			// comment, chardata, innerxml and any fields
+			continue
+		}
+
+		if hasOption(opts, "attr") {
+			et.attrs[name] = true
+			continue
+		}
+		et.elements[name] = newElementType(indirectType(f.Type), seen)
+	}
+}
+
+// indirectType returns the type of a single element decoded into a field of
+// type t, looking through pointers and slices.
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+func hasOption(opts string, option string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// isNamespaceAttr reports whether attr declares a namespace or belongs to the
+// XML Schema instance namespace, which every element may carry.
+func isNamespaceAttr(attr xml.Attr) bool {
+	return attr.Name.Space == "xmlns" ||
+		attr.Name.Space == "" && attr.Name.Local == "xmlns" ||
+		attr.Name.Space == xsiNamespace
+}
@@ -0,0 +1,44 @@
+package pom
+
+import (
+	"bytes"
+	"embed"
+	"strings"
+)
+
+//go:embed superpom/*.xml
+var superPOMs embed.FS
+
+// SuperPOM returns the Super POM of the Maven version mavenVersion, like 3.9.6
+// or 4.0.0, that all POMs inherit from: it sets the default directories of the
+// build, the final name, the central repository and the versions of some
+// plugins. Versions before 4 get the Super POM of Maven 3.9, later ones that
+// of Maven 4.0. Each call returns a new model.
+//
+// The Super POM is inherited by Effective when given as the SuperPOM of its
+// options.
+func SuperPOM(mavenVersion string) (*Model, error) {
+	name := "superpom/maven-3.xml"
+	if isMaven4(mavenVersion) {
+		name = "superpom/maven-4.xml"
+	}
+	data, err := superPOMs.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return Read(bytes.NewReader(data))
+}
+
+// isMaven4 reports whether mavenVersion is Maven 4 or later.
+func isMaven4(mavenVersion string) bool {
+	major, _, _ := strings.Cut(mavenVersion, ".")
+	return CompareVersions(major, "4") >= 0
+}
+
+// InjectSuperPOM makes m inherit from super, a Super POM, like Maven does
+// for the last of the parents of a POM. The profiles of super are not
+// injected: Effective activates them like those of the POM when super is
+// the SuperPOM of its options.
+func (m *Model) InjectSuperPOM(super *Model) {
+	inherit(m, super)
+}
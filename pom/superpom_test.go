@@ -0,0 +1,57 @@
+package pom_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+func TestSuperPOM(t *testing.T) {
+	t.Run("Should apply the defaults of Maven", func(t *testing.T) {
+		super, err := pom.SuperPOM("3.9.6")
+		if err != nil {
+			t.Fatalf("Expected no errors reading the Super POM, but found: %s", err.Error())
+		}
+		eff := effectiveCore(t, pom.EffectiveOptions{SuperPOM: super})
+
+		dir, _ := filepath.Abs(filepath.Join("testdata", "effective", "core"))
+		if found := eff.Build.SourceDirectory; found != filepath.Join(dir, "src", "main", "java") {
+			t.Errorf("Expected the default source directory, but found: %s", found)
+		}
+		if found := eff.Build.OutputDirectory; found != filepath.Join(dir, "target", "classes") {
+			t.Errorf("Expected the default output directory, but found: %s", found)
+		}
+		if found := eff.Build.FinalName; found != "core-1.2.0-SNAPSHOT" {
+			t.Errorf("Expected the default final name, but found: %s", found)
+		}
+		if eff.Repositories == nil || len(eff.Repositories.Repository) != 1 || eff.Repositories.Repository[0].Id != "central" {
+			t.Errorf("Expected the central repository, but found: %v", eff.Repositories)
+		}
+		if p := findPlugin(eff, "maven-compiler-plugin"); p == nil || p.Version != "3.11.0" {
+			t.Errorf("Expected the compiler plugin version of the parent, but found: %v", p)
+		}
+	})
+
+	t.Run("Should activate the release profile of Maven 3 only", func(t *testing.T) {
+		for version, expected := range map[string]bool{"3.9.6": true, "4.0.0": false} {
+			super, err := pom.SuperPOM(version)
+			if err != nil {
+				t.Fatalf("Expected no errors reading the Super POM, but found: %s", err.Error())
+			}
+			eff := effectiveCore(t, pom.EffectiveOptions{SuperPOM: super, Properties: map[string]string{"performRelease": "true"}})
+			if found := findPlugin(eff, "maven-source-plugin") != nil; found != expected {
+				t.Errorf("Expected the source plugin %v with Maven %s, but found: %v", expected, version, found)
+			}
+		}
+	})
+
+	t.Run("Should not override the model", func(t *testing.T) {
+		super, _ := pom.SuperPOM("4.0.0")
+		m := &pom.Model{ArtifactId: "example", Build: &pom.Build{SourceDirectory: "src"}}
+		m.InjectSuperPOM(super)
+		if m.Build.SourceDirectory != "src" || m.Build.TestSourceDirectory != "${project.basedir}/src/test/java" {
+			t.Errorf("Expected the source directories of the model and the Super POM, but found: %s %s", m.Build.SourceDirectory, m.Build.TestSourceDirectory)
+		}
+	})
+}
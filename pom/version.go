@@ -0,0 +1,296 @@
+package pom
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Model versions understood by this package.
+const (
+	// ModelVersion400 is the modelVersion of POMs conforming to maven-4.0.0.xsd.
+	ModelVersion400 = "4.0.0"
+	// ModelVersion410 is the modelVersion of POMs conforming to
+	// maven-4.1.0.xsd, introduced by Maven 4.
+	ModelVersion410 = "4.1.0"
+)
+
+var supportedModelVersions = map[string]bool{
+	ModelVersion400: true,
+	ModelVersion410: true,
+}
+
+// Dependency scopes. The last three were introduced by Maven 4 and are only
+// valid in modelVersion 4.1.0.
+const (
+	ScopeCompile     = "compile"
+	ScopeProvided    = "provided"
+	ScopeRuntime     = "runtime"
+	ScopeTest        = "test"
+	ScopeSystem      = "system"
+	ScopeImport      = "import"
+	ScopeCompileOnly = "compile-only"
+	ScopeTestOnly    = "test-only"
+	ScopeTestRuntime = "test-runtime"
+)
+
+// PackagingBOM is the Maven 4 packaging of bill of materials projects, only
+// valid in modelVersion 4.1.0. It is deployed as a pom packaged project.
+const PackagingBOM = "bom"
+
+const (
+	pomNamespace410 = "http://maven.apache.org/POM/4.1.0"
+	pomSchema410    = "https://maven.apache.org/xsd/maven-4.1.0.xsd"
+)
+
+// ConversionIssue describes part of a Model that could not be represented
+// exactly in the target model version.
+type ConversionIssue struct {
+	// Path is the slash separated path to the element, like in Unrecognized.
+	Path    string
+	Message string
+}
+
+func (i ConversionIssue) String() string {
+	return i.Path + ": " + i.Message
+}
+
+// Convert returns a copy of m converted to modelVersion version, either
+// ModelVersion400 or ModelVersion410, along with everything that could not be
+// carried over exactly. m itself is not modified.
+//
+// Upgrading turns modules into subprojects. Downgrading turns subprojects back
+// into modules and source directories into their 4.0.0 equivalents where
+// possible, replaces Maven 4 scopes and packaging with the closest 4.0.0 ones,
+// and drops the remaining 4.1.0 elements.
+func (m *Model) Convert(version string) (*Model, []ConversionIssue, error) {
+	c := m.Clone()
+
+	switch version {
+	case ModelVersion410:
+		c.ModelVersion = ModelVersion410
+		upgradeModules(&c.Modules, &c.Subprojects)
+		if c.Profiles != nil {
+			for i := range c.Profiles.Profile {
+				upgradeModules(&c.Profiles.Profile[i].Modules, &c.Profiles.Profile[i].Subprojects)
+			}
+		}
+		return c, nil, nil
+	case ModelVersion400:
+		c.ModelVersion = ModelVersion400
+		conv := &downgrade{}
+		conv.model(c)
+		return c, conv.issues, nil
+	default:
+		return nil, nil, fmt.Errorf("pom: cannot convert to unsupported modelVersion %q", version)
+	}
+}
+
+func upgradeModules(modules **Modules, subprojects **Subprojects) {
+	if *modules == nil {
+		return
+	}
+	if *subprojects == nil {
+		*subprojects = &Subprojects{}
+	}
+	(*subprojects).Subproject = append((*subprojects).Subproject, (*modules).Module...)
+	*modules = nil
+}
+
+type downgrade struct {
+	issues []ConversionIssue
+}
+
+func (d *downgrade) report(path string, format string, args ...any) {
+	d.issues = append(d.issues, ConversionIssue{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (d *downgrade) model(m *Model) {
+	if m.Root != "" {
+		d.report("/project/@root", "the root attribute is not supported, mark the root with a .mvn directory instead")
+		m.Root = ""
+	}
+
+	if m.Parent != nil && (m.Parent.GroupId == "" || m.Parent.ArtifactId == "" || m.Parent.Version == "") {
+		d.report("/project/parent", "groupId, artifactId and version of the parent cannot be inferred and must be given")
+	}
+
+	if m.Packaging == PackagingBOM {
+		d.report("/project/packaging", "packaging bom is replaced by pom")
+		m.Packaging = "pom"
+	}
+
+	m.Modules = d.subprojects(m.Modules, m.Subprojects)
+	m.Subprojects = nil
+
+	d.dependencies(m.Dependencies, "/project/dependencies")
+	if m.DependencyManagement != nil {
+		d.dependencies(m.DependencyManagement.Dependencies, "/project/dependencyManagement/dependencies")
+	}
+
+	if m.Build != nil {
+		d.sources(m.Build, "/project/build/sources")
+		d.buildBase(&m.Build.BuildBase, "/project/build")
+	}
+
+	if m.Profiles != nil {
+		for i := range m.Profiles.Profile {
+			d.profile(&m.Profiles.Profile[i], "/project/profiles/profile")
+		}
+	}
+}
+
+func (d *downgrade) profile(p *Profile, path string) {
+	p.Modules = d.subprojects(p.Modules, p.Subprojects)
+	p.Subprojects = nil
+
+	if p.Activation != nil && p.Activation.Condition != "" {
+		d.report(path+"/activation/condition", "profile %q: activation conditions are not supported and were removed", p.Id)
+		p.Activation.Condition = ""
+	}
+
+	d.dependencies(p.Dependencies, path+"/dependencies")
+	if p.DependencyManagement != nil {
+		d.dependencies(p.DependencyManagement.Dependencies, path+"/dependencyManagement/dependencies")
+	}
+	if p.Build != nil {
+		d.buildBase(p.Build, path+"/build")
+	}
+}
+
+// subprojects returns modules with subprojects appended, which loses nothing.
+func (d *downgrade) subprojects(modules *Modules, subprojects *Subprojects) *Modules {
+	if subprojects == nil || len(subprojects.Subproject) == 0 {
+		return modules
+	}
+	if modules == nil {
+		modules = &Modules{}
+	}
+	modules.Module = append(modules.Module, subprojects.Subproject...)
+	return modules
+}
+
+// downgradedScopes maps the Maven 4 scopes to the closest 4.0.0 scope.
+var downgradedScopes = map[string]string{
+	ScopeCompileOnly: ScopeProvided,
+	ScopeTestOnly:    ScopeTest,
+	ScopeTestRuntime: ScopeTest,
+}
+
+func (d *downgrade) dependencies(deps *Dependencies, path string) {
+	if deps == nil {
+		return
+	}
+	for i := range deps.Dependency {
+		dep := &deps.Dependency[i]
+		if scope, ok := downgradedScopes[dep.Scope]; ok {
+			d.report(path+"/dependency/scope", "%s:%s: scope %s is replaced by %s", dep.GroupId, dep.ArtifactId, dep.Scope, scope)
+			dep.Scope = scope
+		}
+	}
+}
+
+func (d *downgrade) buildBase(b *BuildBase, path string) {
+	for _, plugins := range []*Plugins{b.Plugins, pluginManagementPlugins(b.PluginManagement)} {
+		if plugins == nil {
+			continue
+		}
+		for i := range plugins.Plugin {
+			p := &plugins.Plugin[i]
+			if p.Executions == nil {
+				continue
+			}
+			for j := range p.Executions.Execution {
+				e := &p.Executions.Execution[j]
+				if e.Priority != "" {
+					d.report(path+"/plugins/plugin/executions/execution/priority", "%s execution %q: execution priority is not supported and was removed", p.ArtifactId, e.Id)
+					e.Priority = ""
+				}
+			}
+		}
+	}
+}
+
+func pluginManagementPlugins(pm *PluginManagement) *Plugins {
+	if pm == nil {
+		return nil
+	}
+	return pm.Plugins
+}
+
+// sources maps 4.1.0 source directories onto the 4.0.0 source directories and
+// resources. Only one main and one test directory of Java sources can be
+// represented, without filters, modules or target versions.
+func (d *downgrade) sources(b *Build, path string) {
+	if b.Sources == nil {
+		return
+	}
+
+	for _, src := range b.Sources.Source {
+		scope := src.Scope
+		if scope == "" {
+			scope = "main"
+		}
+		lang := src.Lang
+		if lang == "" {
+			lang = "java"
+		}
+		dir := src.Directory
+		if dir == "" {
+			dir = "src/" + scope + "/" + lang
+		}
+
+		if strings.EqualFold(src.Enabled, "false") {
+			d.report(path, "disabled %s %s source %s was removed", scope, lang, dir)
+			continue
+		}
+		if src.Module != "" || src.TargetVersion != "" {
+			d.report(path, "%s %s source %s: module and targetVersion are not supported", scope, lang, dir)
+			continue
+		}
+
+		switch lang {
+		case "java":
+			if src.Includes != nil || src.Excludes != nil {
+				d.report(path, "%s source %s: includes and excludes are not supported for Java sources", scope, dir)
+			}
+			target := &b.SourceDirectory
+			if scope == "test" {
+				target = &b.TestSourceDirectory
+			}
+			if *target != "" && *target != dir {
+				d.report(path, "%s source %s: only one %s source directory is supported, keeping %s", scope, dir, scope, *target)
+				continue
+			}
+			*target = dir
+		case "resources":
+			res := Resource{
+				Directory:  dir,
+				Includes:   src.Includes,
+				Excludes:   src.Excludes,
+				Filtering:  src.StringFiltering,
+				TargetPath: src.TargetPath,
+			}
+			if scope == "test" {
+				if b.TestResources == nil {
+					b.TestResources = &TestResources{}
+				}
+				b.TestResources.TestResource = append(b.TestResources.TestResource, res)
+			} else {
+				if b.Resources == nil {
+					b.Resources = &Resources{}
+				}
+				b.Resources.Resource = append(b.Resources.Resource, res)
+			}
+		case "script":
+			if scope == "main" && b.ScriptSourceDirectory == "" {
+				b.ScriptSourceDirectory = dir
+				continue
+			}
+			d.report(path, "%s script source %s is not supported", scope, dir)
+		default:
+			d.report(path, "%s %s source %s is not supported", scope, lang, dir)
+		}
+	}
+
+	b.Sources = nil
+}
@@ -0,0 +1,139 @@
+package pom_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+func readVersion410(t *testing.T) *pom.Model {
+	t.Helper()
+
+	data, err := os.ReadFile("testdata/roundtrip/maven-4.1.0.xml")
+	if err != nil {
+		t.Fatalf("Expected no errors reading the 4.1.0 pom, but found: %s", err.Error())
+	}
+	return decodeStrict(t, data)
+}
+
+func TestModelVersion410(t *testing.T) {
+	t.Run("Should read the Maven 4 elements", func(t *testing.T) {
+		m := readVersion410(t)
+
+		if m.ModelVersion != pom.ModelVersion410 || m.Root != "true" {
+			t.Errorf("Expected a 4.1.0 root project, but found: %s root=%q", m.ModelVersion, m.Root)
+		}
+		if m.Subprojects == nil || len(m.Subprojects.Subproject) != 2 {
+			t.Errorf("Expected 2 subprojects, but found: %+v", m.Subprojects)
+		}
+		if len(m.Build.Sources.Source) != 3 || m.Build.Sources.Source[1].TargetVersion != "21" {
+			t.Errorf("Expected 3 sources, but found: %+v", m.Build.Sources)
+		}
+		if m.Profiles.Profile[0].Activation.Condition != "${os.name} == 'linux'" {
+			t.Errorf("Expected an activation condition, but found: %q", m.Profiles.Profile[0].Activation.Condition)
+		}
+	})
+
+	t.Run("Should write the 4.1.0 namespace", func(t *testing.T) {
+		m := readVersion410(t)
+
+		var buf bytes.Buffer
+		if err := m.Write(&buf, nil); err != nil {
+			t.Fatalf("Expected no errors writing pom, but found: %s", err.Error())
+		}
+		if !strings.Contains(buf.String(), `xmlns="http://maven.apache.org/POM/4.1.0"`) ||
+			!strings.Contains(buf.String(), "maven-4.1.0.xsd") {
+			t.Errorf("Expected the 4.1.0 namespace and schema, but found:\n%s", buf.String())
+		}
+	})
+}
+
+func TestConvert(t *testing.T) {
+	t.Run("Should upgrade modules to subprojects", func(t *testing.T) {
+		m, err := pom.Read(bytes.NewReader(testPomFile))
+		if err != nil {
+			t.Fatalf("Expected no errors reading pom, but found: %s", err.Error())
+		}
+		m.Modules = &pom.Modules{Module: []string{"core"}}
+
+		c, issues, err := m.Convert(pom.ModelVersion410)
+		if err != nil || len(issues) != 0 {
+			t.Fatalf("Expected a clean upgrade, but found: %v %v", issues, err)
+		}
+		if c.ModelVersion != pom.ModelVersion410 || c.Modules != nil || c.Subprojects.Subproject[0] != "core" {
+			t.Errorf("Expected modules to become subprojects, but found: %+v %+v", c.Modules, c.Subprojects)
+		}
+		if m.Modules == nil || m.ModelVersion != pom.ModelVersion400 {
+			t.Errorf("Expected the original model to be unchanged, but found: %s %+v", m.ModelVersion, m.Modules)
+		}
+	})
+
+	t.Run("Should downgrade and report what cannot be represented", func(t *testing.T) {
+		m := readVersion410(t)
+
+		c, issues, err := m.Convert(pom.ModelVersion400)
+		if err != nil {
+			t.Fatalf("Expected no errors converting, but found: %s", err.Error())
+		}
+
+		if c.Root != "" || c.Subprojects != nil || c.Modules == nil || len(c.Modules.Module) != 2 {
+			t.Errorf("Expected subprojects to become modules, but found: %+v", c.Modules)
+		}
+		if c.Packaging != "pom" {
+			t.Errorf("Expected packaging pom, but found: %s", c.Packaging)
+		}
+		if scope := c.Dependencies.Dependency[0].Scope; scope != pom.ScopeProvided {
+			t.Errorf("Expected compile-only to become provided, but found: %s", scope)
+		}
+		if scope := c.Dependencies.Dependency[1].Scope; scope != pom.ScopeTest {
+			t.Errorf("Expected test-runtime to become test, but found: %s", scope)
+		}
+		if c.Build.Sources != nil || c.Build.SourceDirectory != "src/main/java" {
+			t.Errorf("Expected the main source directory src/main/java, but found: %q", c.Build.SourceDirectory)
+		}
+		if c.Build.Resources == nil || c.Build.Resources.Resource[0].TargetPath != "META-INF" {
+			t.Errorf("Expected the resources source to become a resource, but found: %+v", c.Build.Resources)
+		}
+		if c.Build.Plugins.Plugin[0].Executions.Execution[0].Priority != "" {
+			t.Errorf("Expected execution priority to be removed")
+		}
+		if c.Profiles.Profile[0].Activation.Condition != "" || c.Profiles.Profile[0].Modules.Module[0] != "native" {
+			t.Errorf("Expected the profile to be downgraded, but found: %+v", c.Profiles.Profile[0])
+		}
+
+		paths := map[string]bool{}
+		for _, issue := range issues {
+			paths[issue.Path] = true
+		}
+		for _, path := range []string{
+			"/project/@root",
+			"/project/packaging",
+			"/project/dependencies/dependency/scope",
+			"/project/build/sources",
+			"/project/build/plugins/plugin/executions/execution/priority",
+			"/project/profiles/profile/activation/condition",
+		} {
+			if !paths[path] {
+				t.Errorf("Expected an issue for %s, but found: %v", path, issues)
+			}
+		}
+
+		var buf bytes.Buffer
+		if err := c.Write(&buf, nil); err != nil {
+			t.Fatalf("Expected no errors writing pom, but found: %s", err.Error())
+		}
+		decodeStrict(t, buf.Bytes())
+		if !strings.Contains(buf.String(), "maven-4.0.0.xsd") {
+			t.Errorf("Expected the 4.0.0 schema, but found:\n%s", buf.String())
+		}
+	})
+
+	t.Run("Should reject an unsupported model version", func(t *testing.T) {
+		if _, _, err := pom.New().Convert("5.0.0"); err == nil {
+			t.Errorf("Expected an error converting to 5.0.0")
+		}
+	})
+}
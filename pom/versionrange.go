@@ -0,0 +1,410 @@
+package pom
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CompareVersions compares two artifact versions the way Maven orders them
+// and returns -1, 0 or +1. Versions are split into numbers and qualifiers at
+// dots, hyphens and transitions between digits and letters, so that
+// 1.0-alpha-1 < 1.0-beta < 1.0-rc-1 < 1.0-SNAPSHOT < 1.0 = 1.0.0 = 1.0-ga <
+// 1.0-sp-1 < 1.0.1. Unknown qualifiers sort after the known ones, in
+// alphabetical order.
+func CompareVersions(a, b string) int {
+	return parseVersion(a).compare(parseVersion(b))
+}
+
+// IsSnapshot reports whether version is a SNAPSHOT version, either
+// 1.0-SNAPSHOT or a timestamped one like 1.0-20240102.030405-6.
+func IsSnapshot(version string) bool {
+	if strings.HasSuffix(version, "-SNAPSHOT") {
+		return true
+	}
+
+	// <base>-<yyyyMMdd.HHmmss>-<buildNumber>
+	i := strings.LastIndexByte(version, '-')
+	if i < 0 || !isDigits(version[i+1:]) {
+		return false
+	}
+	j := strings.LastIndexByte(version[:i], '-')
+	if j < 0 {
+		return false
+	}
+	date, clock, ok := strings.Cut(version[j+1:i], ".")
+	return ok && len(date) == 8 && len(clock) == 6 && isDigits(date) && isDigits(clock)
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+type versionItemKind int
+
+const (
+	intItem versionItemKind = iota
+	stringItem
+	listItem
+)
+
+// versionItem is one part of a parsed version: a number, a qualifier, or the
+// list of items following a hyphen.
+type versionItem struct {
+	kind versionItemKind
+	// digits is the number of an intItem without leading zeros.
+	digits string
+	// value is the qualifier of a stringItem, with aliases replaced.
+	value string
+	items []*versionItem
+}
+
+// knownQualifiers in ascending order; the empty qualifier is a release.
+var knownQualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+
+var qualifierAliases = map[string]string{
+	"ga":      "",
+	"final":   "",
+	"release": "",
+	"cr":      "rc",
+}
+
+func parseVersion(version string) *versionItem {
+	version = strings.ToLower(version)
+
+	root := &versionItem{kind: listItem}
+	list := root
+	stack := []*versionItem{root}
+
+	isDigit := false
+	start := 0
+	newList := func() {
+		sub := &versionItem{kind: listItem}
+		list.items = append(list.items, sub)
+		list = sub
+		stack = append(stack, sub)
+	}
+
+	for i := 0; i < len(version); i++ {
+		c := version[i]
+		switch {
+		case c == '.' || c == '-':
+			if i == start {
+				list.items = append(list.items, &versionItem{kind: intItem})
+			} else {
+				list.items = append(list.items, parseVersionItem(isDigit, version[start:i], false))
+			}
+			start = i + 1
+			if c == '-' {
+				newList()
+			}
+		case c >= '0' && c <= '9':
+			if !isDigit && i > start {
+				list.items = append(list.items, parseVersionItem(false, version[start:i], true))
+				start = i
+				newList()
+			}
+			isDigit = true
+		default:
+			if isDigit && i > start {
+				list.items = append(list.items, parseVersionItem(true, version[start:i], false))
+				start = i
+				newList()
+			}
+			isDigit = false
+		}
+	}
+	if len(version) > start {
+		list.items = append(list.items, parseVersionItem(isDigit, version[start:], false))
+	}
+
+	for i := len(stack) - 1; i >= 0; i-- {
+		stack[i].normalize()
+	}
+
+	return root
+}
+
+func parseVersionItem(isDigit bool, s string, followedByDigit bool) *versionItem {
+	if isDigit {
+		return &versionItem{kind: intItem, digits: strings.TrimLeft(s, "0")}
+	}
+
+	if followedByDigit && len(s) == 1 {
+		switch s {
+		case "a":
+			s = "alpha"
+		case "b":
+			s = "beta"
+		case "m":
+			s = "milestone"
+		}
+	}
+	if alias, ok := qualifierAliases[s]; ok {
+		s = alias
+	}
+	return &versionItem{kind: stringItem, value: s}
+}
+
+// normalize removes trailing items that compare equal to nothing, so that
+// 1.0.0 equals 1 and 1-ga equals 1.
+func (v *versionItem) normalize() {
+	for i := len(v.items) - 1; i >= 0; i-- {
+		item := v.items[i]
+		if item.isNull() {
+			v.items = append(v.items[:i], v.items[i+1:]...)
+		} else if item.kind != listItem {
+			break
+		}
+	}
+}
+
+func (v *versionItem) isNull() bool {
+	switch v.kind {
+	case intItem:
+		return v.digits == ""
+	case stringItem:
+		return v.value == ""
+	default:
+		return len(v.items) == 0
+	}
+}
+
+func comparableQualifier(q string) string {
+	for i, known := range knownQualifiers {
+		if q == known {
+			return fmt.Sprint(i)
+		}
+	}
+	return fmt.Sprintf("%d-%s", len(knownQualifiers), q)
+}
+
+// compare compares v with other, where a nil other stands for the missing
+// item of a shorter version.
+func (v *versionItem) compare(other *versionItem) int {
+	switch v.kind {
+	case intItem:
+		if other == nil {
+			if v.digits == "" {
+				return 0
+			}
+			return 1
+		}
+		if other.kind != intItem {
+			return 1
+		}
+		if len(v.digits) != len(other.digits) {
+			return sign(len(v.digits) - len(other.digits))
+		}
+		return strings.Compare(v.digits, other.digits)
+
+	case stringItem:
+		if other == nil {
+			return strings.Compare(comparableQualifier(v.value), comparableQualifier(""))
+		}
+		if other.kind != stringItem {
+			return -1
+		}
+		return strings.Compare(comparableQualifier(v.value), comparableQualifier(other.value))
+
+	default:
+		if other == nil {
+			if len(v.items) == 0 {
+				return 0
+			}
+			return v.items[0].compare(nil)
+		}
+		switch other.kind {
+		case intItem:
+			return -1
+		case stringItem:
+			return 1
+		}
+		for i := 0; i < len(v.items) || i < len(other.items); i++ {
+			var result int
+			switch {
+			case i >= len(v.items):
+				result = -other.items[i].compare(nil)
+			case i >= len(other.items):
+				result = v.items[i].compare(nil)
+			default:
+				result = v.items[i].compare(other.items[i])
+			}
+			if result != 0 {
+				return result
+			}
+		}
+		return 0
+	}
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// VersionRange is a set of versions written in Maven's range syntax, such as
+// [1.0,2.0), [1.5] or (,1.0],[1.2,). A version without brackets is a soft
+// requirement that recommends that version but allows any other.
+type VersionRange struct {
+	// Recommended is the version of a soft requirement.
+	Recommended  string
+	Restrictions []Restriction
+}
+
+// Restriction is one interval of a VersionRange. An empty Lower or Upper
+// leaves that side unbounded.
+type Restriction struct {
+	Lower          string
+	LowerInclusive bool
+	Upper          string
+	UpperInclusive bool
+}
+
+// ParseVersionRange parses a version or version range specification.
+func ParseVersionRange(spec string) (*VersionRange, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, fmt.Errorf("pom: empty version range")
+	}
+
+	if spec[0] != '[' && spec[0] != '(' {
+		if strings.ContainsAny(spec, "[]()") {
+			return nil, fmt.Errorf("pom: invalid version range %q", spec)
+		}
+		return &VersionRange{Recommended: spec, Restrictions: []Restriction{{}}}, nil
+	}
+
+	r := &VersionRange{}
+	rest := spec
+	for rest != "" {
+		end := strings.IndexAny(rest, "])")
+		if end < 0 {
+			return nil, fmt.Errorf("pom: unbounded version range %q", spec)
+		}
+		restriction, err := parseRestriction(rest[:end+1])
+		if err != nil {
+			return nil, fmt.Errorf("pom: invalid version range %q: %w", spec, err)
+		}
+		r.Restrictions = append(r.Restrictions, restriction)
+
+		rest = strings.TrimSpace(rest[end+1:])
+		if strings.HasPrefix(rest, ",") {
+			rest = strings.TrimSpace(rest[1:])
+			if rest == "" {
+				return nil, fmt.Errorf("pom: invalid version range %q: trailing comma", spec)
+			}
+		} else if rest != "" {
+			return nil, fmt.Errorf("pom: invalid version range %q: ranges must be separated by commas", spec)
+		}
+	}
+
+	for i := 1; i < len(r.Restrictions); i++ {
+		prev, next := r.Restrictions[i-1], r.Restrictions[i]
+		if prev.Upper == "" || next.Lower == "" || CompareVersions(prev.Upper, next.Lower) > 0 {
+			return nil, fmt.Errorf("pom: invalid version range %q: ranges overlap", spec)
+		}
+	}
+
+	return r, nil
+}
+
+func parseRestriction(spec string) (Restriction, error) {
+	r := Restriction{
+		LowerInclusive: spec[0] == '[',
+		UpperInclusive: spec[len(spec)-1] == ']',
+	}
+	inner := strings.TrimSpace(spec[1 : len(spec)-1])
+
+	lower, upper, isRange := strings.Cut(inner, ",")
+	if !isRange {
+		if !r.LowerInclusive || !r.UpperInclusive || inner == "" {
+			return r, fmt.Errorf("single version must be surrounded by []")
+		}
+		r.Lower, r.Upper = inner, inner
+		return r, nil
+	}
+
+	r.Lower, r.Upper = strings.TrimSpace(lower), strings.TrimSpace(upper)
+	if strings.Contains(r.Upper, ",") {
+		return r, fmt.Errorf("too many commas in %s", spec)
+	}
+	if r.Lower != "" && r.Upper != "" && CompareVersions(r.Lower, r.Upper) > 0 {
+		return r, fmt.Errorf("lower bound %s is greater than upper bound %s", r.Lower, r.Upper)
+	}
+	return r, nil
+}
+
+// Contains reports whether version is within the range. A soft requirement
+// contains every version.
+func (r *VersionRange) Contains(version string) bool {
+	for _, restriction := range r.Restrictions {
+		if restriction.Contains(version) {
+			return true
+		}
+	}
+	return false
+}
+
+// Contains reports whether version is within the interval.
+func (r Restriction) Contains(version string) bool {
+	if r.Lower != "" {
+		c := CompareVersions(version, r.Lower)
+		if c < 0 || c == 0 && !r.LowerInclusive {
+			return false
+		}
+	}
+	if r.Upper != "" {
+		c := CompareVersions(version, r.Upper)
+		if c > 0 || c == 0 && !r.UpperInclusive {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *VersionRange) String() string {
+	if r.Recommended != "" {
+		return r.Recommended
+	}
+
+	parts := make([]string, len(r.Restrictions))
+	for i, restriction := range r.Restrictions {
+		parts[i] = restriction.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+func (r Restriction) String() string {
+	var b strings.Builder
+	if r.LowerInclusive {
+		b.WriteByte('[')
+	} else {
+		b.WriteByte('(')
+	}
+	if r.LowerInclusive && r.UpperInclusive && r.Lower == r.Upper && r.Lower != "" {
+		b.WriteString(r.Lower)
+	} else {
+		b.WriteString(r.Lower)
+		b.WriteByte(',')
+		b.WriteString(r.Upper)
+	}
+	if r.UpperInclusive {
+		b.WriteByte(']')
+	} else {
+		b.WriteByte(')')
+	}
+	return b.String()
+}
@@ -0,0 +1,103 @@
+package pom_test
+
+import (
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+func TestCompareVersions(t *testing.T) {
+	t.Run("Should order versions like Maven", func(t *testing.T) {
+		ordered := []string{
+			"1.0-alpha-1", "1.0-alpha2", "1.0-beta", "1.0-milestone-1", "1.0-rc-1",
+			"1.0-SNAPSHOT", "1.0", "1.0-sp-1", "1.0-xyz", "1.0.1", "1.1", "1.10", "2",
+		}
+		for i := 0; i+1 < len(ordered); i++ {
+			if pom.CompareVersions(ordered[i], ordered[i+1]) >= 0 || pom.CompareVersions(ordered[i+1], ordered[i]) <= 0 {
+				t.Errorf("Expected %s < %s", ordered[i], ordered[i+1])
+			}
+		}
+	})
+
+	t.Run("Should treat equivalent versions as equal", func(t *testing.T) {
+		for _, pair := range [][2]string{{"1", "1.0.0"}, {"1.0", "1-ga"}, {"1.0-final", "1.0.RELEASE"}, {"1.0-cr1", "1.0-rc-1"}, {"1.0-a1", "1.0-alpha-1"}} {
+			if pom.CompareVersions(pair[0], pair[1]) != 0 {
+				t.Errorf("Expected %s = %s", pair[0], pair[1])
+			}
+		}
+	})
+
+	t.Run("Should recognize snapshots", func(t *testing.T) {
+		for version, expected := range map[string]bool{"1.0-SNAPSHOT": true, "1.0-20240102.030405-6": true, "1.0": false, "1.0-rc-1": false} {
+			if pom.IsSnapshot(version) != expected {
+				t.Errorf("Expected IsSnapshot(%s) to be %v", version, expected)
+			}
+		}
+	})
+}
+
+func TestVersionRange(t *testing.T) {
+	t.Run("Should contain the versions in range", func(t *testing.T) {
+		for spec, cases := range map[string]map[string]bool{
+			"[1.0,2.0)":     {"1.0": true, "1.5": true, "2.0": false, "0.9": false},
+			"(,1.0],[1.2,)": {"0.1": true, "1.0": true, "1.1": false, "1.2": true, "9": true},
+			"[1.5]":         {"1.5": true, "1.5.0": true, "1.6": false},
+			"1.5":           {"1.0": true, "3": true},
+		} {
+			r, err := pom.ParseVersionRange(spec)
+			if err != nil {
+				t.Errorf("Expected no errors parsing %s, but found: %s", spec, err.Error())
+				continue
+			}
+			for version, expected := range cases {
+				if r.Contains(version) != expected {
+					t.Errorf("Expected %s contains %s to be %v", spec, version, expected)
+				}
+			}
+			if r.String() != spec {
+				t.Errorf("Expected %s to format as itself, but found: %s", spec, r.String())
+			}
+		}
+	})
+
+	t.Run("Should reject invalid ranges", func(t *testing.T) {
+		for _, spec := range []string{"", "[1.0", "(1.0)", "[2.0,1.0]", "[1.0,2.0],[1.5,3.0]", "[1.0,2.0] [3.0,)", "1.0]"} {
+			if _, err := pom.ParseVersionRange(spec); err == nil {
+				t.Errorf("Expected an error parsing %q", spec)
+			}
+		}
+	})
+}
+
+func TestIncrementVersion(t *testing.T) {
+	t.Run("Should increment a part and reset the following ones", func(t *testing.T) {
+		for _, c := range []struct {
+			version  string
+			part     pom.VersionPart
+			expected string
+		}{
+			{"1.2.3", pom.Major, "2.0.0"},
+			{"1.2.3", pom.Minor, "1.3.0"},
+			{"1.2.3-SNAPSHOT", pom.Incremental, "1.2.4-SNAPSHOT"},
+			{"1.2", pom.Incremental, "1.2.1"},
+			{"1.2.3-rc-1", pom.Minor, "1.3.0"},
+			{"5", pom.Minor, "5.1"},
+		} {
+			if next, err := pom.IncrementVersion(c.version, c.part); err != nil || next != c.expected {
+				t.Errorf("Expected %s after %s, but found: %s %v", c.expected, c.version, next, err)
+			}
+		}
+	})
+
+	t.Run("Should reject versions without numbers", func(t *testing.T) {
+		if _, err := pom.IncrementVersion("LATEST", pom.Minor); err == nil {
+			t.Errorf("Expected an error")
+		}
+	})
+
+	t.Run("Should drop the SNAPSHOT suffix of releases", func(t *testing.T) {
+		if v := pom.ReleaseVersion("1.2.3-SNAPSHOT"); v != "1.2.3" {
+			t.Errorf("Expected 1.2.3, but found: %s", v)
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package pom
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+)
+
+// Walk calls fn for m and each element within it, in document order, with
+// the path of the element in the form of the Path of a Change, like
+// /project/dependencies/dependency[org.slf4j:slf4j-api:jar:]. element is a
+// pointer into m, like *Dependency or *Plugin, so fn may modify it.
+// Configurations and unknown elements are passed as a *DOM and not descended
+// into.
+func (m *Model) Walk(fn func(path string, element any)) {
+	walk(diffContext{path: "/project", element: "project"}, reflect.ValueOf(m), fn)
+}
+
+func walk(ctx diffContext, v reflect.Value, fn func(path string, element any)) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() || v.Elem().Kind() != reflect.Struct {
+			return
+		}
+		fn(ctx.path, v.Interface())
+		if _, ok := v.Interface().(*DOM); !ok {
+			walkFields(ctx, v.Elem(), fn)
+		}
+	case reflect.Slice:
+		elem := v.Type().Elem()
+		if elem.Kind() != reflect.Struct || elem == reflect.TypeOf(xml.Attr{}) {
+			return
+		}
+
+		name := ctx.path[strings.LastIndexByte(ctx.path, '/')+1:]
+		keys, items := diffItems(v)
+		for _, key := range keys {
+			item := items[key]
+			walk(ctx.item(itemName(name, item), key, item), item.Addr(), fn)
+		}
+	}
+}
+
+func walkFields(ctx diffContext, v reflect.Value, fn func(path string, element any)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if isEmbedded(f) {
+			walkFields(ctx, v.Field(i), fn)
+			continue
+		}
+		name, ok := fieldName(f)
+		if !ok {
+			continue
+		}
+		walk(ctx.child(name, true), v.Field(i), fn)
+	}
+}
@@ -0,0 +1,29 @@
+package pom_test
+
+import (
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+func TestWalk(t *testing.T) {
+	t.Run("Should visit elements with their paths", func(t *testing.T) {
+		before, _ := readDiffPair(t)
+
+		paths := make(map[string]any)
+		before.Walk(func(path string, element any) {
+			paths[path] = element
+		})
+
+		if _, ok := paths["/project"].(*pom.Model); !ok {
+			t.Errorf("Expected the model at /project, but found: %T", paths["/project"])
+		}
+		d, ok := paths["/project/profiles/profile[release]/dependencies/dependency[org.example:signing:jar:]"].(*pom.Dependency)
+		if !ok || d != &before.Profiles.Profile[0].Dependencies.Dependency[0] {
+			t.Errorf("Expected a pointer to the dependency of the profile, but found: %v", d)
+		}
+		if _, ok := paths["/project/build/plugins/plugin[org.apache.maven.plugins:maven-surefire-plugin]/executions/execution[integration]"].(*pom.Execution); !ok {
+			t.Errorf("Expected the execution of the plugin, but found: %v", paths)
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package pom
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	xsiNamespace    = "http://www.w3.org/2001/XMLSchema-instance"
+	pomNamespace400 = "http://maven.apache.org/POM/4.0.0"
+	pomSchema400    = "https://maven.apache.org/xsd/maven-4.0.0.xsd"
+)
+
+// WriteOptions controls how a Model is encoded by Write.
+type WriteOptions struct {
+	// Indent is repeated once per nesting level. An empty Indent writes the
+	// whole document on a single line.
+	Indent string
+	// XMLDeclaration writes <?xml version="1.0" encoding="UTF-8"?> before the
+	// document element.
+	XMLDeclaration bool
+	// SchemaHeader writes the standard xmlns, xmlns:xsi and xsi:schemaLocation
+	// attributes on <project>.
+	SchemaHeader bool
+	// Encoding is the character encoding of the output. When empty the Model's
+	// own Encoding is used, so documents are written back the way they were
+	// read. Documents in an encoding other than UTF-8 always get a declaration.
+	Encoding string
+}
+
+// DefaultWriteOptions are used by Write when no options are given.
+var DefaultWriteOptions = WriteOptions{
+	Indent:         "  ",
+	XMLDeclaration: true,
+	SchemaHeader:   true,
+}
+
+// Write encodes m as a POM document to w. A nil opts uses DefaultWriteOptions.
+//
+// Characters that the output encoding cannot represent are written as
+// numeric character references, except in comments, where they would change
+// the text: Write returns an *EncodingError for those.
+func (m *Model) Write(w io.Writer, opts *WriteOptions) error {
+	if opts == nil {
+		opts = &DefaultWriteOptions
+	}
+
+	encoding := opts.Encoding
+	if encoding == "" {
+		encoding = m.Encoding
+	}
+	if isUTF8(encoding) {
+		return m.write(w, opts, opts.XMLDeclaration)
+	}
+
+	cs, ok := lookupCharset(encoding)
+	if !ok {
+		return &EncodingError{Encoding: encoding}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<?xml version=\"1.0\" encoding=\"%s\"?>\n", encoding)
+	if err := m.write(&buf, opts, false); err != nil {
+		return err
+	}
+
+	out, err := cs.encode(buf.Bytes())
+	if err != nil {
+		if encErr, ok := err.(*EncodingError); ok {
+			encErr.Encoding = encoding
+		}
+		return err
+	}
+
+	if strings.EqualFold(encoding, "UTF-16") {
+		if _, err := w.Write(bomUTF16LE); err != nil {
+			return err
+		}
+	}
+
+	_, err = w.Write(out)
+	return err
+}
+
+func (m *Model) write(w io.Writer, opts *WriteOptions, declaration bool) error {
+	if declaration {
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+	}
+
+	e := xml.NewEncoder(w)
+	e.Indent("", opts.Indent)
+
+	if err := e.EncodeElement(m, m.rootElement(opts)); err != nil {
+		return err
+	}
+	if err := e.Close(); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func (m *Model) rootElement(opts *WriteOptions) xml.StartElement {
+	start := xml.StartElement{Name: xml.Name{Local: "project"}}
+
+	if opts.SchemaHeader {
+		namespace, schema := pomNamespace400, pomSchema400
+		if m.ModelVersion == ModelVersion410 {
+			namespace, schema = pomNamespace410, pomSchema410
+		}
+
+		start.Attr = []xml.Attr{
+			{Name: xml.Name{Local: "xmlns"}, Value: namespace},
+			{Name: xml.Name{Local: "xmlns:xsi"}, Value: xsiNamespace},
+			{Name: xml.Name{Local: "xsi:schemaLocation"}, Value: namespace + " " + schema},
+		}
+	}
+
+	return start
+}
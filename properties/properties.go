@@ -0,0 +1,486 @@
+// Package properties reads and writes Java .properties files, like
+// pom.properties, maven-wrapper.properties or application.properties, in the
+// format of java.util.Properties.
+//
+// Properties keep the text of the file: writing them back after changes only
+// rewrites the entries that changed, so comments, blank lines, separators,
+// escapes and line continuations of the others are preserved.
+package properties
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/obscurelyme/encoding/pom"
+)
+
+// Encoding is the character encoding of a .properties file.
+type Encoding int
+
+const (
+	// ISO88591 is the encoding of Properties.load(InputStream). Characters
+	// outside of it are written as \uXXXX escapes.
+	ISO88591 Encoding = iota
+	// UTF8 is the encoding of resource bundles since Java 9 and of
+	// Properties.load(Reader) with a UTF-8 reader.
+	UTF8
+)
+
+func (e Encoding) String() string {
+	if e == UTF8 {
+		return "UTF-8"
+	}
+	return "ISO-8859-1"
+}
+
+// SyntaxError is returned for a malformed \uXXXX escape, the only error of
+// the format.
+type SyntaxError struct {
+	Line int
+	Msg  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("properties: line %d: %s", e.Line, e.Msg)
+}
+
+// entry is a logical line of a file, or a comment or blank line.
+type entry struct {
+	// text is the text of the entry in the file, with its line
+	// continuations and line terminator.
+	text string
+	// comment is whether the entry is a comment or blank line, without key.
+	comment bool
+	key     string
+	value   string
+	// indent, rawKey and separator are the text of the entry before its
+	// key, of its key and between its key and value, reused when its value
+	// changes.
+	indent    string
+	rawKey    string
+	separator string
+}
+
+// Properties is the content of a .properties file.
+type Properties struct {
+	// Encoding is the encoding of the file, used by Bytes.
+	Encoding Encoding
+	entries  []*entry
+	// newline is the line terminator of the entries added.
+	newline string
+}
+
+// New returns empty Properties in ISO-8859-1.
+func New() *Properties {
+	return &Properties{newline: "\n"}
+}
+
+// Read decodes the properties read from r. The encoding is UTF-8 for files
+// with non-ASCII text that is valid UTF-8, like PropertyResourceBundle
+// assumes, and ISO-8859-1 otherwise.
+func Read(r io.Reader) (*Properties, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	enc := ISO88591
+	if !isASCII(data) && utf8.Valid(data) {
+		enc = UTF8
+	}
+	return Parse(data, enc)
+}
+
+// ReadFile decodes the properties file at path, like Read.
+func ReadFile(path string) (*Properties, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	p, err := Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return p, nil
+}
+
+func isASCII(data []byte) bool {
+	for _, b := range data {
+		if b >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// Parse decodes the properties of data in the encoding enc.
+func Parse(data []byte, enc Encoding) (*Properties, error) {
+	text := string(data)
+	if enc == ISO88591 {
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		text = string(runes)
+	}
+
+	p := &Properties{Encoding: enc, newline: "\n"}
+	if i := strings.IndexAny(text, "\r\n"); i >= 0 {
+		p.newline = text[i : i+1]
+		if strings.HasPrefix(text[i:], "\r\n") {
+			p.newline = "\r\n"
+		}
+	}
+
+	line := 0
+	for text != "" {
+		first, rest := naturalLine(text)
+		line++
+		start := line
+
+		e := &entry{text: first}
+		content := strings.TrimLeft(trimTerminator(first), " \t\f")
+		if content == "" || content[0] == '#' || content[0] == '!' {
+			e.comment = true
+		} else {
+			// A logical line goes on after the natural lines ending with an
+			// odd number of backslashes, without their leading whitespace.
+			logical := content
+			for continues(logical) && rest != "" {
+				first, rest = naturalLine(rest)
+				line++
+				e.text += first
+				logical = logical[:len(logical)-1] + strings.TrimLeft(trimTerminator(first), " \t\f")
+			}
+			if continues(logical) {
+				// A backslash at the end of the file is dropped.
+				logical = logical[:len(logical)-1]
+			}
+			indent := e.text[:len(e.text)-len(strings.TrimLeft(e.text, " \t\f"))]
+			if err := e.parse(logical, indent); err != nil {
+				return nil, &SyntaxError{Line: start, Msg: err.Error()}
+			}
+		}
+		p.entries = append(p.entries, e)
+		text = rest
+	}
+	return p, nil
+}
+
+// naturalLine splits the first line of text, with its terminator, from the
+// rest.
+func naturalLine(text string) (string, string) {
+	i := strings.IndexAny(text, "\r\n")
+	if i < 0 {
+		return text, ""
+	}
+	if strings.HasPrefix(text[i:], "\r\n") {
+		return text[:i+2], text[i+2:]
+	}
+	return text[:i+1], text[i+1:]
+}
+
+func trimTerminator(line string) string {
+	return strings.TrimRight(line, "\r\n")
+}
+
+// continues reports whether line ends with an odd number of backslashes.
+func continues(line string) bool {
+	n := len(line) - len(strings.TrimRight(line, `\`))
+	return n%2 == 1
+}
+
+// parse sets the key and value of e from its logical line.
+func (e *entry) parse(logical, indent string) error {
+	end := 0
+	for end < len(logical) {
+		c := logical[end]
+		if c == '\\' {
+			end += 2
+			continue
+		}
+		if c == '=' || c == ':' || c == ' ' || c == '\t' || c == '\f' {
+			break
+		}
+		end++
+	}
+	end = min(end, len(logical))
+
+	valueStart := end
+	for valueStart < len(logical) && strings.IndexByte(" \t\f", logical[valueStart]) >= 0 {
+		valueStart++
+	}
+	if valueStart < len(logical) && (logical[valueStart] == '=' || logical[valueStart] == ':') {
+		valueStart++
+		for valueStart < len(logical) && strings.IndexByte(" \t\f", logical[valueStart]) >= 0 {
+			valueStart++
+		}
+	}
+
+	var err error
+	e.indent, e.rawKey, e.separator = indent, logical[:end], logical[end:valueStart]
+	if e.key, err = unescape(e.rawKey); err != nil {
+		return err
+	}
+	e.value, err = unescape(logical[valueStart:])
+	return err
+}
+
+// unescape replaces the escapes of s.
+func unescape(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+
+	var b strings.Builder
+	var units []uint16
+	flush := func() {
+		b.WriteString(string(utf16.Decode(units)))
+		units = units[:0]
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			flush()
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch c := s[i]; c {
+		case 'u':
+			if i+5 > len(s) {
+				return "", fmt.Errorf(`malformed \uxxxx encoding`)
+			}
+			u, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil || strings.ContainsAny(s[i+1:i+5], "+-_") {
+				return "", fmt.Errorf(`malformed \uxxxx encoding`)
+			}
+			// Characters outside of the BMP are escaped as surrogate pairs.
+			units = append(units, uint16(u))
+			i += 4
+			continue
+		case 't':
+			flush()
+			b.WriteByte('\t')
+		case 'n':
+			flush()
+			b.WriteByte('\n')
+		case 'r':
+			flush()
+			b.WriteByte('\r')
+		case 'f':
+			flush()
+			b.WriteByte('\f')
+		default:
+			flush()
+			b.WriteByte(c)
+		}
+	}
+	flush()
+	return b.String(), nil
+}
+
+// Len returns the number of keys of p.
+func (p *Properties) Len() int {
+	return len(p.Keys())
+}
+
+// Keys returns the keys of p in the order of the file, a key defined more
+// than once at its first place.
+func (p *Properties) Keys() []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, e := range p.entries {
+		if !e.comment && !seen[e.key] {
+			keys = append(keys, e.key)
+			seen[e.key] = true
+		}
+	}
+	return keys
+}
+
+// Get returns the value of key and whether p has it. The last definition of
+// a key wins, like in Java.
+func (p *Properties) Get(key string) (string, bool) {
+	if e := p.last(key); e != nil {
+		return e.value, true
+	}
+	return "", false
+}
+
+func (p *Properties) last(key string) *entry {
+	for i := len(p.entries) - 1; i >= 0; i-- {
+		if e := p.entries[i]; !e.comment && e.key == key {
+			return e
+		}
+	}
+	return nil
+}
+
+// Set sets the value of key, in place if p has it, or else in a new entry at
+// the end.
+func (p *Properties) Set(key, value string) {
+	e := p.last(key)
+	if e == nil {
+		p.ensureNewline()
+		e = &entry{rawKey: escape(key, true), separator: "=", text: p.newline}
+		p.entries = append(p.entries, e)
+	} else if e.value == value {
+		return
+	}
+
+	newline := e.text[len(strings.TrimRight(e.text, "\r\n")):]
+	e.key, e.value = key, value
+	e.text = e.indent + e.rawKey + e.separator + escape(value, false) + newline
+}
+
+// Delete removes key from p, with every definition of it.
+func (p *Properties) Delete(key string) {
+	kept := p.entries[:0]
+	for _, e := range p.entries {
+		if e.comment || e.key != key {
+			kept = append(kept, e)
+		}
+	}
+	p.entries = kept
+}
+
+// AddComment adds a # comment line at the end of p. Lines of text are
+// added as separate comments.
+func (p *Properties) AddComment(text string) {
+	p.ensureNewline()
+	for _, line := range strings.Split(text, "\n") {
+		p.entries = append(p.entries, &entry{text: "#" + escapeComment(line) + p.newline, comment: true})
+	}
+}
+
+// ensureNewline terminates the last entry, so that another can follow it.
+func (p *Properties) ensureNewline() {
+	if p.newline == "" {
+		p.newline = "\n"
+	}
+	if n := len(p.entries); n > 0 {
+		last := p.entries[n-1]
+		if !strings.HasSuffix(last.text, "\n") && !strings.HasSuffix(last.text, "\r") {
+			last.text += p.newline
+		}
+	}
+}
+
+// Map returns the keys and values of p.
+func (p *Properties) Map() map[string]string {
+	m := make(map[string]string)
+	for _, e := range p.entries {
+		if !e.comment {
+			m[e.key] = e.value
+		}
+	}
+	return m
+}
+
+// escape escapes s for a key, or a value, like Properties.store. Characters
+// outside of ISO-8859-1 are kept and left to Bytes.
+func escape(s string, key bool) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch r {
+		case ' ':
+			if key || i == 0 {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(' ')
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '=', ':', '#', '!', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		default:
+			if r < 0x20 || r == 0x7F {
+				writeUnicodeEscape(&b, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	return b.String()
+}
+
+func escapeComment(s string) string {
+	return strings.ReplaceAll(s, "\r", "")
+}
+
+func writeUnicodeEscape(b *strings.Builder, r rune) {
+	if r > 0xFFFF {
+		r1, r2 := utf16.EncodeRune(r)
+		writeUnicodeEscape(b, r1)
+		writeUnicodeEscape(b, r2)
+		return
+	}
+	fmt.Fprintf(b, `\u%04X`, r)
+}
+
+// Bytes returns the file of p in its Encoding. In ISO-8859-1, characters
+// outside of it are written as \uXXXX escapes, which only occur in the
+// values set: the text read is kept as it is.
+func (p *Properties) Bytes() []byte {
+	var text strings.Builder
+	for _, e := range p.entries {
+		text.WriteString(e.text)
+	}
+	if p.Encoding == UTF8 {
+		return []byte(text.String())
+	}
+
+	var b bytes.Buffer
+	var escaped strings.Builder
+	for _, r := range text.String() {
+		if r <= 0xFF {
+			b.WriteByte(byte(r))
+			continue
+		}
+		escaped.Reset()
+		writeUnicodeEscape(&escaped, r)
+		b.WriteString(escaped.String())
+	}
+	return b.Bytes()
+}
+
+// WriteTo writes the file of p to w, like Bytes.
+func (p *Properties) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(p.Bytes())
+	return int64(n), err
+}
+
+// FromPOM returns the properties of a POM, in the order of its keys.
+func FromPOM(props *pom.Properties) *Properties {
+	p := New()
+	if props == nil {
+		return p
+	}
+	for _, key := range props.Keys() {
+		p.Set(key, props.Fields[key])
+	}
+	return p
+}
+
+// POM returns the properties of p for a POM, in the order of p.
+func (p *Properties) POM() *pom.Properties {
+	props := &pom.Properties{}
+	for _, key := range p.Keys() {
+		value, _ := p.Get(key)
+		props.Set(key, value)
+	}
+	return props
+}
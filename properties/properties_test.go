@@ -0,0 +1,150 @@
+package properties_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/obscurelyme/encoding/pom"
+	"github.com/obscurelyme/encoding/properties"
+)
+
+func readFixture(t *testing.T) (*properties.Properties, []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", "application.properties")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := properties.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected no errors reading properties, but found: %s", err.Error())
+	}
+	return p, data
+}
+
+func TestRead(t *testing.T) {
+	p, data := readFixture(t)
+
+	t.Run("Should decode the format of java.util.Properties", func(t *testing.T) {
+		expected := map[string]string{
+			"server.port":   "8080",
+			"server.name":   "webserver",
+			"greeting text": "Hello World\t!",
+			"unicode":       "café é 😀",
+			"indented":      "value  ",
+			"empty":         "",
+			"trailing":      "last",
+		}
+		for key, value := range expected {
+			if found, ok := p.Get(key); !ok || found != value {
+				t.Errorf("Expected %s=%q, but found: %q %v", key, value, found, ok)
+			}
+		}
+		keys := "server.port server.name greeting text unicode indented empty trailing"
+		if found := strings.Join(p.Keys(), " "); found != keys || p.Len() != 7 {
+			t.Errorf("Expected the keys in order %s, but found: %s", keys, found)
+		}
+	})
+
+	t.Run("Should detect ISO-8859-1", func(t *testing.T) {
+		if p.Encoding != properties.ISO88591 {
+			t.Errorf("Expected ISO-8859-1, but found: %s", p.Encoding)
+		}
+		utf, err := properties.Read(strings.NewReader("name=café\n"))
+		if err != nil || utf.Encoding != properties.UTF8 {
+			t.Fatalf("Expected UTF-8, but found: %v %v", utf, err)
+		}
+		if value, _ := utf.Get("name"); value != "café" {
+			t.Errorf("Expected café, but found: %s", value)
+		}
+	})
+
+	t.Run("Should round trip unchanged", func(t *testing.T) {
+		if !bytes.Equal(p.Bytes(), data) {
+			t.Errorf("Expected:\n%q\nbut found:\n%q", data, p.Bytes())
+		}
+	})
+
+	t.Run("Should let the last definition win", func(t *testing.T) {
+		p, _ := properties.Read(strings.NewReader("a=1\nb=2\na=3\n"))
+		if value, _ := p.Get("a"); value != "3" || strings.Join(p.Keys(), " ") != "a b" {
+			t.Errorf("Expected a=3, but found: %s %v", value, p.Keys())
+		}
+	})
+
+	t.Run("Should reject malformed escapes", func(t *testing.T) {
+		var syntaxErr *properties.SyntaxError
+		_, err := properties.Read(strings.NewReader("a=1\nb=\\u12G4\n"))
+		if !errors.As(err, &syntaxErr) || syntaxErr.Line != 2 {
+			t.Errorf("Expected a syntax error on line 2, but found: %v", err)
+		}
+	})
+}
+
+func TestWrite(t *testing.T) {
+	t.Run("Should only rewrite the entries that change", func(t *testing.T) {
+		p, err := properties.Read(strings.NewReader("# settings\r\nport = 80\r\nname:web\\\r\n  server\r\nhost=localhost"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.Set("port", "8080")
+		p.Set("name", "web server")
+		p.Set("url", "http://localhost:8080/#home")
+		p.Delete("host")
+		p.AddComment("added")
+
+		expected := "# settings\r\nport = 8080\r\nname:web server\r\nurl=http\\://localhost\\:8080/\\#home\r\n#added\r\n"
+		if found := string(p.Bytes()); found != expected {
+			t.Errorf("Expected:\n%q\nbut found:\n%q", expected, found)
+		}
+	})
+
+	t.Run("Should escape keys and values", func(t *testing.T) {
+		p := properties.New()
+		p.Set("a key", " leading\tand\nnewline")
+		p.Set("unicode", "é😀")
+
+		expected := "a\\ key=\\ leading\\tand\\nnewline\nunicode=\xe9\\uD83D\\uDE00\n"
+		if found := string(p.Bytes()); found != expected {
+			t.Errorf("Expected:\n%q\nbut found:\n%q", expected, found)
+		}
+
+		read, err := properties.Parse(p.Bytes(), properties.ISO88591)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, key := range p.Keys() {
+			want, _ := p.Get(key)
+			if got, _ := read.Get(key); got != want {
+				t.Errorf("Expected %s to read back as %q, but found: %q", key, want, got)
+			}
+		}
+
+		p.Encoding = properties.UTF8
+		if found := string(p.Bytes()); !strings.HasSuffix(found, "unicode=é😀\n") {
+			t.Errorf("Expected UTF-8 text, but found: %q", found)
+		}
+	})
+}
+
+func TestPOM(t *testing.T) {
+	props := &pom.Properties{}
+	props.Set("java.version", "17")
+	props.Set("app.name", "demo")
+
+	p := properties.FromPOM(props)
+	if string(p.Bytes()) != "java.version=17\napp.name=demo\n" {
+		t.Errorf("Expected the properties of the POM in order, but found: %q", p.Bytes())
+	}
+
+	p.Set("extra", "x")
+	back := p.POM()
+	if strings.Join(back.Keys(), " ") != "java.version app.name extra" || back.Fields["extra"] != "x" {
+		t.Errorf("Expected the properties back in order, but found: %v", back.Keys())
+	}
+}